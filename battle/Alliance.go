@@ -0,0 +1,183 @@
+package battle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Trigger represents a scripted battle trigger
+type Trigger struct {
+	// Delay fires the trigger the given time after the battle began
+	Delay time.Duration
+
+	// Condition fires the trigger as soon as it returns true
+	// for a logged event. A trigger defining both a delay and a condition
+	// fires at whichever comes first
+	Condition func(event Event) bool
+}
+
+// Verify returns an error if the trigger is invalid
+func (t *Trigger) Verify() error {
+	if t.Delay < 0 {
+		return errors.Errorf("invalid delay: %s", t.Delay)
+	}
+	if t.Delay == 0 && t.Condition == nil {
+		return errors.New("neither a delay nor a condition defined")
+	}
+	return nil
+}
+
+// allianceBreak represents the state of a scripted alliance breakup
+type allianceBreak struct {
+	faction string
+	trigger Trigger
+	fired   bool
+	timer   *DynamicTicker
+}
+
+// Team returns the name of the team the faction currently belongs to
+func (b *Battle) Team(factionName string) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.teams[factionName]
+}
+
+// hostile returns true if the soldiers of the faction attack
+// the soldiers of the other faction. Neutral factions only fight
+// the factions attacking neutrals (see Faction.AttackNeutrals)
+// and the factions that attacked them.
+// Expects the battle lock to be locked
+func (b *Battle) hostile(factionName, otherFactionName string) bool {
+	switch {
+	case factionName == otherFactionName:
+		return false
+	case b.neutral[factionName]:
+		return b.provoked[factionName][otherFactionName]
+	case b.neutral[otherFactionName]:
+		return b.raiders[factionName] ||
+			b.provoked[otherFactionName][factionName]
+	}
+	return b.teams[factionName] != b.teams[otherFactionName]
+}
+
+// allied returns true if the soldiers of the faction heal
+// the soldiers of the other faction.
+// Expects the battle lock to be locked
+func (b *Battle) allied(factionName, otherFactionName string) bool {
+	if factionName == otherFactionName {
+		return true
+	}
+	return !b.neutral[factionName] && !b.neutral[otherFactionName] &&
+		b.teams[factionName] == b.teams[otherFactionName]
+}
+
+// provoke makes the neutral faction of the attacked soldier
+// hostile toward the faction of the attacker
+func (b *Battle) provoke(attacker, attacked Soldier) {
+	factionName := attacked.ID().Faction
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.neutral[factionName] {
+		return
+	}
+	if b.provoked[factionName] == nil {
+		b.provoked[factionName] = make(map[string]bool)
+	}
+	b.provoked[factionName][attacker.ID().Faction] = true
+}
+
+// breakAlliance makes the faction leave its team
+func (b *Battle) breakAlliance(ab *allianceBreak) error {
+	b.lock.Lock()
+	if ab.fired {
+		b.lock.Unlock()
+		return nil
+	}
+	ab.fired = true
+	team := b.teams[ab.faction]
+	b.teams[ab.faction] = ab.faction
+	b.decideIfOver()
+	b.lock.Unlock()
+
+	if team == ab.faction {
+		// The faction wasn't allied
+		return nil
+	}
+	return b.log().PushEvent(EventAllianceBroken{
+		Faction: ab.faction,
+		Team:    team,
+	})
+}
+
+// checkTriggers fires the alliance breakups whose condition
+// is met by the logged event
+func (b *Battle) checkTriggers(event Event) error {
+	for _, ab := range b.breaks {
+		if ab.trigger.Condition == nil {
+			continue
+		}
+		b.lock.Lock()
+		fired := ab.fired
+		b.lock.Unlock()
+		if fired || !ab.trigger.Condition(event) {
+			continue
+		}
+		if err := b.breakAlliance(ab); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// after runs fn on the next tick of the given ticker unless the battle
+// ends before. Sequential battles run fn in the order of the schedule
+func (b *Battle) after(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	tk *DynamicTicker,
+	fn func() error,
+) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer b.releaseTicker(tk)
+		select {
+		case <-ctx.Done():
+			tk.Reset(0)
+		case <-tk.C():
+			// Stop the ticker before acknowledging the tick
+			tk.Reset(0)
+			var err error
+			b.act(func() { err = fn() })
+			if err != nil {
+				panic(errors.Wrap(err, "unexpected scheduled action err"))
+			}
+			tk.Ack()
+		}
+	}()
+}
+
+// scheduleAllianceBreaks schedules the alliance breakups defining a delay
+func (b *Battle) scheduleAllianceBreaks(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+) {
+	for _, ab := range b.breaks {
+		if ab.trigger.Delay <= 0 {
+			continue
+		}
+		ab, tk := ab, b.newTicker()
+		b.lock.Lock()
+		ab.timer = tk
+		b.lock.Unlock()
+		tk.Reset(ab.trigger.Delay)
+		b.after(ctx, wg, tk, func() error {
+			return b.breakAlliance(ab)
+		})
+	}
+}
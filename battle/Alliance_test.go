@@ -0,0 +1,186 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAlliances runs battles between allied and neutral factions
+func TestAlliances(t *testing.T) {
+	withTeam := func(faction Faction, team string) Faction {
+		faction.Team = team
+		return faction
+	}
+	neutral := Faction{
+		Name:              "N",
+		ArmySize:          5,
+		SoldierAttributes: testAttributes(50),
+		Neutral:           true,
+	}
+	for _, tc := range []struct {
+		name     string
+		factions func() []Faction
+		winners  int
+		raided   bool
+	}{
+		{
+			name: "neutral faction stays out",
+			factions: func() []Faction {
+				return append(testFactions(5, 50), neutral)
+			},
+			winners: 1,
+		},
+		{
+			name: "neutral faction fights back",
+			factions: func() []Faction {
+				factions := testFactions(5, 50)
+				factions[0].AttackNeutrals = true
+				return append(factions, neutral)
+			},
+			winners: 1,
+			raided:  true,
+		},
+		{
+			name: "allies win together",
+			factions: func() []Faction {
+				factions := testFactions(5, 50)
+				return []Faction{
+					withTeam(factions[0], "North"),
+					withTeam(factions[1], "North"),
+					{
+						Name:              "C",
+						ArmySize:          5,
+						SoldierAttributes: testAttributes(50),
+					},
+				}
+			},
+			winners: 2,
+		},
+		{
+			name: "delayed breakup without allies",
+			factions: func() []Faction {
+				// The breakup can't split the team and mustn't
+				// keep the battle going
+				factions := testFactions(5, 50)
+				factions[0].BreakAlliance = &Trigger{Delay: time.Hour}
+				return factions
+			},
+			winners: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			btl := newTestBattle(
+				t,
+				Config{BaseActionDelay: time.Millisecond, Seed: 1},
+				tc.factions()...,
+			)
+			result := awaitResult(t, runAsync(context.Background(), btl))
+			if result.Outcome != OutcomeVictory {
+				t.Fatalf("unexpected outcome: %s", result.Outcome)
+			}
+			if len(result.Winners) != tc.winners {
+				t.Errorf("unexpected winners: %v", result.Winners)
+			}
+
+			// Neutral soldiers only fight back against their raiders
+			attacked, retaliated := false, false
+			for _, entry := range btl.Statistics().Log() {
+				attacker, target := attackOf(entry.Event)
+				if attacker == nil {
+					continue
+				}
+				switch neutral.Name {
+				case target.ID().Faction:
+					attacked = true
+				case attacker.ID().Faction:
+					retaliated = true
+					if faction := target.ID().Faction; faction != "A" {
+						t.Errorf("neutral soldier attacked faction %s", faction)
+					}
+				}
+			}
+			switch {
+			case attacked != tc.raided:
+				t.Errorf("neutral faction attacked: %t", attacked)
+			case retaliated != tc.raided:
+				t.Errorf("neutral faction fought back: %t", retaliated)
+			}
+		})
+	}
+}
+
+// TestNeutralRaiders makes sure neutral factions can't attack
+// other neutral factions unprovoked
+func TestNeutralRaiders(t *testing.T) {
+	factions := append(testFactions(5, 50), Faction{
+		Name:              "N",
+		ArmySize:          5,
+		SoldierAttributes: testAttributes(50),
+		Neutral:           true,
+		AttackNeutrals:    true,
+	})
+	if _, err := NewBattle(Config{Seed: 1}, factions...); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// attackOf returns the attacker and the target of an attack event
+// or nil for any other event
+func attackOf(event Event) (attacker, target Soldier) {
+	switch ev := event.(type) {
+	case EventHit:
+		return ev.Attacker, ev.Attacked
+	case EventKill:
+		return ev.Attacker, ev.Killed
+	case EventMiss:
+		return ev.Attacker, ev.Attacked
+	case EventDodge:
+		return ev.Attacker, ev.Defernder
+	case EventBlock:
+		return ev.Attacker, ev.Defender
+	}
+	return nil, nil
+}
+
+// TestTeamNames makes sure teams can't be named after other factions
+// since the factions without a team form a team named after themselves
+func TestTeamNames(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		teams   [3]string
+		invalid bool
+	}{
+		{name: "own teams"},
+		{name: "shared team", teams: [3]string{"North", "North", ""}},
+		{name: "team named after its faction", teams: [3]string{"A", "", ""}},
+		{
+			name:    "team named after a faction without a team",
+			teams:   [3]string{"", "", "A"},
+			invalid: true,
+		},
+		{
+			name:    "team named after an allied faction",
+			teams:   [3]string{"A", "A", ""},
+			invalid: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factions := append(testFactions(5, 50), Faction{
+				Name:              "C",
+				ArmySize:          5,
+				SoldierAttributes: testAttributes(50),
+			})
+			for i, team := range tc.teams {
+				factions[i].Team = team
+			}
+			_, err := NewBattle(Config{Seed: 1}, factions...)
+			switch {
+			case tc.invalid && err == nil:
+				t.Fatal("expected an error")
+			case !tc.invalid && err != nil:
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -12,12 +12,27 @@ import (
 
 // Battlefield allows
 type Battlefield interface {
-	// FindOpponent returns either an opponent from an opposing faction
-	// or an error if case no more opponents are left
-	FindOpponent(ownFactionName string) (Soldier, error)
+	// FindOpponent returns either an opponent of the given soldier
+	// from an opposing faction or an error if case no more opponents are left
+	FindOpponent(soldier Soldier) (Soldier, error)
+
+	// FindAlly returns either a wounded ally of the given soldier
+	// or ErrNoWoundedAllies in case no ally needs healing
+	FindAlly(soldier Soldier) (Soldier, error)
 
 	// MarkDead marks a soldier as dead
 	MarkDead(soldier Soldier) error
+
+	// MarkRouted marks a soldier as routed.
+	// Routed soldiers can still be attacked but don't keep
+	// their faction in the battle
+	MarkRouted(soldier Soldier) error
+
+	// MarkRallied marks a routed soldier as fighting again
+	MarkRallied(soldier Soldier) error
+
+	// MarkFled marks a routed soldier as having left the battlefield
+	MarkFled(soldier Soldier) error
 }
 
 // Faction represents a faction config
@@ -25,26 +40,200 @@ type Faction struct {
 	Name              string
 	ArmySize          uint
 	SoldierAttributes SoldierAttributes
+
+	// Units defines the unit groups of the faction's army.
+	// A faction defining units must leave ArmySize and SoldierAttributes
+	// undefined, otherwise the army consists of ArmySize soldiers
+	// of an unnamed unit type sharing the same SoldierAttributes
+	Units []Unit
+
+	// Loadouts and Veterans define the equipment and the veterans
+	// of the unnamed unit type (see Unit) if the faction defines no units
+	Loadouts []Loadout
+	Veterans []Veteran
+
+	// Deployment defines the zone the soldiers of the faction
+	// are placed in on a spatial battlefield (see Config.Field)
+	Deployment Zone
+
+	// Targeting defines how the soldiers of the faction select
+	// their opponents. By default, opponents are selected randomly
+	// or, on a spatial battlefield, by distance
+	Targeting TargetingStrategy
+
+	// Command defines the squads and the commander of the faction
+	Command Command
+
+	// Reinforcements defines the waves of soldiers joining
+	// the faction's army during the battle
+	Reinforcements []Wave
+
+	// Team defines the team of allied factions the faction belongs to.
+	// Allied factions don't attack each other and win together.
+	// By default, the faction forms a team of its own named after it,
+	// which is why the team must not be named after another faction
+	Team string
+
+	// Neutral makes the faction stay out of the battle until it's attacked.
+	// Soldiers of a neutral faction only fight the factions that attacked
+	// them and neutral factions don't take part in the victory
+	Neutral bool
+
+	// AttackNeutrals makes the soldiers of a non-neutral faction
+	// attack the neutral factions as well, provoking them to fight back.
+	// By default, neutral factions are only attacked once provoked
+	AttackNeutrals bool
+
+	// BreakAlliance makes the faction leave its team
+	// once the trigger fires
+	BreakAlliance *Trigger
+}
+
+// team returns the name of the team the faction initially belongs to
+func (f Faction) team() string {
+	if f.Team != "" {
+		return f.Team
+	}
+	return f.Name
+}
+
+// units returns the unit groups of the faction's army
+func (f Faction) units() []Unit {
+	if len(f.Units) > 0 {
+		return f.Units
+	}
+	return []Unit{{
+		Count:             f.ArmySize,
+		SoldierAttributes: f.SoldierAttributes,
+		Loadouts:          f.Loadouts,
+		Veterans:          f.Veterans,
+	}}
+}
+
+// Size returns the total number of soldiers in the faction's army
+// including the commander
+func (f Faction) Size() uint {
+	size := uint(0)
+	for _, unit := range f.units() {
+		size += unit.Count
+	}
+	if f.Command.Commander != nil {
+		size++
+	}
+	return size
+}
+
+// MaxSize returns the total number of soldiers in the faction's army
+// including the commander and all reinforcements
+func (f Faction) MaxSize() uint {
+	size := f.Size()
+	for _, w := range f.Reinforcements {
+		for _, unit := range w.units() {
+			size += unit.Count
+		}
+	}
+	return size
 }
 
 // Battle represents a battle
 type Battle struct {
-	lock   *sync.Mutex
-	armies map[string][]Soldier
-	alive  map[string][]Soldier
-	stats  *Statistics
-	config Config
+	lock        *sync.Mutex
+	battlefield Battlefield
+	factions    []string
+	targeting   map[string]TargetingStrategy
+	commands    map[string]*command
+	names       map[string]map[string]struct{}
+	waves       map[string][]*wave
+	teams       map[string]string
+	neutral     map[string]bool
+	raiders     map[string]bool
+	provoked    map[string]map[string]bool
+	breaks      []*allianceBreak
+	spawnLock   *sync.Mutex
+	runCtx      context.Context
+	wg          *sync.WaitGroup
+	armies      map[string][]Soldier
+	alive       map[string][]Soldier
+	routed      map[string][]Soldier
+	fled        map[string][]Soldier
+	decide      context.CancelFunc
+	stats       *Statistics
+	config      Config
+	rng         *rng
+	random      *rand.Rand
+	clock       Clock
+	sched       *scheduler
+	recording   *Recording
+
+	// pauseLock protects the pace of the battle
+	// and the running tickers frozen by pauses
+	pauseLock *sync.Mutex
+	paused    bool
+	speed     float64
+	tickers   map[*DynamicTicker]struct{}
+
+	// actionLock is read-locked by every action taken during the battle
+	// and locked to take snapshots between the actions
+	actionLock *sync.RWMutex
+	start      time.Time
+
+	// elapsed, restored and resumptions describe a battle restored
+	// from a snapshot (see RestoreBattle)
+	elapsed     time.Duration
+	restored    bool
+	resumptions []func(ctx context.Context, wg *sync.WaitGroup)
 }
 
 // Config represents the configuration of a battle
 type Config struct {
 	BaseActionDelay time.Duration
+
+	// Seed seeds the pseudo-random number generator of the battle.
+	// A battle with a non-zero seed is reproducible: the soldier actions
+	// are executed sequentially in the order of their schedule and the same
+	// seed always yields the same armies and the same battle log.
+	// A zero seed makes the battle seed itself from the current time
+	// and lets the soldiers act concurrently
+	Seed int64
+
+	// Clock defines the time source of the battle and defaults to RealClock.
+	// A battle using a VirtualClock is executed sequentially
+	// (see Seed) and runs as fast as possible instead of in real time
+	Clock Clock
+
+	// Field makes the battle take place on a spatial battlefield
+	// of the given dimensions when defined. Soldiers are deployed within
+	// the deployment zones of their factions, move toward their opponents
+	// and can only attack within their attack range
+	Field *Field
+
+	// DamageModifiers defines the damage multipliers between unit types
+	DamageModifiers DamageMatrix
+
+	// Veterancy defines how soldiers gain experience and levels
+	Veterancy Veterancy
+
+	// MoraleContagionRadius limits the morale contagion of kills
+	// (see SoldierAttributes.ComradeDeathMoralePenalty) to the comrades
+	// within the given distance on a spatial battlefield.
+	// Zero spreads the morale among the entire faction
+	MoraleContagionRadius float64
 }
 
 // NewBattle creates a new battle
 func NewBattle(
 	config Config,
 	factions ...Faction,
+) (*Battle, error) {
+	return newBattle(config, factions, nil)
+}
+
+// newBattle creates a new battle generating the armies of the factions
+// unless the battle is restored from the given snapshot
+func newBattle(
+	config Config,
+	factions []Faction,
+	snapshot *Snapshot,
 ) (*Battle, error) {
 	if len(factions) < 2 {
 		return nil, errors.Errorf(
@@ -53,50 +242,195 @@ func NewBattle(
 		)
 	}
 
+	names := make(map[string]bool, len(factions))
+	for _, faction := range factions {
+		names[faction.Name] = true
+	}
+	for _, faction := range factions {
+		if faction.Team != faction.Name && names[faction.Team] {
+			return nil, errors.Errorf(
+				"team %s of faction %s is named after another faction",
+				faction.Team,
+				faction.Name,
+			)
+		}
+	}
+
+	if err := config.DamageModifiers.Verify(); err != nil {
+		return nil, err
+	}
+	if err := config.Veterancy.Verify(); err != nil {
+		return nil, errors.Wrap(err, "invalid veterancy")
+	}
+	if config.MoraleContagionRadius < 0 {
+		return nil, errors.Errorf(
+			"invalid morale contagion radius: %.1f",
+			config.MoraleContagionRadius,
+		)
+	}
+
+	clock := config.Clock
+	if clock == nil {
+		clock = RealClock{}
+	}
+
 	battle := &Battle{
-		lock:   &sync.Mutex{},
-		stats:  NewStatistics(),
-		config: config,
+		lock:       &sync.Mutex{},
+		factions:   make([]string, 0, len(factions)),
+		targeting:  make(map[string]TargetingStrategy, len(factions)),
+		commands:   make(map[string]*command, len(factions)),
+		names:      make(map[string]map[string]struct{}, len(factions)),
+		waves:      make(map[string][]*wave, len(factions)),
+		teams:      make(map[string]string, len(factions)),
+		neutral:    make(map[string]bool, len(factions)),
+		raiders:    make(map[string]bool, len(factions)),
+		provoked:   make(map[string]map[string]bool, len(factions)),
+		spawnLock:  &sync.Mutex{},
+		routed:     make(map[string][]Soldier, len(factions)),
+		fled:       make(map[string][]Soldier, len(factions)),
+		stats:      NewStatistics(clock),
+		config:     config,
+		clock:      clock,
+		recording:  &Recording{Version: RecordingVersion},
+		pauseLock:  &sync.Mutex{},
+		speed:      1,
+		tickers:    make(map[*DynamicTicker]struct{}),
+		actionLock: &sync.RWMutex{},
+	}
+
+	if config.Seed != 0 {
+		battle.rng = newRNG(config.Seed)
+	} else {
+		battle.rng = newRNG(time.Now().UnixNano())
+	}
+	battle.random = rand.New(battle.rng)
+
+	_, isVirtualClock := clock.(*VirtualClock)
+	if config.Seed != 0 || isVirtualClock ||
+		snapshot != nil && snapshot.Sequential {
+		battle.sched = newScheduler(clock)
+	}
+
+	battle.battlefield = battle
+	var field *fieldBattlefield
+	if config.Field != nil {
+		var err error
+		if field, err = newFieldBattlefield(battle, *config.Field); err != nil {
+			return nil, err
+		}
+		battle.battlefield = field
 	}
 
 	armies := make(map[string][]Soldier, len(factions))
 	for _, faction := range factions {
-		// Generate the faction's army
-		names := make(map[SoldierID]struct{}, faction.ArmySize)
-		army := make([]Soldier, 0, faction.ArmySize)
-		for i := uint(0); i < faction.ArmySize; i++ {
-			// Generate unique name
-			id := SoldierID{
-				Faction: faction.Name,
+		if _, duplicate := armies[faction.Name]; duplicate {
+			return nil, errors.Errorf(
+				"duplicate faction name: '%s'",
+				faction.Name,
+			)
+		}
+		if len(faction.Units) > 0 && (faction.ArmySize != 0 ||
+			faction.SoldierAttributes != SoldierAttributes{} ||
+			len(faction.Loadouts) > 0 || len(faction.Veterans) > 0) {
+			return nil, errors.Errorf(
+				"faction %s defines both units and an army size, "+
+					"soldier attributes, loadouts or veterans",
+				faction.Name,
+			)
+		}
+		if err := faction.Command.Verify(); err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"invalid command of faction %s",
+				faction.Name,
+			)
+		}
+		if faction.Neutral && faction.AttackNeutrals {
+			return nil, errors.Errorf(
+				"neutral faction %s can't attack neutral factions",
+				faction.Name,
+			)
+		}
+		if field != nil {
+			if err := verifyMobility(faction); err != nil {
+				return nil, errors.Wrapf(err, "faction %s", faction.Name)
 			}
-			for {
-				id.Name = randomdata.SillyName()
-				if _, alreadyExists := names[id]; !alreadyExists {
-					break
-				}
+		}
+		battle.factions = append(battle.factions, faction.Name)
+		battle.recording.Factions = append(
+			battle.recording.Factions,
+			RecordedFaction{Name: faction.Name, Units: faction.Units},
+		)
+		battle.targeting[faction.Name] = faction.Targeting
+		battle.teams[faction.Name] = faction.team()
+		battle.neutral[faction.Name] = faction.Neutral
+		battle.raiders[faction.Name] = faction.AttackNeutrals
+		if faction.BreakAlliance != nil {
+			if err := faction.BreakAlliance.Verify(); err != nil {
+				return nil, errors.Wrapf(
+					err,
+					"invalid alliance break of faction %s",
+					faction.Name,
+				)
 			}
+			battle.breaks = append(battle.breaks, &allianceBreak{
+				faction: faction.Name,
+				trigger: *faction.BreakAlliance,
+			})
+		}
+		cmd := &command{
+			battle:  battle,
+			faction: faction.Name,
+			config:  faction.Command,
+		}
+		battle.commands[faction.Name] = cmd
 
-			soldier, err := newSoldier(
-				id.Name,
-				faction.Name,
-				faction.SoldierAttributes,
-				config,
-				Battlefield(battle),
-				LogWriter(battle.stats),
-			)
-			if err != nil {
+		for i, w := range faction.Reinforcements {
+			if err := w.Verify(); err != nil {
 				return nil, errors.Wrapf(
 					err,
-					"generating soldier for faction %s",
+					"invalid reinforcement wave %d of faction %s",
+					i,
 					faction.Name,
 				)
 			}
-			army = append(army, soldier)
+			battle.waves[faction.Name] = append(
+				battle.waves[faction.Name],
+				&wave{
+					config:   w,
+					index:    i,
+					faction:  faction,
+					armySize: faction.Size(),
+				},
+			)
+		}
+
+		if snapshot != nil {
+			// The army is restored from the snapshot
+			armies[faction.Name] = nil
+			continue
+		}
+		army, err := battle.generateArmy(faction, field)
+		if err != nil {
+			return nil, err
 		}
 		armies[faction.Name] = army
 	}
 	battle.armies = armies
 
+	belligerents := 0
+	for _, faction := range factions {
+		if !faction.Neutral {
+			belligerents++
+		}
+	}
+	if belligerents < 2 {
+		return nil, errors.Errorf(
+			"invalid number of non-neutral factions: %d",
+			belligerents,
+		)
+	}
+
 	alive := make(map[string][]Soldier, len(factions))
 	for factionName, army := range armies {
 		cp := make([]Soldier, len(army))
@@ -108,89 +442,598 @@ func NewBattle(
 	return battle, nil
 }
 
+// generateArmy generates the army of the faction
+func (b *Battle) generateArmy(
+	faction Faction,
+	field *fieldBattlefield,
+) ([]Soldier, error) {
+	cmd := b.commands[faction.Name]
+	names := make(map[string]struct{}, faction.MaxSize())
+	b.names[faction.Name] = names
+	for _, unit := range faction.units() {
+		for _, veteran := range unit.Veterans {
+			if _, duplicate := names[veteran.Name]; duplicate {
+				return nil, errors.Errorf(
+					"duplicate veteran name '%s' in faction %s",
+					veteran.Name,
+					faction.Name,
+				)
+			}
+			names[veteran.Name] = struct{}{}
+		}
+	}
+	army := make([]Soldier, 0, faction.Size())
+	unitTypes := make(map[string]struct{}, len(faction.units()))
+	for _, unit := range faction.units() {
+		if _, duplicate := unitTypes[unit.Type]; duplicate {
+			return nil, errors.Errorf(
+				"duplicate unit type '%s' in faction %s",
+				unit.Type,
+				faction.Name,
+			)
+		}
+		unitTypes[unit.Type] = struct{}{}
+		if err := verifyLoadouts(unit.Loadouts); err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"invalid loadouts of unit '%s' of faction %s",
+				unit.Type,
+				faction.Name,
+			)
+		}
+		if err := verifyVeterans(unit.Veterans, unit.Count); err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"invalid veterans of unit '%s' of faction %s",
+				unit.Type,
+				faction.Name,
+			)
+		}
+
+		soldiers, err := b.generateUnit(faction, unit, names, field)
+		if err != nil {
+			return nil, err
+		}
+		cmd.formSquads(soldiers)
+		for _, soldier := range soldiers {
+			army = append(army, soldier)
+		}
+	}
+
+	if faction.Command.Commander != nil {
+		commander, err := b.generateSoldier(
+			faction,
+			"",
+			*faction.Command.Commander,
+			Equipment{},
+			"",
+			names,
+			field,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "generating commander")
+		}
+		commander.isCommander = true
+		cmd.commander = commander
+		b.record(commander)
+		army = append(army, commander)
+	}
+	return army, nil
+}
+
+// generateSoldier generates a soldier with a unique name
+// and deploys it on the battlefield
+func (b *Battle) generateSoldier(
+	faction Faction,
+	unitType string,
+	attrs SoldierAttributes,
+	equipment Equipment,
+	name string,
+	names map[string]struct{},
+	field *fieldBattlefield,
+) (*soldier, error) {
+	// Generate unique name unless the name was reserved
+	id := SoldierID{
+		Faction: faction.Name,
+		Name:    name,
+		Unit:    unitType,
+	}
+	for id.Name == "" {
+		id.Name = randomName(b.rng)
+		if _, alreadyExists := names[id.Name]; alreadyExists {
+			id.Name = ""
+		}
+	}
+
+	soldier, err := newSoldier(
+		id,
+		attrs,
+		equipment,
+		b.config,
+		b.rng,
+		b.newTicker(),
+		b.battlefield,
+		b.log(),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"generating soldier for faction %s",
+			faction.Name,
+		)
+	}
+	if field != nil {
+		if err := field.deploy(id, faction.Deployment); err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"deploying soldier for faction %s",
+				faction.Name,
+			)
+		}
+	}
+
+	names[id.Name] = struct{}{}
+	soldier.command = b.commands[faction.Name]
+	soldier.repeater = b
+	soldier.pacer = b
+	return soldier, nil
+}
+
+// generateUnit generates the soldiers of a unit.
+// The names of the unit's veterans are expected to be reserved
+func (b *Battle) generateUnit(
+	faction Faction,
+	unit Unit,
+	names map[string]struct{},
+	field *fieldBattlefield,
+) ([]*soldier, error) {
+	soldiers := make([]*soldier, unit.Count)
+	for i := range soldiers {
+		var veteran Veteran
+		if i < len(unit.Veterans) {
+			veteran = unit.Veterans[i]
+		}
+		var equipment Equipment
+		if veteran.Equipment != nil {
+			equipment = *veteran.Equipment
+		} else {
+			equipment = rollLoadout(unit.Loadouts, b.rng)
+		}
+		soldier, err := b.generateSoldier(
+			faction,
+			unit.Type,
+			unit.SoldierAttributes,
+			equipment,
+			veteran.Name,
+			names,
+			field,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if veteran.MaxHealth > 0 {
+			soldier.maxHealth = veteran.MaxHealth
+			soldier.status.MaxHealth = veteran.MaxHealth
+			soldier.status.Health = veteran.Health
+			soldier.status.Morale = veteran.Morale
+		}
+		soldier.status.Experience = veteran.Experience
+		soldier.status.Level = b.config.Veterancy.Level(veteran.Experience)
+		soldier.stats = veteran.Stats
+		b.record(soldier)
+		soldiers[i] = soldier
+	}
+	return soldiers, nil
+}
+
+// newTicker creates a new action ticker for a soldier.
+// The ticker is paused while the battle is paused
+// until it's released (see releaseTicker)
+func (b *Battle) newTicker() *DynamicTicker {
+	if b.sched != nil {
+		return b.sched.newTicker()
+	}
+	tk := NewDynamicTicker(b.clock)
+
+	b.pauseLock.Lock()
+	defer b.pauseLock.Unlock()
+	b.tickers[tk] = struct{}{}
+	if b.paused {
+		tk.Pause()
+	}
+	return tk
+}
+
+// releaseTicker stops pausing and resuming a ticker
+// that won't tick anymore
+func (b *Battle) releaseTicker(tk *DynamicTicker) {
+	b.pauseLock.Lock()
+	defer b.pauseLock.Unlock()
+	delete(b.tickers, tk)
+}
+
+// join makes the soldier join the battle
+// and releases its action ticker once it left the battle
+func (b *Battle) join(ctx context.Context, s Soldier) {
+	s.JoinBattle(ctx)
+	if s, ok := s.(*soldier); ok {
+		b.releaseTicker(s.actionTicker)
+	}
+}
+
+// Battlefield returns the battlefield the battle takes place on
+func (b *Battle) Battlefield() Battlefield {
+	return b.battlefield
+}
+
+// Squads returns the squads of the given faction
+func (b *Battle) Squads(factionName string) []*Squad {
+	cmd, known := b.commands[factionName]
+	if !known {
+		return nil
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	squads := make([]*Squad, len(cmd.squads))
+	copy(squads, cmd.squads)
+	return squads
+}
+
+// Commander returns the commander of the given faction
+// or nil if the faction has no commander
+func (b *Battle) Commander(factionName string) Soldier {
+	cmd, known := b.commands[factionName]
+	if !known || cmd.commander == nil {
+		return nil
+	}
+	return cmd.commander
+}
+
+// Soldiers returns the soldiers deployed for the given faction
+// including the commander and the reinforcements arrived so far
+func (b *Battle) Soldiers(factionName string) []Soldier {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]Soldier(nil), b.armies[factionName]...)
+}
+
 // Statistics returns the battle statistics reader
 func (b *Battle) Statistics() StatisticsReader {
 	return b.stats
 }
 
-// FindOpponent implements the interface Battlefield
-func (b *Battle) FindOpponent(ownFactionName string) (Soldier, error) {
+// opponents returns a copy of all opponents of the given faction
+// still on the battlefield including the routed ones
+func (b *Battle) opponents(ownFactionName string) []Soldier {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	opposingFactions := make([]string, len(b.alive)-1)
-	i := 0
-	for faction := range b.alive {
-		if faction == ownFactionName {
-			continue
+	var opponents []Soldier
+	for _, faction := range b.factions {
+		if b.hostile(ownFactionName, faction) {
+			opponents = append(opponents, b.alive[faction]...)
+			opponents = append(opponents, b.routed[faction]...)
 		}
-		opposingFactions[i] = faction
-		i++
 	}
+	return opponents
+}
+
+// allies returns a copy of all allies of the given soldier
+// still on the battlefield including the routed ones
+func (b *Battle) allies(soldier Soldier) []Soldier {
+	id := soldier.ID()
 
-	randFactionName := opposingFactions[rand.Intn(len(opposingFactions))]
-	army := b.alive[randFactionName]
+	b.lock.Lock()
+	defer b.lock.Unlock()
 
-	if len(army) < 1 {
-		return nil, ErrNoMoreOpponents
+	var allies []Soldier
+	for _, faction := range b.factions {
+		if !b.allied(id.Faction, faction) {
+			continue
+		}
+		for _, ally := range b.alive[faction] {
+			if ally.ID() != id {
+				allies = append(allies, ally)
+			}
+		}
+		for _, ally := range b.routed[faction] {
+			if ally.ID() != id {
+				allies = append(allies, ally)
+			}
+		}
 	}
+	return allies
+}
 
-	// Take random opponent
-	return army[rand.Intn(len(army))], nil
+// selectTarget selects an opponent for the given soldier
+// using the targeting strategy of its faction
+func (b *Battle) selectTarget(
+	soldier Soldier,
+	strategy TargetingStrategy,
+) (Soldier, error) {
+	// The candidates are copied to not hold the lock while the strategy
+	// inspects the soldiers
+	candidates := b.opponents(soldier.ID().Faction)
+	if len(candidates) < 1 {
+		return nil, ErrNoMoreOpponents
+	}
+	return strategy.SelectTarget(soldier, candidates, b.random), nil
 }
 
-// MarkDead implements the interface Battlefield
-func (b *Battle) MarkDead(soldier Soldier) error {
-	id := soldier.ID()
+// FindOpponent implements the interface Battlefield
+func (b *Battle) FindOpponent(soldier Soldier) (Soldier, error) {
+	ownFactionName := soldier.ID().Faction
+	if strategy := b.targeting[ownFactionName]; strategy != nil {
+		return b.selectTarget(soldier, strategy)
+	}
 
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	// Find army
-	alive, armyFound := b.alive[id.Faction]
-	if !armyFound {
-		return errors.Errorf("unknown faction '%s'", id.Faction)
+	opposingFactions := make([]string, 0, len(b.factions)-1)
+	for _, faction := range b.factions {
+		if !b.hostile(ownFactionName, faction) ||
+			len(b.alive[faction])+len(b.routed[faction]) < 1 {
+			continue
+		}
+		opposingFactions = append(opposingFactions, faction)
+	}
+
+	if len(opposingFactions) < 1 {
+		return nil, ErrNoMoreOpponents
 	}
 
-	// Find soldier
+	randFactionName := opposingFactions[b.rng.intn(len(opposingFactions))]
+	alive := b.alive[randFactionName]
+	routed := b.routed[randFactionName]
+
+	// Take random opponent, routed ones can be pursued
+	index := b.rng.intn(len(alive) + len(routed))
+	if index < len(alive) {
+		return alive[index], nil
+	}
+	return routed[index-len(alive)], nil
+}
+
+// FindAlly implements the interface Battlefield.
+// Returns the ally with the lowest share of health left
+func (b *Battle) FindAlly(soldier Soldier) (Soldier, error) {
+	// The allies are copied to not hold the lock
+	// while inspecting their status
+	var patient Soldier
+	lowest := 1.0
+	for _, ally := range b.allies(soldier) {
+		status := ally.Status()
+		if share := status.Health / status.MaxHealth; share < lowest {
+			patient = ally
+			lowest = share
+		}
+	}
+	if patient == nil {
+		return nil, ErrNoWoundedAllies
+	}
+	return patient, nil
+}
+
+// remove removes the soldier from the given list of the faction
+// and returns true if the soldier was found.
+// Expects the battle lock to be locked
+func (b *Battle) remove(list map[string][]Soldier, id SoldierID) bool {
+	soldiers := list[id.Faction]
 	index := -1
-	for i, s := range alive {
+	for i, s := range soldiers {
 		if s.ID() == id {
 			index = i
 			break
 		}
 	}
 	if index < 0 {
+		return false
+	}
+
+	last := len(soldiers) - 1
+	soldiers[last], soldiers[index] = soldiers[index], soldiers[last]
+	list[id.Faction] = soldiers[:last]
+	return true
+}
+
+// standing returns true if the faction is still taking part in the victory.
+// Factions awaiting reinforcements are still standing
+// while neutral factions never are.
+// Expects the battle lock to be locked
+func (b *Battle) standing(factionName string) bool {
+	return !b.neutral[factionName] && (len(b.alive[factionName]) > 0 ||
+		b.pendingWaves(factionName))
+}
+
+// standingTeams returns the names of the teams left standing.
+// Expects the battle lock to be locked
+func (b *Battle) standingTeams() []string {
+	var standing []string
+	seen := make(map[string]bool, len(b.factions))
+	for _, faction := range b.factions {
+		team := b.teams[faction]
+		if seen[team] || !b.standing(faction) {
+			continue
+		}
+		seen[team] = true
+		standing = append(standing, team)
+	}
+	return standing
+}
+
+// decideIfOver ends the battle once at most one team is left standing
+// unless an alliance is yet to break by a delayed trigger
+// splitting the last team.
+// Expects the battle lock to be locked
+func (b *Battle) decideIfOver() {
+	if len(b.standingTeams()) > 1 || b.decide == nil {
+		return
+	}
+	for _, ab := range b.breaks {
+		if !ab.fired && ab.trigger.Delay > 0 && b.standingAlly(ab.faction) {
+			return
+		}
+	}
+	b.decide()
+}
+
+// standingAlly returns true if both the faction and at least one
+// of its allies are still standing.
+// Expects the battle lock to be locked
+func (b *Battle) standingAlly(factionName string) bool {
+	if !b.standing(factionName) {
+		return false
+	}
+	for _, faction := range b.factions {
+		if faction != factionName && b.standing(faction) &&
+			b.teams[faction] == b.teams[factionName] {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkDead implements the interface Battlefield
+func (b *Battle) MarkDead(soldier Soldier) error {
+	id := soldier.ID()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, armyFound := b.alive[id.Faction]; !armyFound {
+		return errors.Errorf("unknown faction '%s'", id.Faction)
+	}
+
+	// Remove the soldier from the list of the living
+	if !b.remove(b.alive, id) && !b.remove(b.routed, id) {
 		// Dead or not found
 		return nil
 	}
+	b.decideIfOver()
 
-	// Remove the soldier from the list of the living
-	alive[len(alive)-1], alive[index] = alive[index], alive[len(alive)-1]
-	b.alive[id.Faction] = alive[:len(alive)-1]
+	return nil
+}
+
+// MarkRouted implements the interface Battlefield
+func (b *Battle) MarkRouted(soldier Soldier) error {
+	id := soldier.ID()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, armyFound := b.alive[id.Faction]; !armyFound {
+		return errors.Errorf("unknown faction '%s'", id.Faction)
+	}
+
+	if b.remove(b.alive, id) {
+		b.routed[id.Faction] = append(b.routed[id.Faction], soldier)
+		b.decideIfOver()
+	}
+	return nil
+}
+
+// MarkRallied implements the interface Battlefield
+func (b *Battle) MarkRallied(soldier Soldier) error {
+	id := soldier.ID()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, armyFound := b.alive[id.Faction]; !armyFound {
+		return errors.Errorf("unknown faction '%s'", id.Faction)
+	}
+
+	if b.remove(b.routed, id) {
+		b.alive[id.Faction] = append(b.alive[id.Faction], soldier)
+	}
+	return nil
+}
+
+// MarkFled implements the interface Battlefield
+func (b *Battle) MarkFled(soldier Soldier) error {
+	id := soldier.ID()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, armyFound := b.alive[id.Faction]; !armyFound {
+		return errors.Errorf("unknown faction '%s'", id.Faction)
+	}
 
+	if b.remove(b.routed, id) {
+		b.fled[id.Faction] = append(b.fled[id.Faction], soldier)
+	}
 	return nil
 }
 
 // Run runs the battle until it's either finished or canceled by the provided
-// context
-func (b *Battle) Run(ctx context.Context) {
+// context and returns the result of the battle
+func (b *Battle) Run(ctx context.Context) Result {
 	wg := &sync.WaitGroup{}
+	// A restored battle continues the time of the snapshot
+	start := b.clock.Now().Add(-b.elapsed)
+
+	// The battle context is canceled as soon as the battle is decided
+	// to stop the remaining soldiers (including routed ones)
+	battleCtx, decide := context.WithCancel(ctx)
+	defer decide()
+
+	// Events of a decided battle are still published to subscribers
+	// blocking the battle unless the battle is canceled
+	b.stats.cancelOn(ctx)
+	b.lock.Lock()
+	b.decide = decide
+	b.runCtx = battleCtx
+	b.wg = wg
+	b.start = start
+	if !b.restored {
+		b.recording.Start = start
+	}
+	fighting := make(map[string][]Soldier, len(b.armies))
+	for _, factionName := range b.factions {
+		fighting[factionName] = append(
+			append([]Soldier(nil), b.alive[factionName]...),
+			b.routed[factionName]...,
+		)
+	}
+	b.decideIfOver()
+	b.lock.Unlock()
+
+	// Reinforcements might already be needed
+	// in case of armies smaller than their thresholds
+	for _, factionName := range b.factions {
+		if err := b.reinforceIfNeeded(factionName); err != nil {
+			panic(errors.Wrap(err, "unexpected reinforcement err"))
+		}
+	}
+	if b.restored {
+		for _, resume := range b.resumptions {
+			resume(battleCtx, wg)
+		}
+	} else {
+		b.scheduleWaves(battleCtx, wg)
+		b.scheduleAllianceBreaks(battleCtx, wg)
+	}
+
+	if b.sched != nil {
+		// Drive the action tickers sequentially
+		go b.sched.run(battleCtx)
+	}
 
-	// Register all soldiers in the wait-group
-	h := 0
-	for _, army := range b.armies {
-		h += len(army)
-		wg.Add(len(army))
+	// Register all fighting soldiers in the wait-group
+	for _, soldiers := range fighting {
+		wg.Add(len(soldiers))
 	}
 
 	// Make the soldiers join the battle
-	for _, army := range b.armies {
-		for _, soldier := range army {
+	for _, factionName := range b.factions {
+		for _, soldier := range fighting[factionName] {
 			s := soldier
 			go func() {
 				defer wg.Done()
-				s.JoinBattle(ctx)
+				b.join(battleCtx, s)
 			}()
 		}
 	}
@@ -202,10 +1045,69 @@ func (b *Battle) Run(ctx context.Context) {
 	// Stop recorcing battle statistics
 	b.stats.StopRecording()
 
-	// Determine the winner
-	for factionName, army := range b.alive {
-		if len(army) > 0 {
-			b.stats.winnerFaction = factionName
+	result := Result{
+		Survivors: make(map[string][]Soldier, len(b.factions)),
+		Routed:    make(map[string][]Soldier, len(b.factions)),
+		Fled:      make(map[string][]Soldier, len(b.factions)),
+		Deployed:  make(map[string]int, len(b.factions)),
+		Duration:  b.clock.Now().Sub(start),
+		Events:    len(b.stats.Log()),
+	}
+
+	// Determine the outcome
+	b.lock.Lock()
+	for _, factionName := range b.factions {
+		alive := b.alive[factionName]
+		survivors := make([]Soldier, len(alive))
+		copy(survivors, alive)
+		result.Survivors[factionName] = survivors
+		result.Deployed[factionName] = len(b.armies[factionName])
+		result.Routed[factionName] = append(
+			[]Soldier(nil),
+			b.routed[factionName]...,
+		)
+		result.Fled[factionName] = append(
+			[]Soldier(nil),
+			b.fled[factionName]...,
+		)
+	}
+	standing := b.standingTeams()
+	if len(standing) == 1 {
+		result.WinnerTeam = standing[0]
+		for _, factionName := range b.factions {
+			if !b.neutral[factionName] &&
+				b.teams[factionName] == result.WinnerTeam {
+				result.Winners = append(result.Winners, factionName)
+			}
+		}
+	}
+	b.lock.Unlock()
+
+	switch {
+	case len(standing) == 1:
+		result.Outcome = OutcomeVictory
+		if len(result.Winners) == 1 {
+			b.stats.setWinnerFaction(result.Winners[0])
 		}
+	case len(standing) < 1:
+		result.Outcome = OutcomeDraw
+	case ctx.Err() == context.DeadlineExceeded:
+		result.Outcome = OutcomeTimeout
+	default:
+		result.Outcome = OutcomeCanceled
 	}
+	b.recordResult(result)
+
+	return result
+}
+
+// namesLock protects the global random source of the name generator
+var namesLock = &sync.Mutex{}
+
+// randomName generates a random soldier name using the given random source
+func randomName(r *rng) string {
+	namesLock.Lock()
+	defer namesLock.Unlock()
+	randomdata.CustomRand(rand.New(r))
+	return randomdata.SillyName()
 }
@@ -0,0 +1,568 @@
+package battle
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/romshark/go-battle-simulator/wire"
+)
+
+// TestSeededDeterminism makes sure battles of the same seed
+// take the same course in both virtual and real time
+func TestSeededDeterminism(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		virtual bool
+	}{
+		{name: "virtual clock", virtual: true},
+		{name: "real clock"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			for _, scenario := range testScenarios() {
+				scenario := scenario
+				t.Run(scenario.name, func(t *testing.T) {
+					run := func() ([]string, Result) {
+						config := scenario.config
+						if tc.virtual {
+							config.Clock = NewVirtualClock(time.Unix(0, 0))
+						}
+						btl := newTestBattle(t, config, scenario.factions()...)
+						result := awaitResult(
+							t,
+							runAsync(context.Background(), btl),
+						)
+						if result.Outcome != OutcomeVictory {
+							t.Fatalf("unexpected outcome: %s", result.Outcome)
+						}
+						log := btl.Statistics().Log()
+						if !tc.virtual {
+							// Only the virtual time is reproducible
+							for i := range log {
+								log[i].Time = time.Time{}
+							}
+						}
+						return encodeLog(t, log), result
+					}
+
+					log, result := run()
+					again, resultAgain := run()
+					compareLogs(t, again, log)
+					if !reflect.DeepEqual(resultAgain.Winners, result.Winners) {
+						t.Errorf(
+							"won by %v, expected %v",
+							resultAgain.Winners,
+							result.Winners,
+						)
+					}
+					if tc.virtual && resultAgain.Duration != result.Duration {
+						t.Errorf(
+							"took %s, expected %s",
+							resultAgain.Duration,
+							result.Duration,
+						)
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestOutcome makes sure the result of a battle reports its outcome
+// and where every deployed soldier ended up
+func TestOutcome(t *testing.T) {
+	virtual := Config{
+		BaseActionDelay: 10 * time.Millisecond,
+		Seed:            1,
+		Clock:           NewVirtualClock(time.Unix(0, 0)),
+	}
+	routing := testFactions(20, 50)
+	for i := range routing {
+		routing[i].SoldierAttributes.MoraleBreakThreshold = .5
+	}
+
+	for _, tc := range []struct {
+		name     string
+		config   Config
+		factions []Faction
+		timeout  time.Duration
+		canceled bool
+		outcome  Outcome
+		winners  []string
+		routs    bool
+	}{
+		{
+			name:     "victory",
+			config:   virtual,
+			factions: testFactions(5, 50),
+			outcome:  OutcomeVictory,
+			winners:  []string{"A"},
+		},
+		{
+			name:     "victory over routed soldiers",
+			config:   virtual,
+			factions: routing,
+			outcome:  OutcomeVictory,
+			winners:  []string{"A"},
+			routs:    true,
+		},
+		{
+			name:     "draw",
+			config:   virtual,
+			factions: testFactions(0, 50),
+			outcome:  OutcomeDraw,
+		},
+		{
+			name:     "timeout",
+			config:   Config{BaseActionDelay: time.Hour},
+			factions: testFactions(5, 50),
+			timeout:  10 * time.Millisecond,
+			outcome:  OutcomeTimeout,
+		},
+		{
+			name:     "canceled",
+			config:   Config{BaseActionDelay: time.Hour},
+			factions: testFactions(5, 50),
+			canceled: true,
+			outcome:  OutcomeCanceled,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.config
+			if clock, ok := config.Clock.(*VirtualClock); ok {
+				// Don't share the virtual clock among the battles
+				config.Clock = NewVirtualClock(clock.Now())
+			}
+			btl := newTestBattle(t, config, tc.factions...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.timeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+			}
+			defer cancel()
+			if tc.canceled {
+				cancel()
+			}
+			result := awaitResult(t, runAsync(ctx, btl))
+
+			if result.Outcome != tc.outcome {
+				t.Fatalf("unexpected outcome: %s", result.Outcome)
+			}
+			if !reflect.DeepEqual(result.Winners, tc.winners) {
+				t.Errorf("won by %v, expected %v", result.Winners, tc.winners)
+			}
+			log := btl.Statistics().Log()
+			if result.Events != len(log) {
+				t.Errorf("%d events reported, %d logged", result.Events, len(log))
+			}
+			routs := 0
+			for _, entry := range log {
+				if _, ok := entry.Event.(EventRout); ok {
+					routs++
+				}
+			}
+			if tc.routs != (routs > 0) {
+				t.Errorf("%d routs logged", routs)
+			}
+
+			for _, faction := range tc.factions {
+				name := faction.Name
+				if deployed := result.Deployed[name]; deployed !=
+					int(faction.Size()) {
+					t.Errorf("%s: %d soldiers deployed", name, deployed)
+				}
+				dead := 0
+				for _, s := range btl.Soldiers(name) {
+					if s.Status().Health <= 0 {
+						dead++
+					}
+				}
+				for _, s := range result.Survivors[name] {
+					if status := s.Status(); status.Health <= 0 ||
+						status.Routed {
+						t.Errorf("%s: survivor %s %+v", name, s.ID(), status)
+					}
+				}
+				for _, s := range result.Routed[name] {
+					if status := s.Status(); status.Health <= 0 ||
+						!status.Routed || status.Fled {
+						t.Errorf("%s: routed %s %+v", name, s.ID(), status)
+					}
+				}
+				for _, s := range result.Fled[name] {
+					if status := s.Status(); !status.Fled {
+						t.Errorf("%s: fled %s %+v", name, s.ID(), status)
+					}
+				}
+				if counted := dead +
+					len(result.Survivors[name]) +
+					len(result.Routed[name]) +
+					len(result.Fled[name]); counted != result.Deployed[name] {
+					t.Errorf(
+						"%s: %d soldiers accounted for, %d deployed",
+						name,
+						counted,
+						result.Deployed[name],
+					)
+				}
+			}
+			if tc.outcome == OutcomeTimeout || tc.outcome == OutcomeCanceled {
+				for _, faction := range tc.factions {
+					if survivors := len(result.Survivors[faction.Name]); survivors !=
+						int(faction.Size()) {
+						t.Errorf(
+							"%s: %d survivors of an undecided battle",
+							faction.Name,
+							survivors,
+						)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestReplay records seeded battles, replays the decoded recordings
+// and makes sure the replays log the same events as the battles
+func TestReplay(t *testing.T) {
+	for _, scenario := range testScenarios() {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			config := scenario.config
+			config.Clock = NewVirtualClock(time.Unix(0, 0))
+			btl := newTestBattle(t, config, scenario.factions()...)
+			result := awaitResult(t, runAsync(context.Background(), btl))
+			expected := encodeLog(t, btl.Statistics().Log())
+
+			recording, err := btl.Recording()
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf := &bytes.Buffer{}
+			if err := recording.Encode(buf); err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := DecodeRecording(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			replay, err := NewReplay(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := replay.Play(context.Background(), 0); err != nil {
+				t.Fatal(err)
+			}
+
+			compareLogs(t, encodeLog(t, replay.Statistics().Log()), expected)
+			replayed, recorded := replay.Result()
+			if !recorded {
+				t.Fatal("the recording has no result")
+			}
+			if replayed.Outcome != result.Outcome ||
+				!reflect.DeepEqual(replayed.Winners, result.Winners) ||
+				replayed.Duration != result.Duration {
+				t.Errorf(
+					"replayed %s of %v after %s, expected %s of %v after %s",
+					replayed.Outcome,
+					replayed.Winners,
+					replayed.Duration,
+					result.Outcome,
+					result.Winners,
+					result.Duration,
+				)
+			}
+		})
+	}
+}
+
+// testScenario represents a battle exercising a feature of the simulator
+type testScenario struct {
+	name   string
+	config Config
+
+	// factions creates new factions for every battle
+	// since targeting strategies keep state
+	factions func() []Faction
+}
+
+// testScenarios returns battles exercising the features of the simulator
+func testScenarios() []testScenario {
+	base := Config{BaseActionDelay: 10 * time.Millisecond, Seed: 42}
+	withConfig := func(modify func(config *Config)) Config {
+		config := base
+		modify(&config)
+		return config
+	}
+
+	mobile := testAttributes(50)
+	mobile.MovementSpeed = 2
+	mobile.AttackRange = 1.5
+
+	medic := testAttributes(40)
+	medic.HealingMin = 5
+	medic.HealingMax = 10
+
+	fragile := testAttributes(50)
+	fragile.MoraleBreakThreshold = .6
+	fragile.ComradeDeathMoralePenalty = .3
+	fragile.ComradeKillMoraleBonus = .05
+	fragile.CriticalChance = .2
+	fragile.CriticalMultiplier = 2
+	fragile.StaminaAttackCost = .1
+	fragile.StaminaDodgeCost = .05
+	fragile.StaminaRegeneration = .2
+
+	sword := Loadout{Weight: 1, Equipment: Equipment{Weapon: &Weapon{
+		Name:       "sword",
+		DamageType: "slashing",
+		DamageMin:  2,
+		DamageMax:  4,
+		Effects: []StatusEffect{
+			{
+				Kind:      EffectBleeding,
+				Chance:    .3,
+				Interval:  15 * time.Millisecond,
+				Ticks:     3,
+				Magnitude: 5,
+			},
+			{
+				Kind:     EffectStun,
+				Chance:   .1,
+				Interval: 20 * time.Millisecond,
+				Ticks:    1,
+			},
+		},
+	}}}
+	mace := Loadout{Weight: 1, Equipment: Equipment{Weapon: &Weapon{
+		Name:        "mace",
+		DamageMin:   1,
+		DamageMax:   3,
+		AttackSpeed: 1.5,
+		Effects: []StatusEffect{{
+			Kind:      EffectFear,
+			Chance:    .2,
+			Interval:  10 * time.Millisecond,
+			Ticks:     2,
+			Magnitude: .1,
+		}},
+	}}}
+	armored := Loadout{Weight: 1, Equipment: Equipment{
+		Armor: &Armor{
+			Name: "leather",
+			Protection: map[string]Protection{
+				"slashing": {Flat: 1, Percentage: .1},
+			},
+		},
+		Shield: &Shield{Name: "buckler", BlockChance: .1},
+	}}
+
+	return []testScenario{
+		{
+			name:     "plain",
+			config:   base,
+			factions: func() []Faction { return testFactions(10, 50) },
+		},
+		{
+			name: "field",
+			config: withConfig(func(config *Config) {
+				config.Field = &Field{Width: 20, Height: 20}
+				config.MoraleContagionRadius = 5
+			}),
+			factions: func() []Faction {
+				return []Faction{
+					{
+						Name:              "A",
+						ArmySize:          8,
+						SoldierAttributes: mobile,
+						Deployment: Zone{
+							Max: Position{X: 5, Y: 20},
+						},
+					},
+					{
+						Name:              "B",
+						ArmySize:          8,
+						SoldierAttributes: mobile,
+						Deployment: Zone{
+							Min: Position{X: 15},
+							Max: Position{X: 20, Y: 20},
+						},
+					},
+				}
+			},
+		},
+		{
+			name: "equipment",
+			config: withConfig(func(config *Config) {
+				config.DamageModifiers = DamageMatrix{
+					"infantry": {"archers": 1.5},
+				}
+			}),
+			factions: func() []Faction {
+				return []Faction{
+					{Name: "A", Units: []Unit{{
+						Type:              "infantry",
+						Count:             8,
+						SoldierAttributes: testAttributes(100),
+						Loadouts:          []Loadout{sword, mace},
+					}}},
+					{Name: "B", Units: []Unit{{
+						Type:              "archers",
+						Count:             8,
+						SoldierAttributes: testAttributes(100),
+						Loadouts:          []Loadout{armored},
+					}}},
+				}
+			},
+		},
+		{
+			name:   "morale",
+			config: base,
+			factions: func() []Faction {
+				factions := testFactions(10, 50)
+				factions[1].SoldierAttributes = fragile
+				return factions
+			},
+		},
+		{
+			name:   "command",
+			config: base,
+			factions: func() []Faction {
+				factions := testFactions(9, 50)
+				commander := testAttributes(20)
+				factions[0].Command = Command{
+					SquadSize: 3,
+					Order:     Order{Kind: OrderAdvance},
+					Commander: &commander,
+					Aura: Aura{
+						Morale:    .05,
+						HitChance: .05,
+					},
+					DeathMoralePenalty: .3,
+				}
+				factions[1].Targeting, _ = NewTargetingStrategy("focusFire")
+				return factions
+			},
+		},
+		{
+			name:   "reinforcements",
+			config: base,
+			factions: func() []Faction {
+				factions := testFactions(8, 50)
+				factions[1].ArmySize = 5
+				factions[1].Reinforcements = []Wave{
+					{
+						Size:              3,
+						SoldierAttributes: testAttributes(50),
+						ArmyThreshold:     .5,
+					},
+					{
+						Size:              2,
+						SoldierAttributes: testAttributes(50),
+						Delay:             50 * time.Millisecond,
+					},
+				}
+				return factions
+			},
+		},
+		{
+			name:   "alliances",
+			config: base,
+			factions: func() []Faction {
+				factions := testFactions(5, 50)
+				factions[0].Team = "North"
+				factions[1].Team = "North"
+				factions[1].BreakAlliance = &Trigger{
+					Delay: 300 * time.Millisecond,
+				}
+				return append(
+					factions,
+					Faction{
+						Name:              "C",
+						ArmySize:          8,
+						SoldierAttributes: testAttributes(50),
+					},
+					Faction{
+						Name:              "N",
+						ArmySize:          3,
+						SoldierAttributes: testAttributes(50),
+						Neutral:           true,
+					},
+				)
+			},
+		},
+		{
+			name: "veterancy",
+			config: withConfig(func(config *Config) {
+				config.Veterancy = Veterancy{
+					KillExperience:   10,
+					DamageExperience: 1,
+					LevelExperience:  20,
+					MaxLevel:         3,
+					HitChanceBonus:   .05,
+					DodgeChanceBonus: .02,
+					MoraleResilience: .1,
+				}
+			}),
+			factions: func() []Faction {
+				factions := testFactions(8, 50)
+				factions[0].Units = []Unit{
+					{
+						Type:              "infantry",
+						Count:             6,
+						SoldierAttributes: testAttributes(50),
+						Veterans: []Veteran{
+							{Name: "Veteran", Experience: 30},
+						},
+					},
+					{Type: "medics", Count: 2, SoldierAttributes: medic},
+				}
+				factions[0].ArmySize = 0
+				factions[0].SoldierAttributes = SoldierAttributes{}
+				return factions
+			},
+		},
+	}
+}
+
+// encodeLog encodes the log in the JSON wire format
+// failing the test on error
+func encodeLog(t *testing.T, log []LogEntry) []string {
+	t.Helper()
+	lines := make([]string, len(log))
+	buf := &bytes.Buffer{}
+	encoder := wire.NewJSONEncoder(buf)
+	for i, entry := range log {
+		wireEntry, err := WireEntry(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Reset()
+		if err := encoder.Encode(wireEntry); err != nil {
+			t.Fatal(err)
+		}
+		lines[i] = buf.String()
+	}
+	return lines
+}
+
+// compareLogs fails the test if the encoded logs differ
+func compareLogs(t *testing.T, log, expected []string) {
+	t.Helper()
+	for i := 0; i < len(log) && i < len(expected); i++ {
+		if log[i] != expected[i] {
+			t.Fatalf(
+				"entry %d differs:\n%s\nexpected:\n%s",
+				i,
+				log[i],
+				expected[i],
+			)
+		}
+	}
+	if len(log) != len(expected) {
+		t.Fatalf("%d entries logged, expected %d", len(log), len(expected))
+	}
+}
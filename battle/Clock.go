@@ -0,0 +1,51 @@
+package battle
+
+import "time"
+
+// Clock represents an abstract source of time
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// NewTimer creates a new timer that sends the current time
+	// on its channel after at least the given duration
+	NewTimer(duration time.Duration) Timer
+}
+
+// Timer represents an abstract single-shot timer
+type Timer interface {
+	// C returns the timer channel
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing and returns false
+	// if the timer already fired or was stopped
+	Stop() bool
+}
+
+// RealClock implements the Clock interface using the system time
+type RealClock struct{}
+
+// Now implements the Clock interface
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer implements the Clock interface
+func (RealClock) NewTimer(duration time.Duration) Timer {
+	return realTimer{time.NewTimer(duration)}
+}
+
+// realTimer implements the Timer interface using a system timer
+type realTimer struct {
+	timer *time.Timer
+}
+
+// C implements the Timer interface
+func (tm realTimer) C() <-chan time.Time {
+	return tm.timer.C
+}
+
+// Stop implements the Timer interface
+func (tm realTimer) Stop() bool {
+	return tm.timer.Stop()
+}
@@ -0,0 +1,265 @@
+package battle
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Command defines the command structure of a faction
+type Command struct {
+	// SquadSize defines the maximum number of soldiers per squad.
+	// Squads are formed within the units of the faction.
+	// Zero means no squads
+	SquadSize uint
+
+	// Order defines the initial order of all squads
+	Order Order
+
+	// Commander defines the attributes of the faction's commander
+	// who joins the army in addition to its units.
+	// The faction has no commander if nil
+	Commander *SoldierAttributes
+
+	// Aura defines the bonuses the commander grants to the soldiers
+	// of the faction while on the battlefield and not routed
+	Aura Aura
+
+	// DeathMoralePenalty defines the morale percentage all soldiers
+	// of the faction lose when the commander is killed
+	DeathMoralePenalty float64
+}
+
+// Verify returns an error if the command structure is invalid
+func (c *Command) Verify() error {
+	if c.Order.Kind == OrderFocus {
+		return errors.New("focus orders can only be given during the battle")
+	}
+	if c.Order.Kind != OrderAdvance && c.Order.Kind != OrderHold {
+		return errors.Errorf("invalid order: %d", c.Order.Kind)
+	}
+	if c.Aura.Morale < 0 || c.Aura.Morale > 1 {
+		return errors.Errorf("invalid aura morale %%: %.1f", c.Aura.Morale)
+	}
+	if c.Aura.HitChance < 0 || c.Aura.HitChance > 1 {
+		return errors.Errorf(
+			"invalid aura hit chance %%: %.1f",
+			c.Aura.HitChance,
+		)
+	}
+	if c.Aura.Radius < 0 {
+		return errors.Errorf("invalid aura radius: %.1f", c.Aura.Radius)
+	}
+	if c.DeathMoralePenalty < 0 || c.DeathMoralePenalty > 1 {
+		return errors.Errorf(
+			"invalid commander death morale penalty %%: %.1f",
+			c.DeathMoralePenalty,
+		)
+	}
+	if c.Commander != nil {
+		if err := c.Commander.Verify(); err != nil {
+			return errors.Wrap(err, "invalid commander attributes")
+		}
+	}
+	return nil
+}
+
+// Aura represents the bonuses granted by a commander
+type Aura struct {
+	// Morale defines the morale percentage the soldiers regain per action
+	Morale float64
+
+	// HitChance defines the bonus added to the hit chance of the soldiers
+	HitChance float64
+
+	// Radius limits the aura to the soldiers within the given distance
+	// of the commander on a spatial battlefield.
+	// Zero means the entire faction
+	Radius float64
+}
+
+// OrderKind represents the kind of a squad order
+type OrderKind int
+
+const (
+	// OrderAdvance makes the squad seek and attack opponents (default)
+	OrderAdvance OrderKind = iota
+
+	// OrderHold makes the squad hold its position and only attack opponents
+	// within range. On a non-spatial battlefield all opponents are in range
+	OrderHold
+
+	// OrderFocus makes the squad attack the order's target while it's
+	// on the battlefield and seek other opponents afterwards
+	OrderFocus
+)
+
+// String stringifies the order kind
+func (k OrderKind) String() string {
+	switch k {
+	case OrderAdvance:
+		return "advance"
+	case OrderHold:
+		return "hold"
+	case OrderFocus:
+		return "focus"
+	}
+	return "unknown"
+}
+
+// Order represents an order given to a squad
+type Order struct {
+	Kind OrderKind
+
+	// Target is the opponent to attack in case of OrderFocus
+	Target Soldier
+}
+
+// Squad represents a group of soldiers of the same unit led by a leader
+type Squad struct {
+	lock    *sync.Mutex
+	battle  *Battle
+	index   int
+	faction string
+	members []Soldier
+	order   Order
+}
+
+// Index returns the index of the squad within its faction
+func (sq *Squad) Index() int {
+	return sq.index
+}
+
+// Faction returns the name of the faction of the squad
+func (sq *Squad) Faction() string {
+	return sq.faction
+}
+
+// Members returns a copy of the list of all squad members
+// including dead and fled ones
+func (sq *Squad) Members() []Soldier {
+	members := make([]Soldier, len(sq.members))
+	copy(members, sq.members)
+	return members
+}
+
+// Leader returns the leader of the squad which is the first of its members
+// still fighting or nil if no members are left
+func (sq *Squad) Leader() Soldier {
+	for _, member := range sq.members {
+		status := member.Status()
+		if status.Health > 0 && !status.Fled && !status.Routed {
+			return member
+		}
+	}
+	return nil
+}
+
+// Order returns the current order of the squad
+func (sq *Squad) Order() Order {
+	sq.lock.Lock()
+	defer sq.lock.Unlock()
+	return sq.order
+}
+
+// SetOrder gives the squad a new order.
+// The target of a focus order must be hostile toward the squad
+func (sq *Squad) SetOrder(order Order) error {
+	switch order.Kind {
+	case OrderAdvance, OrderHold:
+	case OrderFocus:
+		if order.Target == nil {
+			return errors.New("missing focus target")
+		}
+		sq.battle.lock.Lock()
+		hostile := sq.battle.hostile(sq.faction, order.Target.ID().Faction)
+		sq.battle.lock.Unlock()
+		if !hostile {
+			return errors.Errorf(
+				"focus target %s isn't hostile toward the squad",
+				order.Target.ID(),
+			)
+		}
+	default:
+		return errors.Errorf("invalid order: %d", order.Kind)
+	}
+
+	sq.lock.Lock()
+	defer sq.lock.Unlock()
+	sq.order = order
+	return nil
+}
+
+// focusTarget returns the target of the squad's focus order
+// if it's still on the battlefield
+func (sq *Squad) focusTarget() Soldier {
+	order := sq.Order()
+	if order.Kind != OrderFocus {
+		return nil
+	}
+	status := order.Target.Status()
+	if status.Health <= 0 || status.Fled {
+		return nil
+	}
+	return order.Target
+}
+
+// command represents the command structure of a faction during the battle
+type command struct {
+	battle    *Battle
+	faction   string
+	config    Command
+	commander Soldier
+	squads    []*Squad
+}
+
+// formSquads splits the soldiers of a unit into squads
+// unless the faction has no squads
+func (c *command) formSquads(soldiers []*soldier) {
+	if c.config.SquadSize < 1 {
+		return
+	}
+	var squad *Squad
+	for i, s := range soldiers {
+		if uint(i)%c.config.SquadSize == 0 {
+			squad = &Squad{
+				lock:    &sync.Mutex{},
+				battle:  c.battle,
+				index:   len(c.squads),
+				faction: c.faction,
+				order:   c.config.Order,
+			}
+			c.squads = append(c.squads, squad)
+		}
+		squad.members = append(squad.members, s)
+		s.squad = squad
+	}
+}
+
+// aura returns the aura of the commander affecting the given soldier
+func (c *command) aura(s Soldier, battlefield Battlefield) (Aura, error) {
+	if c == nil || c.commander == nil || c.commander == s {
+		return Aura{}, nil
+	}
+	status := c.commander.Status()
+	if status.Health <= 0 || status.Routed || status.Fled {
+		return Aura{}, nil
+	}
+
+	field, isSpatial := battlefield.(SpatialBattlefield)
+	if !isSpatial || c.config.Aura.Radius <= 0 {
+		return c.config.Aura, nil
+	}
+	commanderPos, err := field.Position(c.commander.ID())
+	if err != nil {
+		return Aura{}, err
+	}
+	pos, err := field.Position(s.ID())
+	if err != nil {
+		return Aura{}, err
+	}
+	if pos.Distance(commanderPos) > c.config.Aura.Radius {
+		return Aura{}, nil
+	}
+	return c.config.Aura, nil
+}
@@ -0,0 +1,90 @@
+package battle
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSetOrder gives orders to a squad
+func TestSetOrder(t *testing.T) {
+	factions := testFactions(4, 50)
+	factions[0].Team = "North"
+	factions[0].Command = Command{SquadSize: 2}
+	factions = append(
+		factions,
+		Faction{
+			Name:              "C",
+			Team:              "North",
+			ArmySize:          2,
+			SoldierAttributes: testAttributes(50),
+		},
+		Faction{
+			Name:              "N",
+			ArmySize:          2,
+			SoldierAttributes: testAttributes(50),
+			Neutral:           true,
+		},
+	)
+	btl := newTestBattle(
+		t,
+		Config{BaseActionDelay: time.Millisecond, Seed: 1},
+		factions...,
+	)
+	squad := btl.Squads("A")[0]
+
+	for _, tc := range []struct {
+		name    string
+		order   Order
+		invalid bool
+	}{
+		{name: "advance", order: Order{Kind: OrderAdvance}},
+		{name: "hold", order: Order{Kind: OrderHold}},
+		{
+			name:  "focus on an opponent",
+			order: Order{Kind: OrderFocus, Target: btl.Soldiers("B")[0]},
+		},
+		{
+			name:    "focus without a target",
+			order:   Order{Kind: OrderFocus},
+			invalid: true,
+		},
+		{
+			name:    "focus on a comrade",
+			order:   Order{Kind: OrderFocus, Target: btl.Soldiers("A")[3]},
+			invalid: true,
+		},
+		{
+			name:    "focus on an ally",
+			order:   Order{Kind: OrderFocus, Target: btl.Soldiers("C")[0]},
+			invalid: true,
+		},
+		{
+			name:    "focus on an unprovoked neutral",
+			order:   Order{Kind: OrderFocus, Target: btl.Soldiers("N")[0]},
+			invalid: true,
+		},
+		{
+			name:    "unknown order",
+			order:   Order{Kind: OrderKind(42)},
+			invalid: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			previous := squad.Order()
+			err := squad.SetOrder(tc.order)
+			switch {
+			case tc.invalid && err == nil:
+				t.Fatal("invalid order accepted")
+			case !tc.invalid && err != nil:
+				t.Fatal(err)
+			}
+			expected := tc.order
+			if tc.invalid {
+				expected = previous
+			}
+			if order := squad.Order(); order != expected {
+				t.Errorf("unexpected order: %v", order)
+			}
+		})
+	}
+}
@@ -10,15 +10,36 @@ import (
 // that allows dynamically changing the interval
 type DynamicTicker struct {
 	c               chan time.Time
+	clock           Clock
 	lock            *sync.Mutex
 	currentTickerID uint64
 	stop            chan struct{}
+
+	// interval, first and since describe the running ticker:
+	// it first ticks after the first interval counted since the given time
+	// and then every interval
+	interval time.Duration
+	first    time.Duration
+	since    time.Time
+
+	// remaining is the time left until the next tick of a paused ticker
+	paused    bool
+	remaining time.Duration
+
+	// sched is only set for tickers driven by a scheduler
+	sched   *scheduler
+	index   uint64
+	ack     chan struct{}
+	pending bool
+	entry   *scheduledTick
 }
 
 // NewDynamicTicker creates a new dynamic ticker instance
-func NewDynamicTicker() *DynamicTicker {
+// ticking according to the given clock
+func NewDynamicTicker(clock Clock) *DynamicTicker {
 	return &DynamicTicker{
 		c:               make(chan time.Time),
+		clock:           clock,
 		lock:            &sync.Mutex{},
 		currentTickerID: 0,
 		stop:            nil,
@@ -27,37 +48,150 @@ func NewDynamicTicker() *DynamicTicker {
 
 // Reset resets the ticker to apply a new interval.
 // If the interval is 0 then the time is stopped until it's reset again.
+// Resetting a paused ticker makes it tick after the new interval
+// once it's resumed.
 // Reset is thread-safe and can safely be called concurrently
 func (tk *DynamicTicker) Reset(newInterval time.Duration) {
-	tk.lock.Lock()
+	tk.resetAfter(newInterval, newInterval)
+}
 
-	// Stop current ticker
-	if tk.stop != nil {
-		tk.stop <- struct{}{}
-		tk.stop = nil
+// resetAfter resets the ticker to first tick after the given delay
+// and then every interval. A zero interval stops the ticker
+func (tk *DynamicTicker) resetAfter(first, interval time.Duration) {
+	if interval == 0 {
+		first = 0
 	}
+	if tk.sched != nil {
+		tk.sched.reset(tk, first, interval)
+		return
+	}
+
+	tk.lock.Lock()
+	defer tk.lock.Unlock()
 
-	if newInterval == 0 {
+	tk.stopTicking()
+	tk.interval = interval
+	if interval == 0 {
 		// Stop
-		tk.lock.Unlock()
+		tk.remaining = 0
+		return
+	}
+	if tk.paused {
+		tk.remaining = first
+		return
+	}
+	tk.startTicking(first)
+}
+
+// timer returns the time left until the next tick and the interval
+// of the ticker and false if the ticker is stopped
+func (tk *DynamicTicker) timer() (
+	remaining time.Duration,
+	interval time.Duration,
+	running bool,
+) {
+	if tk.sched != nil {
+		return tk.sched.timer(tk)
+	}
+
+	tk.lock.Lock()
+	defer tk.lock.Unlock()
+
+	switch {
+	case tk.paused:
+		return tk.remaining, tk.interval, tk.interval > 0
+	case tk.stop == nil:
+		return 0, 0, false
+	}
+	return tk.timeLeft(), tk.interval, true
+}
+
+// Pause freezes the ticker keeping the time left until its next tick.
+// Tickers driven by a scheduler are frozen by pausing the scheduler,
+// for them Pause is a no-op.
+// Pause is thread-safe and can safely be called concurrently
+func (tk *DynamicTicker) Pause() {
+	if tk.sched != nil {
 		return
 	}
 
-	// Start a new ticker
+	tk.lock.Lock()
+	defer tk.lock.Unlock()
+
+	if tk.paused {
+		return
+	}
+	tk.paused = true
+	if tk.stop == nil {
+		// The ticker is stopped
+		tk.remaining = 0
+		return
+	}
+
+	tk.remaining = tk.timeLeft()
+	tk.stopTicking()
+}
+
+// Resume continues a paused ticker ticking after the time
+// that was left when it was paused.
+// Resume is thread-safe and can safely be called concurrently
+func (tk *DynamicTicker) Resume() {
+	if tk.sched != nil {
+		return
+	}
+
+	tk.lock.Lock()
+	defer tk.lock.Unlock()
+
+	if !tk.paused {
+		return
+	}
+	tk.paused = false
+	if tk.interval > 0 {
+		tk.startTicking(tk.remaining)
+		tk.remaining = 0
+	}
+}
+
+// timeLeft returns the time left until the next tick of the running ticker.
+// Expects the ticker lock to be locked
+func (tk *DynamicTicker) timeLeft() time.Duration {
+	elapsed := tk.clock.Now().Sub(tk.since)
+	if elapsed < tk.first {
+		return tk.first - elapsed
+	}
+	return tk.interval - (elapsed-tk.first)%tk.interval
+}
+
+// stopTicking stops the current ticker goroutine.
+// Expects the ticker lock to be locked
+func (tk *DynamicTicker) stopTicking() {
+	if tk.stop != nil {
+		tk.stop <- struct{}{}
+		tk.stop = nil
+	}
+}
+
+// startTicking starts a new ticker goroutine first ticking after
+// the given delay and then every interval.
+// Expects the ticker lock to be locked
+func (tk *DynamicTicker) startTicking(first time.Duration) {
 	tickerID := atomic.AddUint64(&tk.currentTickerID, 1)
-	ticker := time.NewTicker(newInterval)
 	stop := make(chan struct{})
 	tk.stop = stop
+	tk.first = first
+	tk.since = tk.clock.Now()
 
-	tk.lock.Unlock()
-
+	interval := tk.interval
 	go func() {
+		delay := first
 		for {
+			timer := tk.clock.NewTimer(delay)
 			select {
 			case <-stop:
-				ticker.Stop()
+				timer.Stop()
 				return
-			case tm := <-ticker.C:
+			case tm := <-timer.C():
 				if tickerID != atomic.LoadUint64(&tk.currentTickerID) {
 					// Avoid firing the tick because this ticker was canceled
 					return
@@ -69,10 +203,24 @@ func (tk *DynamicTicker) Reset(newInterval time.Duration) {
 				default:
 				}
 			}
+			delay = interval
 		}
 	}()
 }
 
+// Ack acknowledges that the last received tick was processed.
+// Tickers driven by a scheduler won't let the scheduler deliver any other tick
+// until the current one is acknowledged, for all other tickers Ack is a no-op
+func (tk *DynamicTicker) Ack() {
+	if tk.ack == nil {
+		return
+	}
+	select {
+	case tk.ack <- struct{}{}:
+	default:
+	}
+}
+
 // C returns the ticker channel
 func (tk *DynamicTicker) C() <-chan time.Time {
 	return tk.c
@@ -0,0 +1,203 @@
+package battle
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Weapon represents the weapon a soldier attacks with
+type Weapon struct {
+	Name string
+
+	// DamageType is the type of damage dealt such as "slashing"
+	// or "piercing" (see Armor)
+	DamageType string
+
+	// DamageMin and DamageMax define the damage added
+	// to the attack strength of the soldier
+	DamageMin float64
+	DamageMax float64
+
+	// Range replaces the attack range of the soldier
+	// on a spatial battlefield unless zero
+	Range float64
+
+	// AttackSpeed multiplies the rate of the soldier's actions.
+	// A speed of 2 halves the delay between the actions while
+	// zero leaves it unchanged
+	AttackSpeed float64
+
+	// Effects defines the status effects the hits of the weapon
+	// can inflict
+	Effects []StatusEffect
+}
+
+// Verify returns an error if the weapon is invalid
+func (w *Weapon) Verify() error {
+	if w.DamageMin < 0 || w.DamageMin > w.DamageMax {
+		return errors.Errorf(
+			"invalid damage range: %.1f - %.1f",
+			w.DamageMin,
+			w.DamageMax,
+		)
+	}
+	if w.Range < 0 {
+		return errors.Errorf("invalid range: %.1f", w.Range)
+	}
+	if w.AttackSpeed < 0 {
+		return errors.Errorf("invalid attack speed: %.2f", w.AttackSpeed)
+	}
+	for _, effect := range w.Effects {
+		if err := effect.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Protection represents the protection of an armor
+// against a type of damage
+type Protection struct {
+	// Flat is subtracted from the damage taken
+	Flat float64
+
+	// Percentage reduces the damage remaining after the flat reduction
+	Percentage float64
+}
+
+// Armor represents the armor a soldier wears
+type Armor struct {
+	Name string
+
+	// Protection maps damage types to the protection against them.
+	// The empty damage type defines the protection against
+	// the damage of unarmed soldiers and weapons of an undefined type
+	Protection map[string]Protection
+}
+
+// mitigate returns the damage left after the armor's protection
+// against the given type of damage
+func (a *Armor) mitigate(damage float64, damageType string) float64 {
+	protection := a.Protection[damageType]
+	damage = math.Max(damage-protection.Flat, 0)
+	return damage * (1 - protection.Percentage)
+}
+
+// Verify returns an error if the armor is invalid
+func (a *Armor) Verify() error {
+	for damageType, protection := range a.Protection {
+		if protection.Flat < 0 {
+			return errors.Errorf(
+				"invalid flat protection against '%s': %.1f",
+				damageType,
+				protection.Flat,
+			)
+		}
+		if protection.Percentage < 0 || protection.Percentage > 1 {
+			return errors.Errorf(
+				"invalid protection %% against '%s': %.2f",
+				damageType,
+				protection.Percentage,
+			)
+		}
+	}
+	return nil
+}
+
+// Shield represents the shield a soldier carries
+type Shield struct {
+	Name string
+
+	// BlockChance defines the chance to block an attack
+	// that wasn't dodged
+	BlockChance float64
+}
+
+// Verify returns an error if the shield is invalid
+func (s *Shield) Verify() error {
+	if s.BlockChance < 0 || s.BlockChance > 1 {
+		return errors.Errorf("invalid block chance: %.2f", s.BlockChance)
+	}
+	return nil
+}
+
+// Equipment represents the equipment a soldier carries.
+// Soldiers without a weapon fight unarmed and deal untyped damage
+type Equipment struct {
+	Weapon *Weapon
+	Armor  *Armor
+	Shield *Shield
+}
+
+// Verify returns an error if any item of the equipment is invalid
+func (e *Equipment) Verify() error {
+	if e.Weapon != nil {
+		if err := e.Weapon.Verify(); err != nil {
+			return errors.Wrapf(err, "invalid weapon '%s'", e.Weapon.Name)
+		}
+	}
+	if e.Armor != nil {
+		if err := e.Armor.Verify(); err != nil {
+			return errors.Wrapf(err, "invalid armor '%s'", e.Armor.Name)
+		}
+	}
+	if e.Shield != nil {
+		if err := e.Shield.Verify(); err != nil {
+			return errors.Wrapf(err, "invalid shield '%s'", e.Shield.Name)
+		}
+	}
+	return nil
+}
+
+// Loadout represents a possible equipment of the soldiers of a unit
+type Loadout struct {
+	Equipment
+
+	// Weight defines the relative share of the soldiers
+	// carrying the loadout
+	Weight float64
+}
+
+// verifyLoadouts returns an error if any of the loadouts is invalid
+func verifyLoadouts(loadouts []Loadout) error {
+	total := 0.0
+	for i, loadout := range loadouts {
+		if loadout.Weight < 0 {
+			return errors.Errorf(
+				"invalid weight of loadout %d: %.2f",
+				i,
+				loadout.Weight,
+			)
+		}
+		if err := loadout.Equipment.Verify(); err != nil {
+			return errors.Wrapf(err, "invalid loadout %d", i)
+		}
+		total += loadout.Weight
+	}
+	if len(loadouts) > 0 && total <= 0 {
+		return errors.New("loadouts have no weight")
+	}
+	return nil
+}
+
+// rollLoadout selects one of the loadouts randomly by their weights.
+// Returns no equipment if no loadouts are defined
+func rollLoadout(loadouts []Loadout, r *rng) Equipment {
+	total := 0.0
+	for _, loadout := range loadouts {
+		total += loadout.Weight
+	}
+	if total <= 0 {
+		return Equipment{}
+	}
+
+	roll := r.random(0, total)
+	for _, loadout := range loadouts {
+		if roll < loadout.Weight {
+			return loadout.Equipment
+		}
+		roll -= loadout.Weight
+	}
+	return loadouts[len(loadouts)-1].Equipment
+}
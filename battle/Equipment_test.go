@@ -0,0 +1,93 @@
+package battle
+
+import "testing"
+
+// TestProtection makes sure armors reduce and shields block
+// the damage taken
+func TestProtection(t *testing.T) {
+	armor := &Armor{Name: "mail", Protection: map[string]Protection{
+		"":         {Flat: 1},
+		"slashing": {Flat: 2, Percentage: .5},
+	}}
+	shield := &Shield{Name: "pavise", BlockChance: 1}
+	sword := &Weapon{Name: "sword", DamageType: "slashing"}
+	spear := &Weapon{Name: "spear", DamageType: "piercing"}
+
+	for _, tc := range []struct {
+		name     string
+		weapon   *Weapon
+		defense  Equipment
+		expected float64
+		err      error
+	}{
+		{name: "unprotected", weapon: sword, expected: 14},
+		{
+			name:     "armor",
+			weapon:   sword,
+			defense:  Equipment{Armor: armor},
+			expected: 6,
+		},
+		{
+			name:     "armor against unarmed",
+			defense:  Equipment{Armor: armor},
+			expected: 13,
+		},
+		{
+			name:     "armor against another damage type",
+			weapon:   spear,
+			defense:  Equipment{Armor: armor},
+			expected: 14,
+		},
+		{
+			name:    "shield",
+			weapon:  sword,
+			defense: Equipment{Armor: armor, Shield: shield},
+			err:     ErrBlocked,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := testAttributes(50)
+			attrs.DodgeChanceMin = 0
+			attrs.DodgeChanceMax = 0
+			btl := newTestBattle(
+				t,
+				Config{Seed: 1},
+				Faction{
+					Name:              "A",
+					ArmySize:          1,
+					SoldierAttributes: attrs,
+					Loadouts: []Loadout{{
+						Equipment: Equipment{Weapon: tc.weapon},
+						Weight:    1,
+					}},
+				},
+				Faction{
+					Name:              "B",
+					ArmySize:          1,
+					SoldierAttributes: attrs,
+					Loadouts:          []Loadout{{Equipment: tc.defense, Weight: 1}},
+				},
+			)
+			attacker, defender := btl.Soldiers("A")[0], btl.Soldiers("B")[0]
+
+			damage, _, err := defender.TakeDamage(attacker, 14)
+			if err != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if damage != tc.expected {
+				t.Errorf("took %.2f damage, expected %.2f", damage, tc.expected)
+			}
+			if health := defender.Status().Health; health != 50-tc.expected {
+				t.Errorf("%.2f health left", health)
+			}
+
+			stats := defender.Stats()
+			if tc.err == nil && stats.DamageMitigated != 14-tc.expected {
+				t.Errorf("%.2f damage mitigated", stats.DamageMitigated)
+			}
+			if blocked := tc.err == ErrBlocked; blocked != (stats.Blocks == 1) {
+				t.Errorf("%d attacks blocked", stats.Blocks)
+			}
+		})
+	}
+}
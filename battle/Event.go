@@ -10,69 +10,376 @@ type EventDodge struct {
 	Attacker      Soldier
 	Defernder     Soldier
 	MoralePenalty float64
+
+	// AttackerStamina and DefenderStamina represent the stamina
+	// of the soldiers after the attack
+	AttackerStamina float64
+	DefenderStamina float64
 }
 
 // String turns the event into a message
 func (ev EventDodge) String() string {
 	return fmt.Sprintf(
-		"%s dodged an attack of %s (morale penalty for the attacker: %.1f%%)",
+		"%s dodged an attack of %s (morale penalty for the attacker: %.1f%%, "+
+			"defender stamina: %.1f%%, attacker stamina: %.1f%%)",
 		ev.Defernder.ID(),
 		ev.Attacker.ID(),
 		ev.MoralePenalty*100,
+		ev.DefenderStamina*100,
+		ev.AttackerStamina*100,
+	)
+}
+
+// EventBlock represents an event describing an attack
+// blocked by the shield of the defender
+type EventBlock struct {
+	Attacker        Soldier
+	Defender        Soldier
+	MoralePenalty   float64
+	AttackerStamina float64
+}
+
+// String turns the event into a message
+func (ev EventBlock) String() string {
+	return fmt.Sprintf(
+		"%s blocked an attack of %s (morale penalty for the attacker: %.1f%%, "+
+			"attacker stamina: %.1f%%)",
+		ev.Defender.ID(),
+		ev.Attacker.ID(),
+		ev.MoralePenalty*100,
+		ev.AttackerStamina*100,
 	)
 }
 
 // EventMiss represents an event describing an unsuccessful attack
 type EventMiss struct {
-	Attacker      Soldier
-	Attacked      Soldier
-	MoralePenalty float64
+	Attacker        Soldier
+	Attacked        Soldier
+	MoralePenalty   float64
+	AttackerStamina float64
 }
 
 // String turns the event into a message
 func (ev EventMiss) String() string {
 	return fmt.Sprintf(
-		"%s missed when trying to attack %s (morale penalty: %.1f%%)",
+		"%s missed when trying to attack %s "+
+			"(morale penalty: %.1f%%, stamina: %.1f%%)",
 		ev.Attacker.ID(),
 		ev.Attacked.ID(),
 		ev.MoralePenalty*100,
+		ev.AttackerStamina*100,
 	)
 }
 
 // EventHit represents an event describing a successful attack
 type EventHit struct {
-	Attacker    Soldier
-	Attacked    Soldier
-	DamageDealt float64
-	MoraleBonus float64
+	Attacker        Soldier
+	Attacked        Soldier
+	DamageDealt     float64
+	MoraleBonus     float64
+	AttackerStamina float64
+	Critical        bool
 }
 
 // String turns the event into a message
 func (ev EventHit) String() string {
 	return fmt.Sprintf(
-		"%s hit and dealt %.1f damage to %s (morale bonus: %.1f%%)",
+		"%s %s and dealt %.1f damage to %s "+
+			"(morale bonus: %.1f%%, stamina: %.1f%%)",
 		ev.Attacker.ID(),
+		hitVerb(ev.Critical),
 		ev.DamageDealt,
 		ev.Attacked.ID(),
 		ev.MoraleBonus*100,
+		ev.AttackerStamina*100,
 	)
 }
 
+// hitVerb describes either a regular or a critical hit
+func hitVerb(critical bool) string {
+	if critical {
+		return "critically hit"
+	}
+	return "hit"
+}
+
 // EventKill represents an event describing a kill
 type EventKill struct {
-	Attacker    Soldier
-	Killed      Soldier
-	DamageDealt float64
-	MoraleBonus float64
+	Attacker        Soldier
+	Killed          Soldier
+	DamageDealt     float64
+	MoraleBonus     float64
+	AttackerStamina float64
+	Critical        bool
 }
 
 // String turns the event into a message
 func (ev EventKill) String() string {
 	return fmt.Sprintf(
-		"%s hit, dealt %.1f damage and killed %s (morale bonus: %.1f%%)",
+		"%s %s, dealt %.1f damage and killed %s "+
+			"(morale bonus: %.1f%%, stamina: %.1f%%)",
 		ev.Attacker.ID(),
+		hitVerb(ev.Critical),
 		ev.DamageDealt,
 		ev.Killed.ID(),
 		ev.MoraleBonus*100,
+		ev.AttackerStamina*100,
+	)
+}
+
+// EventMove represents an event describing a soldier moving
+// toward an opponent on a spatial battlefield
+type EventMove struct {
+	Soldier Soldier
+	Target  Soldier
+	From    Position
+	To      Position
+}
+
+// String turns the event into a message
+func (ev EventMove) String() string {
+	if ev.Target == nil {
+		return fmt.Sprintf(
+			"%s fled from %s to %s",
+			ev.Soldier.ID(),
+			ev.From,
+			ev.To,
+		)
+	}
+	return fmt.Sprintf(
+		"%s moved from %s to %s toward %s",
+		ev.Soldier.ID(),
+		ev.From,
+		ev.To,
+		ev.Target.ID(),
+	)
+}
+
+// EventRout represents an event describing a soldier whose morale broke
+type EventRout struct {
+	Soldier Soldier
+	Morale  float64
+}
+
+// String turns the event into a message
+func (ev EventRout) String() string {
+	return fmt.Sprintf(
+		"%s routed (morale: %.1f%%)",
+		ev.Soldier.ID(),
+		ev.Morale*100,
+	)
+}
+
+// EventRally represents an event describing a routed soldier
+// returning to the fight
+type EventRally struct {
+	Soldier Soldier
+	Morale  float64
+}
+
+// String turns the event into a message
+func (ev EventRally) String() string {
+	return fmt.Sprintf(
+		"%s rallied (morale: %.1f%%)",
+		ev.Soldier.ID(),
+		ev.Morale*100,
+	)
+}
+
+// EventFlee represents an event describing a routed soldier
+// leaving the battlefield
+type EventFlee struct {
+	Soldier Soldier
+}
+
+// String turns the event into a message
+func (ev EventFlee) String() string {
+	return fmt.Sprintf("%s fled the battlefield", ev.Soldier.ID())
+}
+
+// EventCommanderKilled represents an event describing the death
+// of a faction's commander shocking the faction's morale
+type EventCommanderKilled struct {
+	Attacker      Soldier
+	Commander     Soldier
+	MoralePenalty float64
+}
+
+// String turns the event into a message
+func (ev EventCommanderKilled) String() string {
+	return fmt.Sprintf(
+		"%s killed the commander %s (morale penalty for the faction: %.1f%%)",
+		ev.Attacker.ID(),
+		ev.Commander.ID(),
+		ev.MoralePenalty*100,
+	)
+}
+
+// EventReinforcementsArrived represents an event describing the arrival
+// of a wave of reinforcements
+type EventReinforcementsArrived struct {
+	Faction  string
+	Wave     int
+	Soldiers []Soldier
+}
+
+// String turns the event into a message
+func (ev EventReinforcementsArrived) String() string {
+	return fmt.Sprintf(
+		"%d reinforcements of faction %s arrived (wave %d)",
+		len(ev.Soldiers),
+		ev.Faction,
+		ev.Wave,
+	)
+}
+
+// EventAllianceBroken represents an event describing a faction
+// leaving its team
+type EventAllianceBroken struct {
+	Faction string
+	Team    string
+}
+
+// String turns the event into a message
+func (ev EventAllianceBroken) String() string {
+	return fmt.Sprintf(
+		"Faction %s broke the alliance with team %s",
+		ev.Faction,
+		ev.Team,
+	)
+}
+
+// EventHeal represents an event describing a medic healing an ally
+type EventHeal struct {
+	Healer  Soldier
+	Healed  Soldier
+	Healing float64
+}
+
+// String turns the event into a message
+func (ev EventHeal) String() string {
+	return fmt.Sprintf(
+		"%s healed %s by %.1f health-points",
+		ev.Healer.ID(),
+		ev.Healed.ID(),
+		ev.Healing,
+	)
+}
+
+// EventEffectApplied represents an event describing a status effect
+// inflicted on a soldier
+type EventEffectApplied struct {
+	Soldier Soldier
+	Source  Soldier
+	Effect  StatusEffect
+}
+
+// String turns the event into a message
+func (ev EventEffectApplied) String() string {
+	return fmt.Sprintf(
+		"%s inflicted %s on %s (%d ticks)",
+		ev.Source.ID(),
+		ev.Effect.Kind,
+		ev.Soldier.ID(),
+		ev.Effect.Ticks,
+	)
+}
+
+// EventEffectTick represents an event describing a tick
+// of a status effect affecting a soldier
+type EventEffectTick struct {
+	Soldier Soldier
+	Effect  EffectKind
+
+	// Magnitude represents the damage dealt by bleeding
+	// and the morale percentage drained by fear
+	Magnitude float64
+	TicksLeft uint
+	Killed    bool
+}
+
+// String turns the event into a message
+func (ev EventEffectTick) String() string {
+	switch {
+	case ev.Killed:
+		return fmt.Sprintf(
+			"%s took %.1f damage from %s and died",
+			ev.Soldier.ID(),
+			ev.Magnitude,
+			ev.Effect,
+		)
+	case ev.Effect == EffectBleeding:
+		return fmt.Sprintf(
+			"%s took %.1f damage from bleeding (%d ticks left)",
+			ev.Soldier.ID(),
+			ev.Magnitude,
+			ev.TicksLeft,
+		)
+	case ev.Effect == EffectFear:
+		return fmt.Sprintf(
+			"%s lost %.1f%% morale from fear (%d ticks left)",
+			ev.Soldier.ID(),
+			ev.Magnitude*100,
+			ev.TicksLeft,
+		)
+	}
+	return fmt.Sprintf(
+		"%s is affected by %s (%d ticks left)",
+		ev.Soldier.ID(),
+		ev.Effect,
+		ev.TicksLeft,
+	)
+}
+
+// EventEffectExpired represents an event describing the expiry
+// of a status effect affecting a soldier
+type EventEffectExpired struct {
+	Soldier Soldier
+	Effect  EffectKind
+}
+
+// String turns the event into a message
+func (ev EventEffectExpired) String() string {
+	return fmt.Sprintf(
+		"%s is no longer affected by %s",
+		ev.Soldier.ID(),
+		ev.Effect,
+	)
+}
+
+// EventEffectKill represents an event describing a soldier killed
+// by a status effect inflicted by the source
+type EventEffectKill struct {
+	Source      Soldier
+	Killed      Soldier
+	Effect      EffectKind
+	DamageDealt float64
+}
+
+// String turns the event into a message
+func (ev EventEffectKill) String() string {
+	return fmt.Sprintf(
+		"%s killed %s by %s",
+		ev.Source.ID(),
+		ev.Killed.ID(),
+		ev.Effect,
+	)
+}
+
+// EventLevelUp represents an event describing a soldier reaching
+// a new veterancy level
+type EventLevelUp struct {
+	Soldier    Soldier
+	Level      uint
+	Experience float64
+}
+
+// String turns the event into a message
+func (ev EventLevelUp) String() string {
+	return fmt.Sprintf(
+		"%s reached level %d (%.1f experience)",
+		ev.Soldier.ID(),
+		ev.Level,
+		ev.Experience,
 	)
 }
@@ -0,0 +1,301 @@
+package battle
+
+import (
+	"github.com/pkg/errors"
+)
+
+// SpatialBattlefield represents a battlefield on which the soldiers have
+// a position and can only attack opponents within their attack range
+type SpatialBattlefield interface {
+	Battlefield
+
+	// Position returns the current position of a soldier
+	Position(soldier SoldierID) (Position, error)
+
+	// Approach moves the soldier toward the opponent by at most the given
+	// distance unless the opponent already is within the attack range.
+	// Returns the positions before and after the movement
+	// and whether the opponent is within the attack range
+	Approach(
+		soldier Soldier,
+		opponent Soldier,
+		maxDistance float64,
+		attackRange float64,
+	) (
+		from Position,
+		to Position,
+		inRange bool,
+		err error,
+	)
+
+	// Retreat moves the soldier toward the nearest edge of the battlefield
+	// by at most the given distance. Returns the positions before and after
+	// the movement and whether the soldier reached the edge
+	Retreat(soldier Soldier, maxDistance float64) (
+		from Position,
+		to Position,
+		reachedEdge bool,
+		err error,
+	)
+}
+
+// rangeTolerance is the distance by which an opponent may exceed
+// the attack range due to rounding errors of the movements
+const rangeTolerance = 1e-9
+
+// fieldBattlefield implements the SpatialBattlefield interface
+// on a continuous 2D plane
+type fieldBattlefield struct {
+	battle    *Battle
+	field     Field
+	positions map[SoldierID]Position
+}
+
+// verifyMobility returns an error if any soldier of the faction
+// including its commander and reinforcements can't move
+func verifyMobility(faction Faction) error {
+	for _, unit := range faction.units() {
+		if err := unit.SoldierAttributes.verifyMobility(); err != nil {
+			return errors.Wrapf(err, "invalid attributes of unit '%s'", unit.Type)
+		}
+	}
+	if commander := faction.Command.Commander; commander != nil {
+		if err := commander.verifyMobility(); err != nil {
+			return errors.Wrap(err, "invalid commander attributes")
+		}
+	}
+	for i, w := range faction.Reinforcements {
+		for _, unit := range w.units() {
+			if err := unit.SoldierAttributes.verifyMobility(); err != nil {
+				return errors.Wrapf(
+					err,
+					"invalid attributes of unit '%s' of wave %d",
+					unit.Type,
+					i,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// newFieldBattlefield creates a new spatial battlefield of the given battle
+func newFieldBattlefield(battle *Battle, field Field) (
+	*fieldBattlefield,
+	error,
+) {
+	if field.Width <= 0 || field.Height <= 0 {
+		return nil, errors.Errorf(
+			"invalid field dimensions: %.1fx%.1f",
+			field.Width,
+			field.Height,
+		)
+	}
+	return &fieldBattlefield{
+		battle:    battle,
+		field:     field,
+		positions: make(map[SoldierID]Position),
+	}, nil
+}
+
+// deploy places a soldier at a random position within the zone
+func (f *fieldBattlefield) deploy(soldier SoldierID, zone Zone) error {
+	if zone.Min.X > zone.Max.X || zone.Min.Y > zone.Max.Y {
+		return errors.Errorf("invalid deployment zone: %v", zone)
+	}
+	fieldZone := f.field.Zone()
+	if !fieldZone.Contains(zone.Min) || !fieldZone.Contains(zone.Max) {
+		return errors.Errorf(
+			"deployment zone %v exceeds the field (%.1fx%.1f)",
+			zone,
+			f.field.Width,
+			f.field.Height,
+		)
+	}
+
+	f.battle.lock.Lock()
+	defer f.battle.lock.Unlock()
+	f.positions[soldier] = Position{
+		X: f.battle.rng.random(zone.Min.X, zone.Max.X),
+		Y: f.battle.rng.random(zone.Min.Y, zone.Max.Y),
+	}
+	return nil
+}
+
+// FindOpponent implements the Battlefield interface.
+// Returns the nearest opponent unless the faction of the soldier
+// defines a targeting strategy
+func (f *fieldBattlefield) FindOpponent(soldier Soldier) (Soldier, error) {
+	id := soldier.ID()
+	if strategy := f.battle.targeting[id.Faction]; strategy != nil {
+		return f.battle.selectTarget(soldier, strategy)
+	}
+
+	f.battle.lock.Lock()
+	defer f.battle.lock.Unlock()
+
+	own, known := f.positions[id]
+	if !known {
+		return nil, errors.Errorf("unknown soldier %s", id)
+	}
+
+	var nearest Soldier
+	nearestDistance := 0.0
+	for _, factionName := range f.battle.factions {
+		if !f.battle.hostile(id.Faction, factionName) {
+			continue
+		}
+		for _, opponents := range [][]Soldier{
+			f.battle.alive[factionName],
+			f.battle.routed[factionName],
+		} {
+			for _, opponent := range opponents {
+				distance := own.Distance(f.positions[opponent.ID()])
+				if nearest == nil || distance < nearestDistance {
+					nearest = opponent
+					nearestDistance = distance
+				}
+			}
+		}
+	}
+
+	if nearest == nil {
+		return nil, ErrNoMoreOpponents
+	}
+	return nearest, nil
+}
+
+// FindAlly implements the Battlefield interface.
+// Returns the nearest wounded ally
+func (f *fieldBattlefield) FindAlly(soldier Soldier) (Soldier, error) {
+	own, err := f.Position(soldier.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	// The allies are copied to not hold the lock
+	// while inspecting their status
+	var nearest Soldier
+	nearestDistance := 0.0
+	for _, ally := range f.battle.allies(soldier) {
+		status := ally.Status()
+		if status.Health >= status.MaxHealth {
+			continue
+		}
+		pos, err := f.Position(ally.ID())
+		if err != nil {
+			return nil, err
+		}
+		distance := own.Distance(pos)
+		if nearest == nil || distance < nearestDistance {
+			nearest = ally
+			nearestDistance = distance
+		}
+	}
+
+	if nearest == nil {
+		return nil, ErrNoWoundedAllies
+	}
+	return nearest, nil
+}
+
+// MarkDead implements the Battlefield interface
+func (f *fieldBattlefield) MarkDead(soldier Soldier) error {
+	return f.battle.MarkDead(soldier)
+}
+
+// MarkRouted implements the Battlefield interface
+func (f *fieldBattlefield) MarkRouted(soldier Soldier) error {
+	return f.battle.MarkRouted(soldier)
+}
+
+// MarkRallied implements the Battlefield interface
+func (f *fieldBattlefield) MarkRallied(soldier Soldier) error {
+	return f.battle.MarkRallied(soldier)
+}
+
+// MarkFled implements the Battlefield interface
+func (f *fieldBattlefield) MarkFled(soldier Soldier) error {
+	return f.battle.MarkFled(soldier)
+}
+
+// Position implements the SpatialBattlefield interface
+func (f *fieldBattlefield) Position(soldier SoldierID) (Position, error) {
+	f.battle.lock.Lock()
+	defer f.battle.lock.Unlock()
+
+	pos, known := f.positions[soldier]
+	if !known {
+		return Position{}, errors.Errorf("unknown soldier %s", soldier)
+	}
+	return pos, nil
+}
+
+// Approach implements the SpatialBattlefield interface
+func (f *fieldBattlefield) Approach(
+	soldier Soldier,
+	opponent Soldier,
+	maxDistance float64,
+	attackRange float64,
+) (
+	from Position,
+	to Position,
+	inRange bool,
+	err error,
+) {
+	f.battle.lock.Lock()
+	defer f.battle.lock.Unlock()
+
+	from, known := f.positions[soldier.ID()]
+	if !known {
+		return from, from, false, errors.Errorf(
+			"unknown soldier %s",
+			soldier.ID(),
+		)
+	}
+	target, known := f.positions[opponent.ID()]
+	if !known {
+		return from, from, false, errors.Errorf(
+			"unknown opponent %s",
+			opponent.ID(),
+		)
+	}
+
+	distance := from.Distance(target)
+	if distance <= attackRange+rangeTolerance {
+		return from, from, true, nil
+	}
+
+	if maxDistance >= distance-attackRange {
+		// Stop exactly at the attack range
+		to = target.MoveToward(from, attackRange)
+	} else {
+		to = from.MoveToward(target, maxDistance)
+	}
+	f.positions[soldier.ID()] = to
+	return from, to, false, nil
+}
+
+// Retreat implements the SpatialBattlefield interface
+func (f *fieldBattlefield) Retreat(soldier Soldier, maxDistance float64) (
+	from Position,
+	to Position,
+	reachedEdge bool,
+	err error,
+) {
+	f.battle.lock.Lock()
+	defer f.battle.lock.Unlock()
+
+	from, known := f.positions[soldier.ID()]
+	if !known {
+		return from, from, false, errors.Errorf(
+			"unknown soldier %s",
+			soldier.ID(),
+		)
+	}
+
+	edge := f.field.nearestEdge(from)
+	to = from.MoveToward(edge, maxDistance)
+	f.positions[soldier.ID()] = to
+	return from, to, to == edge, nil
+}
@@ -0,0 +1,120 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TestImmobileSoldiers makes sure soldiers unable to move
+// are rejected on a spatial battlefield
+func TestImmobileSoldiers(t *testing.T) {
+	mobile := testAttributes(50)
+	mobile.MovementSpeed = 1
+	mobile.AttackRange = 1
+	immobile := mobile
+	immobile.MovementSpeed = 0
+
+	for _, tc := range []struct {
+		name    string
+		field   *Field
+		faction func(faction *Faction)
+		invalid bool
+	}{
+		{
+			name:  "mobile soldiers",
+			field: &Field{Width: 10, Height: 10},
+		},
+		{
+			name: "immobile soldiers without a field",
+			faction: func(faction *Faction) {
+				faction.SoldierAttributes = immobile
+			},
+		},
+		{
+			name:  "immobile soldiers",
+			field: &Field{Width: 10, Height: 10},
+			faction: func(faction *Faction) {
+				faction.SoldierAttributes = immobile
+			},
+			invalid: true,
+		},
+		{
+			name:  "immobile commander",
+			field: &Field{Width: 10, Height: 10},
+			faction: func(faction *Faction) {
+				faction.Command.Commander = &immobile
+			},
+			invalid: true,
+		},
+		{
+			name:  "immobile reinforcements",
+			field: &Field{Width: 10, Height: 10},
+			faction: func(faction *Faction) {
+				faction.Reinforcements = []Wave{{
+					Size:              2,
+					SoldierAttributes: immobile,
+					Delay:             time.Second,
+				}}
+			},
+			invalid: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factions := []Faction{
+				{Name: "A", ArmySize: 2, SoldierAttributes: mobile},
+				{Name: "B", ArmySize: 2, SoldierAttributes: mobile},
+			}
+			if tc.faction != nil {
+				tc.faction(&factions[1])
+			}
+			_, err := NewBattle(Config{
+				BaseActionDelay: time.Millisecond,
+				Seed:            1,
+				Field:           tc.field,
+			}, factions...)
+			if !tc.invalid {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			attrErr, isAttrErr := errors.Cause(err).(*AttributeError)
+			if !isAttrErr || attrErr.Attribute != "MovementSpeed" {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestFieldBattleEnds makes sure soldiers approaching their opponents
+// end up within range and decide battles on a spatial battlefield
+func TestFieldBattleEnds(t *testing.T) {
+	var field testScenario
+	for _, scenario := range testScenarios() {
+		if scenario.name == "field" {
+			field = scenario
+		}
+	}
+	for _, delay := range []time.Duration{
+		time.Millisecond,
+		2 * time.Millisecond,
+		3 * time.Millisecond,
+		time.Second,
+	} {
+		t.Run(delay.String(), func(t *testing.T) {
+			config := field.config
+			config.BaseActionDelay = delay
+			config.Clock = NewVirtualClock(time.Unix(0, 0))
+			btl := newTestBattle(t, config, field.factions()...)
+			result := awaitResult(t, runAsync(context.Background(), btl))
+			switch result.Outcome {
+			case OutcomeVictory, OutcomeDraw:
+			default:
+				t.Fatalf("unexpected outcome: %s", result.Outcome)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package battle
+
+import (
+	"fmt"
+	"math"
+)
+
+// Position represents a point on a 2D battlefield
+type Position struct {
+	X float64
+	Y float64
+}
+
+// Distance returns the euclidean distance to the given position
+func (pos Position) Distance(to Position) float64 {
+	return math.Hypot(to.X-pos.X, to.Y-pos.Y)
+}
+
+// MoveToward returns the position reached after moving
+// toward the target by at most the given distance
+func (pos Position) MoveToward(target Position, distance float64) Position {
+	total := pos.Distance(target)
+	if total <= distance || total == 0 {
+		return target
+	}
+	ratio := distance / total
+	return Position{
+		X: pos.X + (target.X-pos.X)*ratio,
+		Y: pos.Y + (target.Y-pos.Y)*ratio,
+	}
+}
+
+// String stringifies the position
+func (pos Position) String() string {
+	return fmt.Sprintf("(%.1f, %.1f)", pos.X, pos.Y)
+}
+
+// Zone represents a rectangular area on a 2D battlefield
+type Zone struct {
+	Min Position
+	Max Position
+}
+
+// Contains returns true if the position is inside the zone
+func (zone Zone) Contains(pos Position) bool {
+	return pos.X >= zone.Min.X && pos.X <= zone.Max.X &&
+		pos.Y >= zone.Min.Y && pos.Y <= zone.Max.Y
+}
+
+// Field represents the dimensions of a 2D battlefield
+// spanning from (0, 0) to (Width, Height)
+type Field struct {
+	Width  float64
+	Height float64
+}
+
+// Zone returns the zone covering the entire field
+func (field Field) Zone() Zone {
+	return Zone{Max: Position{X: field.Width, Y: field.Height}}
+}
+
+// nearestEdge returns the nearest position on the edge of the field
+func (field Field) nearestEdge(pos Position) Position {
+	edge := Position{X: 0, Y: pos.Y}
+	distance := pos.X
+	if d := field.Width - pos.X; d < distance {
+		edge, distance = Position{X: field.Width, Y: pos.Y}, d
+	}
+	if d := pos.Y; d < distance {
+		edge, distance = Position{X: pos.X, Y: 0}, d
+	}
+	if d := field.Height - pos.Y; d < distance {
+		edge = Position{X: pos.X, Y: field.Height}
+	}
+	return edge
+}
@@ -0,0 +1,319 @@
+package battle
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RecordingVersion is the version of the recording format
+const RecordingVersion = 1
+
+// Recording represents the serializable recording of a battle
+// that can be replayed (see Replay)
+type Recording struct {
+	Version int
+
+	// Start represents the time the battle started at
+	Start time.Time
+
+	// Factions represents the factions in the order they were defined
+	Factions []RecordedFaction
+
+	// Soldiers represents all soldiers deployed during the battle
+	// including the commanders and the arrived reinforcements
+	Soldiers []RecordedSoldier
+
+	// Entries represents the battle log
+	Entries []RecordedEntry
+
+	// Result represents the result of the battle,
+	// it's nil unless the battle is over
+	Result *RecordedResult
+}
+
+// RecordedFaction represents a faction of a recorded battle
+type RecordedFaction struct {
+	Name  string
+	Units []Unit
+}
+
+// RecordedSoldier represents a soldier of a recorded battle
+// as it was deployed
+type RecordedSoldier struct {
+	ID         SoldierID
+	Attributes SoldierAttributes
+	Equipment  Equipment
+	Status     SoldierStatus
+	Commander  bool
+
+	// Position represents the position the soldier was deployed at
+	// on a spatial battlefield, it's nil otherwise
+	Position *Position
+}
+
+// RecordedEntry represents a battle log entry of a recording.
+// Soldiers are referenced by their IDs
+type RecordedEntry struct {
+	Time time.Time
+
+	// Type is the name of the event type such as "EventHit"
+	Type  string
+	Event json.RawMessage
+
+	// Soldiers represents the status of the soldiers involved in the event
+	Soldiers []SoldierSnapshot `json:",omitempty"`
+}
+
+// RecordedResult represents the result of a recorded battle
+// (see Result). Soldiers are referenced by their IDs
+type RecordedResult struct {
+	Outcome    Outcome
+	Winners    []string
+	WinnerTeam string
+	Survivors  map[string][]SoldierID
+	Routed     map[string][]SoldierID
+	Fled       map[string][]SoldierID
+	Deployed   map[string]int
+	Duration   time.Duration
+	Events     int
+}
+
+// Encode writes the recording in the JSON format
+func (r *Recording) Encode(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(r); err != nil {
+		return errors.Wrap(err, "encoding recording")
+	}
+	return nil
+}
+
+// DecodeRecording reads a recording in the JSON format
+func DecodeRecording(r io.Reader) (*Recording, error) {
+	rec := &Recording{}
+	if err := json.NewDecoder(r).Decode(rec); err != nil {
+		return nil, errors.Wrap(err, "decoding recording")
+	}
+	if rec.Version != RecordingVersion {
+		return nil, errors.Errorf(
+			"unsupported recording version: %d",
+			rec.Version,
+		)
+	}
+	return rec, nil
+}
+
+// record records a soldier deployed to the battle
+//
+// This method is thread-safe
+func (b *Battle) record(s *soldier) {
+	rec := RecordedSoldier{
+		ID:         s.id,
+		Attributes: s.attrs,
+		Equipment:  s.equipment,
+		Status:     s.Status(),
+		Commander:  s.isCommander,
+	}
+	if field, isField := b.battlefield.(*fieldBattlefield); isField {
+		if position, err := field.Position(s.id); err == nil {
+			rec.Position = &position
+		}
+	}
+
+	b.lock.Lock()
+	b.recording.Soldiers = append(b.recording.Soldiers, rec)
+	b.lock.Unlock()
+}
+
+// recordResult records the result of the battle
+//
+// This method is thread-safe
+func (b *Battle) recordResult(result Result) {
+	ids := func(soldiers map[string][]Soldier) map[string][]SoldierID {
+		m := make(map[string][]SoldierID, len(soldiers))
+		for faction, list := range soldiers {
+			m[faction] = make([]SoldierID, len(list))
+			for i, soldier := range list {
+				m[faction][i] = soldier.ID()
+			}
+		}
+		return m
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.recording.Result = &RecordedResult{
+		Outcome:    result.Outcome,
+		Winners:    result.Winners,
+		WinnerTeam: result.WinnerTeam,
+		Survivors:  ids(result.Survivors),
+		Routed:     ids(result.Routed),
+		Fled:       ids(result.Fled),
+		Deployed:   result.Deployed,
+		Duration:   result.Duration,
+		Events:     result.Events,
+	}
+}
+
+// Recording returns the recording of the battle
+// including the events logged so far
+func (b *Battle) Recording() (*Recording, error) {
+	b.lock.Lock()
+	rec := *b.recording
+	rec.Soldiers = append([]RecordedSoldier(nil), b.recording.Soldiers...)
+	b.lock.Unlock()
+
+	log := b.stats.Log()
+	rec.Entries = make([]RecordedEntry, len(log))
+	for i, entry := range log {
+		tp, data, err := encodeEvent(entry.Event)
+		if err != nil {
+			return nil, errors.Wrapf(err, "recording event %d", i)
+		}
+		rec.Entries[i] = RecordedEntry{
+			Time:     entry.Time,
+			Type:     tp,
+			Event:    data,
+			Soldiers: entry.Soldiers,
+		}
+	}
+	return &rec, nil
+}
+
+/*************************************************************\
+	Event encoding
+\*************************************************************/
+
+var (
+	soldierType  = reflect.TypeOf((*Soldier)(nil)).Elem()
+	soldiersType = reflect.TypeOf([]Soldier(nil))
+)
+
+// recordableEvents maps the names of the event types to their types
+var recordableEvents = func() map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for _, event := range []Event{
+		EventDodge{},
+		EventBlock{},
+		EventMiss{},
+		EventHit{},
+		EventKill{},
+		EventMove{},
+		EventRout{},
+		EventRally{},
+		EventFlee{},
+		EventCommanderKilled{},
+		EventReinforcementsArrived{},
+		EventAllianceBroken{},
+		EventHeal{},
+		EventEffectApplied{},
+		EventEffectTick{},
+		EventEffectExpired{},
+		EventEffectKill{},
+		EventLevelUp{},
+	} {
+		tp := reflect.TypeOf(event)
+		types[tp.Name()] = tp
+	}
+	return types
+}()
+
+// encodeEvent encodes an event replacing the soldiers by their IDs
+// and returns the name of its type
+func encodeEvent(event Event) (string, json.RawMessage, error) {
+	value := reflect.ValueOf(event)
+	if !value.IsValid() {
+		return "", nil, errors.New("nil event")
+	}
+	tp := value.Type()
+	if recordableEvents[tp.Name()] != tp {
+		return "", nil, errors.Errorf("unrecordable event type: %s", tp)
+	}
+
+	fields := make(map[string]interface{}, tp.NumField())
+	for i := 0; i < tp.NumField(); i++ {
+		name, field := tp.Field(i).Name, value.Field(i)
+		switch field.Type() {
+		case soldierType:
+			if field.IsNil() {
+				fields[name] = nil
+				continue
+			}
+			fields[name] = field.Interface().(Soldier).ID()
+		case soldiersType:
+			ids := make([]SoldierID, field.Len())
+			for j := range ids {
+				ids[j] = field.Index(j).Interface().(Soldier).ID()
+			}
+			fields[name] = ids
+		default:
+			fields[name] = field.Interface()
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "encoding %s", tp.Name())
+	}
+	return tp.Name(), data, nil
+}
+
+// decodeEvent decodes an event of the given type
+// resolving the soldier IDs using the given function
+func decodeEvent(
+	typeName string,
+	data json.RawMessage,
+	soldier func(SoldierID) (Soldier, error),
+) (Event, error) {
+	tp, known := recordableEvents[typeName]
+	if !known {
+		return nil, errors.Errorf("unknown event type: '%s'", typeName)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.Wrapf(err, "decoding %s", typeName)
+	}
+
+	value := reflect.New(tp).Elem()
+	for i := 0; i < tp.NumField(); i++ {
+		name, field := tp.Field(i).Name, value.Field(i)
+		raw, defined := fields[name]
+		if !defined || string(raw) == "null" {
+			continue
+		}
+		switch field.Type() {
+		case soldierType:
+			var id SoldierID
+			if err := json.Unmarshal(raw, &id); err != nil {
+				return nil, errors.Wrapf(err, "decoding %s.%s", typeName, name)
+			}
+			s, err := soldier(id)
+			if err != nil {
+				return nil, err
+			}
+			field.Set(reflect.ValueOf(s))
+		case soldiersType:
+			var ids []SoldierID
+			if err := json.Unmarshal(raw, &ids); err != nil {
+				return nil, errors.Wrapf(err, "decoding %s.%s", typeName, name)
+			}
+			soldiers := make([]Soldier, len(ids))
+			for j, id := range ids {
+				s, err := soldier(id)
+				if err != nil {
+					return nil, err
+				}
+				soldiers[j] = s
+			}
+			field.Set(reflect.ValueOf(soldiers))
+		default:
+			if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+				return nil, errors.Wrapf(err, "decoding %s.%s", typeName, name)
+			}
+		}
+	}
+	return value.Interface(), nil
+}
@@ -0,0 +1,228 @@
+package battle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Wave represents a wave of reinforcements of a faction
+type Wave struct {
+	// Size and SoldierAttributes or Units define the soldiers of the wave
+	// the same way as the army of a faction (see Faction)
+	Size              uint
+	SoldierAttributes SoldierAttributes
+	Loadouts          []Loadout
+	Units             []Unit
+
+	// Delay makes the wave arrive the given time after the battle began
+	Delay time.Duration
+
+	// ArmyThreshold makes the wave arrive as soon as the number
+	// of fighting soldiers of the faction drops below the given percentage
+	// of its initial army. A wave defining both a delay and a threshold
+	// arrives at whichever comes first
+	ArmyThreshold float64
+}
+
+// units returns the unit groups of the wave
+func (w Wave) units() []Unit {
+	if len(w.Units) > 0 {
+		return w.Units
+	}
+	return []Unit{{
+		Count:             w.Size,
+		SoldierAttributes: w.SoldierAttributes,
+		Loadouts:          w.Loadouts,
+	}}
+}
+
+// Verify returns an error if the wave is invalid
+func (w *Wave) Verify() error {
+	if len(w.Units) > 0 && (w.Size != 0 ||
+		w.SoldierAttributes != SoldierAttributes{} ||
+		len(w.Loadouts) > 0) {
+		return errors.New(
+			"wave defines both units and a size, " +
+				"soldier attributes or loadouts",
+		)
+	}
+	if w.Delay < 0 {
+		return errors.Errorf("invalid delay: %s", w.Delay)
+	}
+	if w.ArmyThreshold < 0 || w.ArmyThreshold > 1 {
+		return errors.Errorf(
+			"invalid army threshold %%: %.1f",
+			w.ArmyThreshold,
+		)
+	}
+	if w.Delay == 0 && w.ArmyThreshold == 0 {
+		return errors.New("neither a delay nor an army threshold defined")
+	}
+	for _, unit := range w.units() {
+		if err := unit.SoldierAttributes.Verify(); err != nil {
+			return errors.Wrapf(err, "invalid attributes of unit '%s'", unit.Type)
+		}
+		if err := verifyLoadouts(unit.Loadouts); err != nil {
+			return errors.Wrapf(err, "invalid loadouts of unit '%s'", unit.Type)
+		}
+		if len(unit.Veterans) > 0 {
+			return errors.Errorf(
+				"unit '%s' of a wave defines veterans",
+				unit.Type,
+			)
+		}
+	}
+	return nil
+}
+
+// wave represents the state of a wave of reinforcements during the battle
+type wave struct {
+	config   Wave
+	index    int
+	faction  Faction
+	armySize uint
+
+	// triggered is set once the wave is on its way,
+	// arrived is set once its soldiers are on the battlefield
+	triggered bool
+	arrived   bool
+
+	// timer is the ticker of the delay of the wave
+	timer *DynamicTicker
+}
+
+// pendingWaves returns true if the faction still awaits reinforcements.
+// Expects the battle lock to be locked
+func (b *Battle) pendingWaves(factionName string) bool {
+	for _, w := range b.waves[factionName] {
+		if !w.arrived {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleWaves schedules the arrival of the waves defining a delay
+func (b *Battle) scheduleWaves(ctx context.Context, wg *sync.WaitGroup) {
+	for _, factionName := range b.factions {
+		for _, w := range b.waves[factionName] {
+			if w.config.Delay <= 0 {
+				continue
+			}
+			w, tk := w, b.newTicker()
+			b.lock.Lock()
+			w.timer = tk
+			b.lock.Unlock()
+			tk.Reset(w.config.Delay)
+			b.after(ctx, wg, tk, func() error {
+				return b.arrive(w)
+			})
+		}
+	}
+}
+
+// arrive makes the wave arrive unless it's already on its way
+func (b *Battle) arrive(w *wave) error {
+	b.lock.Lock()
+	triggered := w.triggered
+	w.triggered = true
+	b.lock.Unlock()
+	if triggered {
+		return nil
+	}
+	return b.spawn(w)
+}
+
+// reinforceIfNeeded makes the waves of the faction arrive
+// whose army threshold has been reached
+func (b *Battle) reinforceIfNeeded(factionName string) error {
+	b.lock.Lock()
+	fighting := float64(len(b.alive[factionName]))
+	var arriving []*wave
+	for _, w := range b.waves[factionName] {
+		if w.triggered || w.config.ArmyThreshold <= 0 {
+			continue
+		}
+		if fighting < w.config.ArmyThreshold*float64(w.armySize) {
+			w.triggered = true
+			arriving = append(arriving, w)
+		}
+	}
+	b.lock.Unlock()
+
+	for _, w := range arriving {
+		if err := b.spawn(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spawn generates the soldiers of the wave
+// and makes them join the running battle
+func (b *Battle) spawn(w *wave) error {
+	b.spawnLock.Lock()
+	defer b.spawnLock.Unlock()
+
+	b.lock.Lock()
+	ctx, wg := b.runCtx, b.wg
+	b.lock.Unlock()
+	if ctx == nil || ctx.Err() != nil {
+		// The battle is over
+		return nil
+	}
+
+	field, _ := b.battlefield.(*fieldBattlefield)
+	factionName := w.faction.Name
+	var soldiers []*soldier
+	for _, unit := range w.config.units() {
+		unitSoldiers, err := b.generateUnit(
+			w.faction,
+			unit,
+			b.names[factionName],
+			field,
+		)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"generating reinforcements for faction %s",
+				factionName,
+			)
+		}
+		b.lock.Lock()
+		b.commands[factionName].formSquads(unitSoldiers)
+		b.lock.Unlock()
+		soldiers = append(soldiers, unitSoldiers...)
+	}
+
+	arrived := make([]Soldier, len(soldiers))
+	for i, s := range soldiers {
+		arrived[i] = s
+	}
+
+	b.lock.Lock()
+	b.armies[factionName] = append(b.armies[factionName], arrived...)
+	b.alive[factionName] = append(b.alive[factionName], arrived...)
+	w.arrived = true
+	wg.Add(len(arrived))
+	b.lock.Unlock()
+
+	if err := b.log().PushEvent(EventReinforcementsArrived{
+		Faction:  factionName,
+		Wave:     w.index,
+		Soldiers: arrived,
+	}, snapshotSoldiers(arrived...)...); err != nil {
+		return err
+	}
+
+	for _, s := range arrived {
+		go func(s Soldier) {
+			defer wg.Done()
+			b.join(ctx, s)
+		}(s)
+	}
+	return nil
+}
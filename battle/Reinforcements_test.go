@@ -0,0 +1,99 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestWaves makes sure waves of reinforcements arrive either
+// after their delay or once the army drops below their threshold
+func TestWaves(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, tc := range []struct {
+		name string
+		wave Wave
+
+		// losses is the minimum number of soldiers the faction
+		// lost before the wave arrived
+		losses int
+	}{
+		{
+			name: "delay",
+			wave: Wave{
+				Size:              3,
+				SoldierAttributes: testAttributes(50),
+				Delay:             50 * time.Millisecond,
+			},
+		},
+		{
+			name: "army threshold",
+			wave: Wave{
+				Size:              3,
+				SoldierAttributes: testAttributes(50),
+				ArmyThreshold:     .5,
+			},
+			losses: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factions := testFactions(5, 50)
+			factions[1].Reinforcements = []Wave{tc.wave}
+			btl := newTestBattle(t, Config{
+				BaseActionDelay: 10 * time.Millisecond,
+				Seed:            1,
+				Clock:           NewVirtualClock(start),
+			}, factions...)
+			result := awaitResult(t, runAsync(context.Background(), btl))
+
+			if deployed := result.Deployed["B"]; deployed != 8 {
+				t.Errorf("%d soldiers deployed, expected 8", deployed)
+			}
+			soldiers := btl.Soldiers("B")
+			if len(soldiers) != 8 {
+				t.Fatalf("%d soldiers, expected 8", len(soldiers))
+			}
+
+			var arrival *EventReinforcementsArrived
+			losses := 0
+			for _, entry := range btl.Statistics().Log() {
+				switch ev := entry.Event.(type) {
+				case EventKill:
+					if arrival == nil && ev.Killed.ID().Faction == "B" {
+						losses++
+					}
+				case EventRout:
+					if arrival == nil && ev.Soldier.ID().Faction == "B" {
+						losses++
+					}
+				case EventReinforcementsArrived:
+					if arrival != nil {
+						t.Fatal("the wave arrived twice")
+					}
+					arrival = &ev
+					if elapsed := entry.Time.Sub(start); elapsed <
+						tc.wave.Delay {
+						t.Errorf("arrived after %s", elapsed)
+					}
+				}
+			}
+			if arrival == nil {
+				t.Fatal("the wave didn't arrive")
+			}
+			if arrival.Faction != "B" || arrival.Wave != 0 {
+				t.Errorf("unexpected arrival: %s", arrival)
+			}
+			if losses < tc.losses {
+				t.Errorf("arrived after %d losses", losses)
+			}
+			if len(arrival.Soldiers) != 3 {
+				t.Fatalf("%d soldiers arrived", len(arrival.Soldiers))
+			}
+			for i, s := range arrival.Soldiers {
+				if soldiers[5+i] != s {
+					t.Errorf("%s is missing in the army", s.ID())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package battle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrReplayOver is an error that's returned by Replay.Step
+// when all events were replayed
+var ErrReplayOver = errors.New("replay is over")
+
+// ErrReplayed is an error that's returned by the actions
+// of replayed soldiers
+var ErrReplayed = errors.New("replayed soldiers can't act")
+
+// Replay replays a recorded battle re-emitting the recorded events
+// through its statistics in their original order and at their original time
+type Replay struct {
+	lock      *sync.Mutex
+	recording *Recording
+	soldiers  map[SoldierID]*replayedSoldier
+	entries   []LogEntry
+	clock     *VirtualClock
+	stats     *Statistics
+	next      int
+	stopped   bool
+}
+
+// NewReplay creates a new replay of the given recording
+func NewReplay(recording *Recording) (*Replay, error) {
+	if recording == nil {
+		return nil, errors.New("missing recording")
+	}
+	if recording.Version != RecordingVersion {
+		return nil, errors.Errorf(
+			"unsupported recording version: %d",
+			recording.Version,
+		)
+	}
+
+	clock := NewVirtualClock(recording.Start)
+	r := &Replay{
+		lock:      &sync.Mutex{},
+		recording: recording,
+		soldiers: make(
+			map[SoldierID]*replayedSoldier,
+			len(recording.Soldiers),
+		),
+		entries: make([]LogEntry, len(recording.Entries)),
+		clock:   clock,
+		stats:   NewStatistics(clock),
+	}
+	for _, rec := range recording.Soldiers {
+		if _, duplicate := r.soldiers[rec.ID]; duplicate {
+			return nil, errors.Errorf("duplicate soldier: %s", rec.ID)
+		}
+		r.soldiers[rec.ID] = &replayedSoldier{recorded: rec}
+	}
+
+	// Decode all events ahead to not fail in the middle of the replay
+	for i, entry := range recording.Entries {
+		event, err := decodeEvent(entry.Type, entry.Event, r.soldier)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid recorded event %d", i)
+		}
+		r.entries[i] = LogEntry{
+			Time:     entry.Time,
+			Event:    event,
+			Soldiers: entry.Soldiers,
+		}
+	}
+	return r, nil
+}
+
+// soldier returns the replayed soldier of the given ID
+func (r *Replay) soldier(id SoldierID) (Soldier, error) {
+	soldier, known := r.soldiers[id]
+	if !known {
+		return nil, errors.Errorf("unknown soldier: %s", id)
+	}
+	return soldier, nil
+}
+
+// Statistics returns the statistics reader the events are replayed through
+func (r *Replay) Statistics() StatisticsReader {
+	return r.stats
+}
+
+// Recording returns the replayed recording
+func (r *Replay) Recording() *Recording {
+	return r.recording
+}
+
+// Factions returns the factions of the replayed battle
+// in the order they were defined
+func (r *Replay) Factions() []Faction {
+	factions := make([]Faction, len(r.recording.Factions))
+	for i, faction := range r.recording.Factions {
+		factions[i] = Faction{Name: faction.Name, Units: faction.Units}
+	}
+	return factions
+}
+
+// Result returns the result of the replayed battle
+// and false if the recording doesn't contain a result
+func (r *Replay) Result() (Result, bool) {
+	rec := r.recording.Result
+	if rec == nil {
+		return Result{}, false
+	}
+	soldiers := func(ids map[string][]SoldierID) map[string][]Soldier {
+		m := make(map[string][]Soldier, len(ids))
+		for faction, list := range ids {
+			m[faction] = make([]Soldier, 0, len(list))
+			for _, id := range list {
+				if soldier, known := r.soldiers[id]; known {
+					m[faction] = append(m[faction], soldier)
+				}
+			}
+		}
+		return m
+	}
+	return Result{
+		Outcome:    rec.Outcome,
+		Winners:    rec.Winners,
+		WinnerTeam: rec.WinnerTeam,
+		Survivors:  soldiers(rec.Survivors),
+		Routed:     soldiers(rec.Routed),
+		Fled:       soldiers(rec.Fled),
+		Deployed:   rec.Deployed,
+		Duration:   rec.Duration,
+		Events:     rec.Events,
+	}, true
+}
+
+// Step replays the next event and returns its log entry
+// or ErrReplayOver if all events were replayed
+//
+// This method is thread-safe
+func (r *Replay) Step() (LogEntry, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.next >= len(r.entries) {
+		r.stop()
+		return LogEntry{}, ErrReplayOver
+	}
+	entry := r.entries[r.next]
+	r.next++
+
+	r.clock.Advance(entry.Time.Sub(r.clock.Now()))
+	// Push the recorded snapshots since the replayed soldiers
+	// only know the status they were deployed with
+	if err := r.stats.pushEntry(entry.Event, entry.Soldiers); err != nil {
+		return LogEntry{}, err
+	}
+	if r.next >= len(r.entries) {
+		r.stop()
+	}
+	return entry, nil
+}
+
+// stop ends the replay closing all subscriptions.
+// Expects the replay lock to be locked
+func (r *Replay) stop() {
+	if r.stopped {
+		return
+	}
+	r.stopped = true
+	if rec := r.recording.Result; rec != nil && len(rec.Winners) == 1 {
+		r.stats.setWinnerFaction(rec.Winners[0])
+	}
+	r.stats.StopRecording()
+}
+
+// Play replays the remaining events until either all events were replayed
+// or the context is canceled. The speed multiplies the original pace
+// of the battle: a speed of 2 replays the battle twice as fast
+// while a zero speed replays the events without any delay
+func (r *Replay) Play(ctx context.Context, speed float64) error {
+	if speed < 0 {
+		return errors.Errorf("invalid replay speed: %.2f", speed)
+	}
+
+	// The events are scheduled relative to the start of the playback
+	// to not accumulate the delays of the timers
+	r.lock.Lock()
+	start, replayStart := time.Now(), r.clock.Now()
+	r.lock.Unlock()
+	for {
+		r.lock.Lock()
+		var delay time.Duration
+		if r.next < len(r.entries) && speed > 0 {
+			offset := r.entries[r.next].Time.Sub(replayStart)
+			delay = time.Duration(float64(offset)/speed) - time.Since(start)
+		}
+		r.lock.Unlock()
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch _, err := r.Step(); err {
+		case nil:
+		case ErrReplayOver:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+// replayedSoldier implements the Soldier interface for the soldiers
+// of a replayed battle. It reports the status the soldier was deployed with
+// and can't act
+type replayedSoldier struct {
+	recorded RecordedSoldier
+}
+
+// ID implements the Soldier interface
+func (s *replayedSoldier) ID() SoldierID { return s.recorded.ID }
+
+// Status implements the Soldier interface
+func (s *replayedSoldier) Status() SoldierStatus {
+	status := s.recorded.Status
+	status.Effects = append([]ActiveEffect(nil), status.Effects...)
+	return status
+}
+
+// Stats implements the Soldier interface
+func (s *replayedSoldier) Stats() SoldierStatistics { return SoldierStatistics{} }
+
+// IsAlive implements the Soldier interface
+func (s *replayedSoldier) IsAlive() bool { return s.recorded.Status.Health > 0 }
+
+// Squad implements the Soldier interface
+func (s *replayedSoldier) Squad() *Squad { return nil }
+
+// IsCommander implements the Soldier interface
+func (s *replayedSoldier) IsCommander() bool { return s.recorded.Commander }
+
+// Equipment implements the Soldier interface
+func (s *replayedSoldier) Equipment() Equipment { return s.recorded.Equipment }
+
+// JoinBattle implements the Soldier interface
+func (s *replayedSoldier) JoinBattle(ctx context.Context) {}
+
+// TakeDamage implements the Soldier interface
+func (s *replayedSoldier) TakeDamage(
+	from Soldier,
+	damage float64,
+) (float64, bool, error) {
+	return 0, false, ErrReplayed
+}
+
+// Attack implements the Soldier interface
+func (s *replayedSoldier) Attack(opponent Soldier) (float64, bool, error) {
+	return 0, false, ErrReplayed
+}
+
+// ReceiveHealing implements the Soldier interface
+func (s *replayedSoldier) ReceiveHealing(
+	from Soldier,
+	healing float64,
+) (float64, error) {
+	return 0, ErrReplayed
+}
+
+// Heal implements the Soldier interface
+func (s *replayedSoldier) Heal(ally Soldier) (float64, error) {
+	return 0, ErrReplayed
+}
+
+// Afflict implements the Soldier interface
+func (s *replayedSoldier) Afflict(from Soldier, effect StatusEffect) error {
+	return ErrReplayed
+}
+
+// AddMorale implements the Soldier interface
+func (s *replayedSoldier) AddMorale(percent float64) (float64, time.Duration) {
+	return s.recorded.Status.Morale, 0
+}
@@ -0,0 +1,76 @@
+package battle
+
+import "time"
+
+// Outcome represents the kind of outcome of a battle
+type Outcome int
+
+const (
+	// OutcomeVictory is the outcome of a battle
+	// won by the last team standing. A faction whose soldiers
+	// are either dead or routed doesn't stand
+	OutcomeVictory Outcome = iota
+
+	// OutcomeDraw is the outcome of a battle no faction survived
+	OutcomeDraw
+
+	// OutcomeTimeout is the outcome of a battle that reached
+	// the deadline of its context before it was decided
+	OutcomeTimeout
+
+	// OutcomeCanceled is the outcome of a battle
+	// that was canceled before it was decided
+	OutcomeCanceled
+)
+
+// String stringifies the outcome
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeVictory:
+		return "victory"
+	case OutcomeDraw:
+		return "draw"
+	case OutcomeTimeout:
+		return "timeout"
+	case OutcomeCanceled:
+		return "canceled"
+	}
+	return "unknown"
+}
+
+// Result represents the result of a battle
+type Result struct {
+	// Outcome represents the kind of outcome
+	Outcome Outcome
+
+	// Winners represents the names of the factions of the winner team,
+	// it's empty unless the outcome is a victory
+	Winners []string
+
+	// WinnerTeam represents the name of the winner team,
+	// it's empty unless the outcome is a victory
+	WinnerTeam string
+
+	// Survivors represents the surviving soldiers per faction name
+	// that didn't rout
+	Survivors map[string][]Soldier
+
+	// Routed represents the routed soldiers per faction name
+	// that are still on the battlefield
+	Routed map[string][]Soldier
+
+	// Fled represents the routed soldiers per faction name
+	// that fled the battlefield
+	Fled map[string][]Soldier
+
+	// Deployed represents the number of soldiers per faction name
+	// that took part in the battle including the arrived reinforcements
+	Deployed map[string]int
+
+	// Duration represents the duration of the battle
+	// measured by the battle clock
+	Duration time.Duration
+
+	// Events represents the total number of logged events
+	Events int
+}
@@ -0,0 +1,800 @@
+package battle
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotVersion is the version of the snapshot format
+const SnapshotVersion = 1
+
+// Snapshot represents the serializable state of a running battle
+// the battle can be restored from (see RestoreBattle)
+type Snapshot struct {
+	Version int
+
+	// Elapsed represents the time the battle had been running for.
+	// The time a sequential battle was paused for isn't included
+	Elapsed time.Duration
+
+	// Sequential is true if the soldiers of the battle act sequentially
+	// (see Config.Seed)
+	Sequential bool
+
+	// RNG represents the state of the pseudo-random number generator
+	RNG uint64
+
+	// Speed represents the speed of the battle (see Battle.SetSpeed)
+	Speed float64
+
+	// Factions represents the state of the factions
+	// in the order they were defined
+	Factions []SnapshotFaction
+
+	// Recording represents the recording of the battle
+	// including the battle log so far
+	Recording *Recording
+}
+
+// SnapshotFaction represents the state of a faction of a battle snapshot
+type SnapshotFaction struct {
+	Name string
+	Team string
+
+	// Provoked represents the factions a neutral faction was provoked by
+	Provoked []string `json:",omitempty"`
+
+	// Soldiers represents all soldiers deployed for the faction
+	// in the order of their deployment including the dead and fled ones
+	Soldiers []SnapshotSoldier
+
+	// Alive, Routed and Fled represent the IDs of the soldiers
+	// still fighting, routed and fled
+	Alive  []SoldierID
+	Routed []SoldierID
+	Fled   []SoldierID
+
+	Squads        []SnapshotSquad        `json:",omitempty"`
+	Waves         []SnapshotWave         `json:",omitempty"`
+	AllianceBreak *SnapshotAllianceBreak `json:",omitempty"`
+
+	// FocusTarget represents the priority target of a faction
+	// using FocusFireTargeting
+	FocusTarget *SoldierID `json:",omitempty"`
+}
+
+// SnapshotSoldier represents the state of a soldier of a battle snapshot
+type SnapshotSoldier struct {
+	ID             SoldierID
+	Attributes     SoldierAttributes
+	Equipment      Equipment
+	MaxHealth      float64
+	Status         SoldierStatus
+	Stats          SoldierStatistics
+	Commander      bool
+	HitChanceBonus float64
+
+	// Position represents the position of the soldier
+	// on a spatial battlefield, it's nil otherwise
+	Position *Position `json:",omitempty"`
+
+	// Action represents the timer of the soldier's next action
+	Action SnapshotTimer
+
+	// RestingSince represents the battle time since which the soldier
+	// is yet to regain stamina
+	RestingSince time.Duration
+
+	// Effects represents the state of the active status effects
+	// in the order of Status.Effects
+	Effects   []SnapshotEffect `json:",omitempty"`
+	EffectSeq uint64
+}
+
+// SnapshotEffect represents the state of an active status effect
+type SnapshotEffect struct {
+	ID       uint64
+	Interval time.Duration
+	Timer    SnapshotTimer
+}
+
+// SnapshotSquad represents a squad of a battle snapshot
+type SnapshotSquad struct {
+	Members []SoldierID
+	Order   OrderKind
+
+	// Target represents the target of a focus order
+	Target *SoldierID `json:",omitempty"`
+}
+
+// SnapshotWave represents the state of a wave of reinforcements
+type SnapshotWave struct {
+	Triggered bool
+	Arrived   bool
+
+	// Timer represents the timer of the wave's delay
+	Timer SnapshotTimer
+}
+
+// SnapshotAllianceBreak represents the state of a scripted alliance breakup
+type SnapshotAllianceBreak struct {
+	Fired bool
+
+	// Timer represents the timer of the breakup's delay
+	Timer SnapshotTimer
+}
+
+// SnapshotTimer represents the state of a timer of a battle snapshot
+type SnapshotTimer struct {
+	// Running is false if the timer was stopped
+	Running bool
+
+	// Remaining represents the time left until the next tick
+	// and Interval the time between the ticks following it
+	Remaining time.Duration
+	Interval  time.Duration
+
+	// Index represents the order of creation of the timer which
+	// decides between timers ticking at the same time in sequential battles
+	Index uint64
+}
+
+// snapshotTimer returns the state of the given ticker
+func snapshotTimer(tk *DynamicTicker) SnapshotTimer {
+	if tk == nil {
+		return SnapshotTimer{}
+	}
+	remaining, interval, running := tk.timer()
+	if !running {
+		return SnapshotTimer{Index: tk.index}
+	}
+	return SnapshotTimer{
+		Running:   true,
+		Remaining: remaining,
+		Interval:  interval,
+		Index:     tk.index,
+	}
+}
+
+// Reseed replaces the state of the pseudo-random number generator
+// of the snapshot by the given seed. Battles restored from snapshots
+// reseeded differently take a different course from the moment
+// of the snapshot on while the same seed always yields the same course
+// in sequential battles
+func (s *Snapshot) Reseed(seed int64) {
+	s.RNG = uint64(seed)
+}
+
+// Encode writes the snapshot in the JSON format
+func (s *Snapshot) Encode(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		return errors.Wrap(err, "encoding snapshot")
+	}
+	return nil
+}
+
+// DecodeSnapshot reads a snapshot in the JSON format
+func DecodeSnapshot(r io.Reader) (*Snapshot, error) {
+	s := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, errors.Wrap(err, "decoding snapshot")
+	}
+	if s.Version != SnapshotVersion {
+		return nil, errors.Errorf(
+			"unsupported snapshot version: %d",
+			s.Version,
+		)
+	}
+	return s, nil
+}
+
+// Snapshot captures the state of the running battle between two actions.
+// The battle is paused while the snapshot is taken.
+// Snapshots of sequential battles are exact: a battle restored
+// from the snapshot continues the same way the battle does.
+// Snapshot must not be called by a subscriber blocking the battle
+// (see OverflowBlock)
+//
+// This method is thread-safe
+func (b *Battle) Snapshot() (*Snapshot, error) {
+	b.lock.Lock()
+	running := b.runCtx != nil && b.runCtx.Err() == nil
+	b.lock.Unlock()
+	if !running {
+		return nil, errors.New("the battle isn't running")
+	}
+
+	// Wait for the actions being taken to complete
+	if !b.pause() {
+		defer b.Resume()
+	}
+	if b.sched != nil {
+		b.sched.waitIdle()
+	}
+	b.actionLock.Lock()
+	defer b.actionLock.Unlock()
+
+	rec, err := b.Recording()
+	if err != nil {
+		return nil, errors.Wrap(err, "recording the battle")
+	}
+	snapshot := &Snapshot{
+		Version:    SnapshotVersion,
+		Sequential: b.sched != nil,
+		RNG:        b.rng.snapshot(),
+		Speed:      b.Speed(),
+		Recording:  rec,
+	}
+	snapshot.Elapsed = b.battleTime()
+
+	field, _ := b.battlefield.(*fieldBattlefield)
+	for _, factionName := range b.factions {
+		faction, err := b.snapshotFaction(factionName, field)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Factions = append(snapshot.Factions, faction)
+	}
+	return snapshot, nil
+}
+
+// snapshotFaction returns the state of the faction.
+// Expects the action lock to be locked
+func (b *Battle) snapshotFaction(
+	factionName string,
+	field *fieldBattlefield,
+) (SnapshotFaction, error) {
+	ids := func(soldiers []Soldier) []SoldierID {
+		list := make([]SoldierID, len(soldiers))
+		for i, soldier := range soldiers {
+			list[i] = soldier.ID()
+		}
+		return list
+	}
+
+	b.lock.Lock()
+	faction := SnapshotFaction{
+		Name:   factionName,
+		Team:   b.teams[factionName],
+		Alive:  ids(b.alive[factionName]),
+		Routed: ids(b.routed[factionName]),
+		Fled:   ids(b.fled[factionName]),
+	}
+	for provoker, provoked := range b.provoked[factionName] {
+		if provoked {
+			faction.Provoked = append(faction.Provoked, provoker)
+		}
+	}
+	sort.Strings(faction.Provoked)
+	for _, w := range b.waves[factionName] {
+		faction.Waves = append(faction.Waves, SnapshotWave{
+			Triggered: w.triggered,
+			Arrived:   w.arrived,
+			Timer:     snapshotTimer(w.timer),
+		})
+	}
+	for _, ab := range b.breaks {
+		if ab.faction == factionName {
+			faction.AllianceBreak = &SnapshotAllianceBreak{
+				Fired: ab.fired,
+				Timer: snapshotTimer(ab.timer),
+			}
+		}
+	}
+	army := append([]Soldier(nil), b.armies[factionName]...)
+	squads := append([]*Squad(nil), b.commands[factionName].squads...)
+	b.lock.Unlock()
+
+	for _, member := range army {
+		s, ok := member.(*soldier)
+		if !ok {
+			return SnapshotFaction{}, errors.Errorf(
+				"unexpected soldier implementation: %T",
+				member,
+			)
+		}
+		faction.Soldiers = append(faction.Soldiers, s.snapshot(field))
+	}
+
+	for _, sq := range squads {
+		order := sq.Order()
+		squad := SnapshotSquad{Members: ids(sq.members), Order: order.Kind}
+		if order.Target != nil {
+			target := order.Target.ID()
+			squad.Target = &target
+		}
+		faction.Squads = append(faction.Squads, squad)
+	}
+
+	if ff, ok := b.targeting[factionName].(*FocusFireTargeting); ok {
+		ff.lock.Lock()
+		if target, hasTarget := ff.targets[factionName]; hasTarget {
+			faction.FocusTarget = &target
+		}
+		ff.lock.Unlock()
+	}
+	return faction, nil
+}
+
+// snapshot returns the state of the soldier
+//
+// This method is thread-safe
+func (s *soldier) snapshot(field *fieldBattlefield) SnapshotSoldier {
+	var position *Position
+	if field != nil {
+		if pos, err := field.Position(s.id); err == nil {
+			position = &pos
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	status := s.status
+	status.Effects = append([]ActiveEffect(nil), s.status.Effects...)
+	if s.status.LastAttacker != nil {
+		lastAttacker := *s.status.LastAttacker
+		status.LastAttacker = &lastAttacker
+	}
+	snapshot := SnapshotSoldier{
+		ID:             s.id,
+		Attributes:     s.attrs,
+		Equipment:      s.equipment,
+		MaxHealth:      s.maxHealth,
+		Status:         status,
+		Stats:          s.stats,
+		Commander:      s.isCommander,
+		HitChanceBonus: s.hitChanceBonus,
+		Position:       position,
+		Action:         snapshotTimer(s.actionTicker),
+		RestingSince:   s.restingSince,
+		EffectSeq:      s.effectSeq,
+	}
+	for _, effect := range s.status.Effects {
+		snapshot.Effects = append(snapshot.Effects, SnapshotEffect{
+			ID:       effect.id,
+			Interval: effect.interval,
+			Timer:    snapshotTimer(effect.ticker),
+		})
+	}
+	return snapshot
+}
+
+// RestoreBattle restores a battle from the given snapshot.
+// The restored battle continues from the moment of the snapshot once it's run.
+// The configuration and the factions must be the ones the battle
+// was created with since they can't be serialized (such as targeting
+// strategies and trigger conditions). A virtual clock is advanced
+// to the time of the snapshot. A battle restored from the snapshot
+// of a sequential battle is sequential
+func RestoreBattle(
+	snapshot *Snapshot,
+	config Config,
+	factions ...Faction,
+) (*Battle, error) {
+	if snapshot == nil {
+		return nil, errors.New("missing snapshot")
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, errors.Errorf(
+			"unsupported snapshot version: %d",
+			snapshot.Version,
+		)
+	}
+	if snapshot.Recording == nil {
+		return nil, errors.New("snapshot without a recording")
+	}
+	if snapshot.Speed <= 0 {
+		return nil, errors.Errorf("invalid speed: %.2f", snapshot.Speed)
+	}
+	if len(snapshot.Factions) != len(factions) {
+		return nil, errors.Errorf(
+			"snapshot of %d factions restored with %d factions",
+			len(snapshot.Factions),
+			len(factions),
+		)
+	}
+	for i, faction := range factions {
+		if snapshot.Factions[i].Name != faction.Name {
+			return nil, errors.Errorf(
+				"faction %s doesn't match faction %s of the snapshot",
+				faction.Name,
+				snapshot.Factions[i].Name,
+			)
+		}
+	}
+
+	b, err := newBattle(config, factions, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.restore(snapshot); err != nil {
+		return nil, errors.Wrap(err, "restoring battle")
+	}
+	return b, nil
+}
+
+// restore replaces the state of the created battle
+// by the state of the snapshot
+func (b *Battle) restore(snapshot *Snapshot) error {
+	b.restored = true
+	b.elapsed = snapshot.Elapsed
+	b.speed = snapshot.Speed
+	if clock, isVirtual := b.clock.(*VirtualClock); isVirtual {
+		now := snapshot.Recording.Start.Add(snapshot.Elapsed)
+		clock.Advance(now.Sub(clock.Now()))
+	}
+	tickers := b.restoreTickers(snapshot)
+
+	// Restore the armies
+	field, _ := b.battlefield.(*fieldBattlefield)
+	soldiers := make(map[SoldierID]*soldier)
+	for i := range snapshot.Factions {
+		faction := &snapshot.Factions[i]
+		names := make(map[string]struct{}, len(faction.Soldiers))
+		army := make([]Soldier, len(faction.Soldiers))
+		for j := range faction.Soldiers {
+			snap := &faction.Soldiers[j]
+			if snap.ID.Faction != faction.Name {
+				return errors.Errorf(
+					"soldier %s in the army of faction %s",
+					snap.ID,
+					faction.Name,
+				)
+			}
+			if _, duplicate := soldiers[snap.ID]; duplicate {
+				return errors.Errorf("duplicate soldier: %s", snap.ID)
+			}
+			s, err := b.restoreSoldier(snap, tickers)
+			if err != nil {
+				return err
+			}
+			if field != nil && snap.Position != nil {
+				field.positions[snap.ID] = *snap.Position
+			}
+			if snap.Commander {
+				b.commands[faction.Name].commander = s
+			}
+			names[snap.ID.Name] = struct{}{}
+			soldiers[snap.ID] = s
+			army[j] = s
+		}
+		b.names[faction.Name] = names
+		b.armies[faction.Name] = army
+	}
+
+	lookup := func(id SoldierID) (Soldier, error) {
+		s, known := soldiers[id]
+		if !known {
+			return nil, errors.Errorf("unknown soldier: %s", id)
+		}
+		return s, nil
+	}
+	list := func(ids []SoldierID) ([]Soldier, error) {
+		list := make([]Soldier, len(ids))
+		for i, id := range ids {
+			s, err := lookup(id)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = s
+		}
+		return list, nil
+	}
+
+	// The sources of the status effects are resolved
+	// once all soldiers are restored
+	for i := range snapshot.Factions {
+		for _, s := range b.armies[snapshot.Factions[i].Name] {
+			effects := s.(*soldier).status.Effects
+			for k := range effects {
+				source, err := lookup(effects[k].Source)
+				if err != nil {
+					return errors.Wrap(err, "unknown status effect source")
+				}
+				effects[k].source = source
+			}
+		}
+	}
+
+	for i := range snapshot.Factions {
+		faction := &snapshot.Factions[i]
+		var err error
+		if b.alive[faction.Name], err = list(faction.Alive); err != nil {
+			return err
+		}
+		if b.routed[faction.Name], err = list(faction.Routed); err != nil {
+			return err
+		}
+		if b.fled[faction.Name], err = list(faction.Fled); err != nil {
+			return err
+		}
+		b.teams[faction.Name] = faction.Team
+		for _, provoker := range faction.Provoked {
+			if b.provoked[faction.Name] == nil {
+				b.provoked[faction.Name] = make(map[string]bool)
+			}
+			b.provoked[faction.Name][provoker] = true
+		}
+		if err := b.restoreSquads(faction, lookup); err != nil {
+			return err
+		}
+		if err := b.restoreTriggers(faction, tickers); err != nil {
+			return err
+		}
+
+		// Don't share the priority targets with other battles
+		if _, ok := b.targeting[faction.Name].(*FocusFireTargeting); ok {
+			ff := NewFocusFireTargeting()
+			if faction.FocusTarget != nil {
+				ff.targets[faction.Name] = *faction.FocusTarget
+			}
+			b.targeting[faction.Name] = ff
+		}
+	}
+
+	// Restore the log and the recording
+	rec := snapshot.Recording
+	log := make([]LogEntry, len(rec.Entries))
+	for i, entry := range rec.Entries {
+		event, err := decodeEvent(entry.Type, entry.Event, lookup)
+		if err != nil {
+			return errors.Wrapf(err, "invalid logged event %d", i)
+		}
+		log[i] = LogEntry{
+			Time:     entry.Time,
+			Event:    event,
+			Soldiers: entry.Soldiers,
+		}
+	}
+	b.stats.restoreLog(log)
+	b.recording = &Recording{
+		Version:  RecordingVersion,
+		Start:    rec.Start,
+		Factions: append([]RecordedFaction(nil), rec.Factions...),
+		Soldiers: append([]RecordedSoldier(nil), rec.Soldiers...),
+	}
+
+	// Restoring the soldiers consumed random numbers
+	b.rng.Seed(int64(snapshot.RNG))
+	return nil
+}
+
+// restoreTickers creates the tickers of all running timers of the snapshot
+// and the action tickers of all soldiers in their original order
+func (b *Battle) restoreTickers(
+	snapshot *Snapshot,
+) map[*SnapshotTimer]*DynamicTicker {
+	var timers []*SnapshotTimer
+	running := func(timer *SnapshotTimer) {
+		if timer.Running {
+			timers = append(timers, timer)
+		}
+	}
+	for i := range snapshot.Factions {
+		faction := &snapshot.Factions[i]
+		for j := range faction.Soldiers {
+			snap := &faction.Soldiers[j]
+			timers = append(timers, &snap.Action)
+			for k := range snap.Effects {
+				running(&snap.Effects[k].Timer)
+			}
+		}
+		for j := range faction.Waves {
+			running(&faction.Waves[j].Timer)
+		}
+		if faction.AllianceBreak != nil {
+			running(&faction.AllianceBreak.Timer)
+		}
+	}
+	sort.SliceStable(timers, func(i, j int) bool {
+		return timers[i].Index < timers[j].Index
+	})
+
+	tickers := make(map[*SnapshotTimer]*DynamicTicker, len(timers))
+	for _, timer := range timers {
+		switch {
+		case b.sched != nil && snapshot.Sequential:
+			tickers[timer] = b.sched.restoreTicker(timer.Index)
+		default:
+			tickers[timer] = b.newTicker()
+		}
+	}
+	return tickers
+}
+
+// restoreSoldier restores a soldier of the snapshot
+func (b *Battle) restoreSoldier(
+	snap *SnapshotSoldier,
+	tickers map[*SnapshotTimer]*DynamicTicker,
+) (*soldier, error) {
+	s, err := newSoldier(
+		snap.ID,
+		snap.Attributes,
+		snap.Equipment,
+		b.config,
+		b.rng,
+		tickers[&snap.Action],
+		b.battlefield,
+		b.log(),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "restoring soldier %s", snap.ID)
+	}
+	if len(snap.Effects) != len(snap.Status.Effects) {
+		return nil, errors.Errorf(
+			"mismatching status effects of soldier %s",
+			snap.ID,
+		)
+	}
+	s.command = b.commands[snap.ID.Faction]
+	s.repeater = b
+	s.pacer = b
+	s.maxHealth = snap.MaxHealth
+	s.status = snap.Status
+	s.status.Effects = append([]ActiveEffect(nil), snap.Status.Effects...)
+	s.stats = snap.Stats
+	s.isCommander = snap.Commander
+	s.hitChanceBonus = snap.HitChanceBonus
+	s.effectSeq = snap.EffectSeq
+	s.restingSince = snap.RestingSince
+
+	for i := range snap.Effects {
+		effect := &snap.Effects[i]
+		s.status.Effects[i].id = effect.ID
+		s.status.Effects[i].interval = effect.Interval
+		if !effect.Timer.Running {
+			continue
+		}
+		tk, id, timer := tickers[&effect.Timer], effect.ID, effect.Timer
+		s.status.Effects[i].ticker = tk
+		b.resumptions = append(b.resumptions, func(
+			ctx context.Context,
+			wg *sync.WaitGroup,
+		) {
+			b.repeatAfter(
+				ctx,
+				wg,
+				tk,
+				timer.Remaining,
+				timer.Interval,
+				func() bool { return s.tickEffect(id) },
+			)
+		})
+	}
+
+	if s.status.Health <= 0 || s.status.Fled {
+		// The soldier left the battlefield
+		close(s.endOfLife)
+		s.actionTicker.Reset(0)
+	} else if snap.Action.Running {
+		action := snap.Action
+		s.resume = &action
+	}
+	return s, nil
+}
+
+// restoreSquads restores the squads of the faction and their orders
+func (b *Battle) restoreSquads(
+	faction *SnapshotFaction,
+	lookup func(SoldierID) (Soldier, error),
+) error {
+	cmd := b.commands[faction.Name]
+	for i, snap := range faction.Squads {
+		sq := &Squad{
+			lock:    &sync.Mutex{},
+			battle:  b,
+			index:   i,
+			faction: faction.Name,
+			order:   Order{Kind: snap.Order},
+		}
+		if snap.Target != nil {
+			target, err := lookup(*snap.Target)
+			if err != nil {
+				return err
+			}
+			sq.order.Target = target
+		}
+		for _, id := range snap.Members {
+			member, err := lookup(id)
+			if err != nil {
+				return err
+			}
+			member.(*soldier).squad = sq
+			sq.members = append(sq.members, member)
+		}
+		cmd.squads = append(cmd.squads, sq)
+	}
+	return nil
+}
+
+// restoreTriggers restores the waves of reinforcements
+// and the alliance breakup of the faction
+func (b *Battle) restoreTriggers(
+	faction *SnapshotFaction,
+	tickers map[*SnapshotTimer]*DynamicTicker,
+) error {
+	waves := b.waves[faction.Name]
+	if len(faction.Waves) != len(waves) {
+		return errors.Errorf(
+			"%d waves of faction %s restored with %d waves",
+			len(faction.Waves),
+			faction.Name,
+			len(waves),
+		)
+	}
+	for i, w := range waves {
+		snap := faction.Waves[i]
+		w.triggered = snap.Triggered
+		w.arrived = snap.Arrived
+		if snap.Timer.Running {
+			w := w
+			w.timer = tickers[&faction.Waves[i].Timer]
+			b.resumeAfter(w.timer, snap.Timer, func() error {
+				return b.arrive(w)
+			})
+		}
+	}
+
+	var ab *allianceBreak
+	for _, candidate := range b.breaks {
+		if candidate.faction == faction.Name {
+			ab = candidate
+		}
+	}
+	if (ab == nil) != (faction.AllianceBreak == nil) {
+		return errors.Errorf(
+			"mismatching alliance break of faction %s",
+			faction.Name,
+		)
+	}
+	if ab == nil {
+		return nil
+	}
+	ab.fired = faction.AllianceBreak.Fired
+	if timer := faction.AllianceBreak.Timer; timer.Running {
+		ab.timer = tickers[&faction.AllianceBreak.Timer]
+		b.resumeAfter(ab.timer, timer, func() error {
+			return b.breakAlliance(ab)
+		})
+	}
+	return nil
+}
+
+// resumeAfter makes the restored battle run fn once the timer
+// resumed by the given ticker ticks (see after)
+func (b *Battle) resumeAfter(
+	tk *DynamicTicker,
+	timer SnapshotTimer,
+	fn func() error,
+) {
+	b.resumptions = append(b.resumptions, func(
+		ctx context.Context,
+		wg *sync.WaitGroup,
+	) {
+		tk.resetAfter(timer.Remaining, timer.Interval)
+		b.after(ctx, wg, tk, fn)
+	})
+}
+
+// resumeActionTicker resumes the action timer of a soldier restored
+// from a snapshot and returns false if there's none to resume
+//
+// This method is thread-safe
+func (s *soldier) resumeActionTicker() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.resume == nil {
+		return false
+	}
+	s.actionTicker.resetAfter(s.resume.Remaining, s.resume.Interval)
+	s.resume = nil
+	return true
+}
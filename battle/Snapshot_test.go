@@ -0,0 +1,113 @@
+package battle
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSnapshotRestore makes sure sequential battles restored
+// from snapshots continue the same way the original battles do
+func TestSnapshotRestore(t *testing.T) {
+	for _, scenario := range testScenarios() {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			original := newVirtualBattle(t, scenario)
+			result := awaitResult(t, runAsync(context.Background(), original))
+			expected := encodeLog(t, original.Statistics().Log())
+
+			// The battle is decided before its last events are logged
+			for _, at := range []int{
+				1,
+				len(expected) / 4,
+				len(expected) / 2,
+				len(expected) * 3 / 4,
+			} {
+				snapshot := snapshotAfter(t, newVirtualBattle(t, scenario), at)
+
+				// Snapshots are restored from their serialized form
+				buf := &bytes.Buffer{}
+				if err := snapshot.Encode(buf); err != nil {
+					t.Fatal(err)
+				}
+				decoded, err := DecodeSnapshot(buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				config := scenario.config
+				config.Clock = NewVirtualClock(decoded.Recording.Start)
+				restored, err := RestoreBattle(
+					decoded,
+					config,
+					scenario.factions()...,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				restoredResult := awaitResult(
+					t,
+					runAsync(context.Background(), restored),
+				)
+				compareLogs(
+					t,
+					encodeLog(t, restored.Statistics().Log()),
+					expected,
+				)
+				if !reflect.DeepEqual(restoredResult.Winners, result.Winners) {
+					t.Errorf(
+						"restored after %d events won by %v, expected %v",
+						at,
+						restoredResult.Winners,
+						result.Winners,
+					)
+				}
+			}
+		})
+	}
+}
+
+// snapshotAfter runs the battle and snapshots it after the action
+// that logged the given number of events
+func snapshotAfter(t *testing.T, btl *Battle, events int) *Snapshot {
+	t.Helper()
+
+	// The battle is paused by the subscriber and snapshotted by the test
+	// since subscribers can't take snapshots of the battle they're blocking
+	sub := btl.Statistics().Subscribe(0, OverflowBlock)
+	reached := make(chan struct{})
+	go func() {
+		logged := 0
+		for range sub.C() {
+			logged++
+			if logged == events {
+				btl.Pause()
+				close(reached)
+			}
+		}
+	}()
+	done := runAsync(context.Background(), btl)
+
+	select {
+	case <-reached:
+	case <-time.After(testTimeout):
+		t.Fatalf("the battle didn't log %d events", events)
+	}
+	snapshot, err := btl.Snapshot()
+	btl.Resume()
+	awaitResult(t, done)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot
+}
+
+// newVirtualBattle creates a new battle of the scenario in virtual time
+func newVirtualBattle(t *testing.T, scenario testScenario) *Battle {
+	t.Helper()
+	config := scenario.config
+	config.Clock = NewVirtualClock(time.Unix(0, 0))
+	return newTestBattle(t, config, scenario.factions()...)
+}
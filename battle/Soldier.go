@@ -2,6 +2,7 @@ package battle
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
@@ -22,11 +23,20 @@ type Soldier interface {
 	// IsAlive returns true if the soldier is still alive
 	IsAlive() bool
 
+	// Squad returns the squad of the soldier or nil if it's not in a squad
+	Squad() *Squad
+
+	// IsCommander returns true if the soldier commands its faction
+	IsCommander() bool
+
+	// Equipment returns the equipment the soldier carries
+	Equipment() Equipment
+
 	// JoinBattle makes a soldier join the battle
 	JoinBattle(ctx context.Context)
 
-	// TakeDamage makes a soldier take damage and returns an error
-	// if the attack was successfully dodged
+	// TakeDamage makes a soldier take damage mitigated by its armor
+	// and returns an error if the attack was successfully dodged or blocked
 	TakeDamage(
 		from Soldier,
 		damage float64,
@@ -37,14 +47,32 @@ type Soldier interface {
 	)
 
 	// Attack makes a soldier attack an opponent and returns an error
-	// if the attack was successfully dodged by the opponent or the soldier
-	// missed
+	// if the attack was successfully dodged by the opponent, the soldier
+	// missed or the opponent already left the battlefield
 	Attack(opponent Soldier) (
 		damageDealt float64,
 		killed bool,
 		err error,
 	)
 
+	// ReceiveHealing makes a soldier restore health-points up to its
+	// maximum health and returns the health-points actually restored
+	ReceiveHealing(
+		from Soldier,
+		healing float64,
+	) (
+		healingReceived float64,
+		err error,
+	)
+
+	// Heal makes a medic heal an ally and returns an error
+	// if the ally is either dead or fled
+	Heal(ally Soldier) (healingDone float64, err error)
+
+	// Afflict inflicts a status effect on the soldier and returns an error
+	// if the soldier is either dead or fled
+	Afflict(from Soldier, effect StatusEffect) error
+
 	// AddMorale increases or decreases the morale depending on whether
 	// a positive or a negative percentage was passed
 	AddMorale(percent float64) (
@@ -63,21 +91,47 @@ type soldier struct {
 	actionTicker *DynamicTicker
 	endOfLife    chan struct{}
 	attrs        SoldierAttributes
+	equipment    Equipment
 	id           SoldierID
 	maxHealth    float64
 	status       SoldierStatus
 	stats        SoldierStatistics
 	battleConfig Config
+	rng          *rng
 	battlefield  Battlefield
 	battleLog    LogWriter
+
+	// repeater makes the status effects tick during the battle
+	repeater  repeater
+	effectSeq uint64
+
+	// pacer provides the base action delay of the running battle
+	pacer pacer
+
+	// restingSince is the battle time since which the soldier
+	// is yet to regain stamina (see recoverStamina)
+	restingSince time.Duration
+
+	// squad and command are set by the battle when forming the army
+	squad       *Squad
+	command     *command
+	isCommander bool
+
+	// hitChanceBonus is granted by the aura of the commander
+	hitChanceBonus float64
+
+	// resume is the action timer of a soldier restored from a snapshot
+	resume *SnapshotTimer
 }
 
 // newSoldier creates a new randomly parameterized soldier instance
 func newSoldier(
-	name string,
-	factionName string,
+	id SoldierID,
 	attrs SoldierAttributes,
+	equipment Equipment,
 	battleConfig Config,
+	rng *rng,
+	actionTicker *DynamicTicker,
 	battlefield Battlefield,
 	battleLog LogWriter,
 ) (*soldier, error) {
@@ -89,34 +143,46 @@ func newSoldier(
 		return nil, errors.New("missing battle log writer")
 	}
 
+	if rng == nil {
+		return nil, errors.New("missing random number generator")
+	}
+
+	if actionTicker == nil {
+		return nil, errors.New("missing action ticker")
+	}
+
 	// Verify the input attributes
 	if err := attrs.Verify(); err != nil {
 		return nil, errors.Wrap(err, "invalid attributes")
 	}
+	if err := equipment.Verify(); err != nil {
+		return nil, errors.Wrap(err, "invalid equipment")
+	}
 
 	// Determine random max health
-	maxHealth := random(attrs.HealthMin, attrs.HealthMax)
+	maxHealth := rng.random(attrs.HealthMin, attrs.HealthMax)
 
 	// Verify faction name
-	if len(factionName) < 1 {
-		return nil, errors.Errorf("invalid faction name: '%s'", factionName)
+	if len(id.Faction) < 1 {
+		return nil, errors.Errorf("invalid faction name: '%s'", id.Faction)
 	}
 
 	return &soldier{
 		lock:         &sync.Mutex{},
-		actionTicker: NewDynamicTicker(),
+		actionTicker: actionTicker,
 		endOfLife:    make(chan struct{}),
-		id: SoldierID{
-			Faction: factionName,
-			Name:    name,
-		},
+		id:           id,
 		status: SoldierStatus{
-			Health: maxHealth,
-			Morale: 1.0,
+			Health:    maxHealth,
+			MaxHealth: maxHealth,
+			Morale:    1.0,
+			Stamina:   1.0,
 		},
 		maxHealth:    maxHealth,
 		attrs:        attrs,
+		equipment:    equipment,
 		battleConfig: battleConfig,
+		rng:          rng,
 		battlefield:  battlefield,
 		battleLog:    battleLog,
 	}, nil
@@ -144,7 +210,11 @@ func (s *soldier) ResetActionTicker() time.Duration {
 // and resets the action ticker
 func (s *soldier) resetActionTicker() time.Duration {
 	// Affect action ticker
-	actionDelay := s.calculateActionDelay(s.battleConfig.BaseActionDelay)
+	baseDelay := s.battleConfig.BaseActionDelay
+	if s.pacer != nil {
+		baseDelay = s.pacer.baseActionDelay()
+	}
+	actionDelay := s.calculateActionDelay(baseDelay)
 	s.actionTicker.Reset(actionDelay)
 	return actionDelay
 }
@@ -167,6 +237,13 @@ func (s *soldier) addMorale(percent float64) (
 	newMorale float64,
 	newActionDelay time.Duration,
 ) {
+	newMorale = s.changeMorale(percent)
+	newActionDelay = s.resetActionTicker()
+	return
+}
+
+// changeMorale changes the morale without resetting the action ticker
+func (s *soldier) changeMorale(percent float64) float64 {
 	if percent < -1 || percent > 1 {
 		panic(errors.Errorf("invalid percentage value: %.1f", percent))
 	}
@@ -175,7 +252,11 @@ func (s *soldier) addMorale(percent float64) (
 	// whether the morale is increased or decreased
 	factor := s.attrs.MoraleIncrementFactor
 	if percent < 0 {
-		factor = s.attrs.MoraleDecrementFactor
+		// Veterans are more resilient
+		factor = s.attrs.MoraleDecrementFactor * math.Max(
+			1-s.levelBonus(s.battleConfig.Veterancy.MoraleResilience),
+			0,
+		)
 	}
 
 	s.status.Morale += percent * factor
@@ -186,19 +267,325 @@ func (s *soldier) addMorale(percent float64) (
 		// No morale
 		s.status.Morale = 0
 	}
-	newMorale = s.status.Morale
-	newActionDelay = s.resetActionTicker()
+	return s.status.Morale
+}
+
+// fatigue returns the share of stamina the soldier lost
+func (s *soldier) fatigue() float64 {
+	return 1 - s.status.Stamina
+}
+
+// drainStamina decreases the soldier's stamina by the given percentage
+// without resetting the action ticker
+func (s *soldier) drainStamina(percent float64) {
+	s.status.Stamina = math.Max(s.status.Stamina-percent, 0)
+}
+
+// recoverStamina makes the soldier regain stamina for the time
+// it spent without attacking or dodging since it last recovered
+// and returns true if the stamina changed.
+// Expects the soldier lock to be locked
+func (s *soldier) recoverStamina() bool {
+	if s.pacer == nil {
+		return false
+	}
+	now := s.pacer.battleTime()
+	rested := now - s.restingSince
+	s.restingSince = now
+
+	baseDelay := s.pacer.baseActionDelay()
+	if rested <= 0 || baseDelay <= 0 ||
+		s.status.Stamina >= 1 || s.attrs.StaminaRegeneration <= 0 {
+		return false
+	}
+	s.status.Stamina = math.Min(
+		s.status.Stamina+
+			s.attrs.StaminaRegeneration*float64(rested)/float64(baseDelay),
+		1,
+	)
+	return true
+}
+
+// rest makes an idle soldier regenerate stamina
+//
+// This method is thread-safe
+func (s *soldier) rest() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.recoverStamina() {
+		s.resetActionTicker()
+	}
+}
+
+// demoralize decreases the soldier's morale by the given percentage
+// without resetting the action ticker
+//
+// This method is thread-safe
+func (s *soldier) demoralize(percent float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.status.Health <= 0 || s.status.Fled {
+		return
+	}
+	s.changeMorale(-percent)
+}
 
+// shareMorale changes the soldier's morale in reaction to a comrade
+// being killed or killing an opponent. The new action delay takes effect
+// on the next reset of the action ticker
+//
+// This method is thread-safe
+func (s *soldier) shareMorale(comradeKilled bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.status.Health <= 0 || s.status.Fled {
+		return
+	}
+	if comradeKilled {
+		s.changeMorale(-s.attrs.ComradeDeathMoralePenalty)
+		return
+	}
+	s.changeMorale(s.attrs.ComradeKillMoraleBonus)
+}
+
+// checkMorale routs the soldier if its morale broke or rallies it
+// if its morale recovered and returns true if the soldier is routed
+func (s *soldier) checkMorale() (routed bool) {
+	s.lock.Lock()
+	wasRouted := s.status.Routed
+	if s.status.Health > 0 {
+		threshold := s.attrs.MoraleBreakThreshold
+		if !wasRouted && s.status.Morale < threshold {
+			s.status.Routed = true
+		} else if wasRouted && s.status.Morale >= threshold {
+			s.status.Routed = false
+		}
+	}
+	routed = s.status.Routed
+	morale := s.status.Morale
+	snapshot := s.logSnapshot()
+	s.lock.Unlock()
+
+	if routed == wasRouted {
+		return
+	}
+
+	if routed {
+		if err := s.battlefield.MarkRouted(s); err != nil {
+			panic(errors.Wrap(err, "unexpected error during MarkRouted"))
+		}
+		if err := s.battleLog.PushEvent(EventRout{
+			Soldier: s,
+			Morale:  morale,
+		}, snapshot); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if err := s.battlefield.MarkRallied(s); err != nil {
+		panic(errors.Wrap(err, "unexpected error during MarkRallied"))
+	}
+	if err := s.battleLog.PushEvent(EventRally{
+		Soldier: s,
+		Morale:  morale,
+	}, snapshot); err != nil {
+		panic(err)
+	}
 	return
 }
 
+// flee makes a routed soldier try to leave the battlefield.
+// On a spatial battlefield the soldier runs toward the nearest edge,
+// otherwise the chance to escape equals the soldier's dodge chance
+func (s *soldier) flee() {
+	// Getting away from the fight restores some morale
+	// Increase morale by 5%
+	s.AddMorale(0.05)
+
+	escaped := false
+	if field, isSpatial := s.battlefield.(SpatialBattlefield); isSpatial {
+		from, to, reachedEdge, err := field.Retreat(s, s.attrs.MovementSpeed)
+		if err != nil {
+			panic(errors.Wrap(err, "unexpected retreat err"))
+		}
+		if from != to {
+			if err := s.battleLog.PushEvent(EventMove{
+				Soldier: s,
+				From:    from,
+				To:      to,
+			}, snapshotSoldiers(s)...); err != nil {
+				panic(err)
+			}
+		}
+		escaped = reachedEdge
+	} else {
+		escaped = s.rng.luck(s.rng.random(
+			s.attrs.DodgeChanceMin,
+			s.attrs.DodgeChanceMax,
+		))
+	}
+	if !escaped {
+		return
+	}
+
+	s.lock.Lock()
+	if s.status.Health <= 0 {
+		// Killed by a pursuer in the meantime
+		s.lock.Unlock()
+		return
+	}
+	s.status.Fled = true
+	s.endLife(false)
+	err := s.battlefield.MarkFled(s)
+	snapshot := s.logSnapshot()
+	s.lock.Unlock()
+	if err != nil {
+		panic(errors.Wrap(err, "unexpected error during MarkFled"))
+	}
+
+	if err := s.battleLog.PushEvent(
+		EventFlee{Soldier: s},
+		snapshot,
+	); err != nil {
+		panic(err)
+	}
+}
+
+// receiveAura applies the aura of the faction's commander (if any)
+func (s *soldier) receiveAura() {
+	aura, err := s.command.aura(s, s.battlefield)
+	if err != nil {
+		panic(errors.Wrap(err, "unexpected aura err"))
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.hitChanceBonus = aura.HitChance
+	if aura.Morale > 0 {
+		s.changeMorale(aura.Morale)
+	}
+}
+
+// movementSpeed returns the distance the soldier moves per action
+// on a spatial battlefield
+func (s *soldier) movementSpeed() float64 {
+	if s.squad != nil && s.squad.Order().Kind == OrderHold {
+		return 0
+	}
+	return s.attrs.MovementSpeed
+}
+
+// attackRange returns the maximum distance to attack an opponent from
+// on a spatial battlefield
+func (s *soldier) attackRange() float64 {
+	if s.equipment.Weapon != nil && s.equipment.Weapon.Range > 0 {
+		return s.equipment.Weapon.Range
+	}
+	return s.attrs.AttackRange
+}
+
+// isMedic returns true if the soldier heals allies
+func (s *soldier) isMedic() bool {
+	return s.attrs.HealingMax > 0
+}
+
+// tend makes a medic heal the wounded ally found on the battlefield
+// and returns false if no ally needs healing
+func (s *soldier) tend() bool {
+	ally, err := s.battlefield.FindAlly(s)
+	switch err {
+	case ErrNoWoundedAllies:
+		return false
+	case nil:
+	default:
+		panic(errors.Wrap(err, "unexpected ally search err"))
+	}
+
+	if field, isSpatial := s.battlefield.(SpatialBattlefield); isSpatial {
+		// Get within range before healing
+		from, to, inRange, err := field.Approach(
+			s,
+			ally,
+			s.movementSpeed(),
+			s.attrs.HealRange,
+		)
+		if err != nil {
+			panic(errors.Wrap(err, "unexpected approach err"))
+		}
+		if !inRange {
+			if from != to {
+				if err := s.battleLog.PushEvent(EventMove{
+					Soldier: s,
+					Target:  ally,
+					From:    from,
+					To:      to,
+				}, snapshotSoldiers(s, ally)...); err != nil {
+					panic(err)
+				}
+			}
+			return true
+		}
+	}
+
+	healingDone, err := s.Heal(ally)
+	switch err {
+	case ErrDead, ErrFled:
+		// The ally left the battlefield in the meantime
+		return true
+	case nil:
+	default:
+		panic(errors.Wrap(err, "unexpected healing err"))
+	}
+
+	if err := s.battleLog.PushEvent(EventHeal{
+		Healer:  s,
+		Healed:  ally,
+		Healing: healingDone,
+	}, snapshotSoldiers(s, ally)...); err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// findOpponent returns either the focus target of the soldier's squad
+// or an opponent found on the battlefield
+func (s *soldier) findOpponent() (Soldier, error) {
+	if s.squad != nil {
+		if target := s.squad.focusTarget(); target != nil {
+			return target, nil
+		}
+	}
+	return s.battlefield.FindOpponent(s)
+}
+
 func (s *soldier) takeAction() {
+	if s.isStunned() {
+		// Stunned soldiers skip their actions
+		return
+	}
+	if s.checkMorale() {
+		// Routed soldiers don't fight
+		s.flee()
+		return
+	}
+	s.receiveAura()
+
+	// Medics heal wounded allies instead of attacking
+	if s.isMedic() && s.tend() {
+		return
+	}
+
 	// Find an opponent
-	opponent, err := s.battlefield.FindOpponent(s.id.Faction)
+	opponent, err := s.findOpponent()
 	switch err {
 	case ErrNoMoreOpponents:
-		// The battle is won! No more opponents are left on the battlefield
-		s.endLife(false)
+		// No more opponents are left on the battlefield, wait for either
+		// the battle to be decided or reinforcements to arrive
+		s.rest()
 		return
 	case nil:
 		// A new opponent is found
@@ -217,29 +604,74 @@ func (s *soldier) takeAction() {
 		}
 	}
 
+	if field, isSpatial := s.battlefield.(SpatialBattlefield); isSpatial {
+		// Get within range before attacking unless holding the position
+		from, to, inRange, err := field.Approach(
+			s,
+			opponent,
+			s.movementSpeed(),
+			s.attackRange(),
+		)
+		if err != nil {
+			panic(errors.Wrap(err, "unexpected approach err"))
+		}
+		if !inRange {
+			if from == to {
+				// Holding the position
+				s.rest()
+				return
+			}
+			panicOnErr(s.battleLog.PushEvent(EventMove{
+				Soldier: s,
+				Target:  opponent,
+				From:    from,
+				To:      to,
+			}, snapshotSoldiers(s, opponent)...))
+			return
+		}
+	}
+
 	// Try to deal some damage to the opponent and log any event
-	damageDealt, killed, err := s.Attack(opponent)
+	damageDealt, killed, critical, err := s.attack(opponent)
+	stamina := s.Status().Stamina
 	switch err {
+	case ErrDead, ErrFled:
+		// The opponent left the battlefield in the meantime
+		// by a status effect or by fleeing
 	case ErrDodged:
 		// Dammit, the opponent dodged!
 		// Decrease morale by 5%
 		moralePenalty := -0.05
 		s.AddMorale(moralePenalty)
 		panicOnErr(s.battleLog.PushEvent(EventDodge{
-			Attacker:      s,
-			Defernder:     opponent,
-			MoralePenalty: moralePenalty,
-		}))
+			Attacker:        s,
+			Defernder:       opponent,
+			MoralePenalty:   moralePenalty,
+			AttackerStamina: stamina,
+			DefenderStamina: opponent.Status().Stamina,
+		}, snapshotSoldiers(s, opponent)...))
+	case ErrBlocked:
+		// Dammit, the opponent blocked with the shield!
+		// Decrease morale by 5%
+		moralePenalty := -0.05
+		s.AddMorale(moralePenalty)
+		panicOnErr(s.battleLog.PushEvent(EventBlock{
+			Attacker:        s,
+			Defender:        opponent,
+			MoralePenalty:   moralePenalty,
+			AttackerStamina: stamina,
+		}, snapshotSoldiers(s, opponent)...))
 	case ErrMissed:
 		// Dammit, I missed!
 		// Decrease morale by 10%
 		moralePenalty := -.1
 		s.AddMorale(moralePenalty)
 		panicOnErr(s.battleLog.PushEvent(EventMiss{
-			Attacker:      s,
-			Attacked:      opponent,
-			MoralePenalty: moralePenalty,
-		}))
+			Attacker:        s,
+			Attacked:        opponent,
+			MoralePenalty:   moralePenalty,
+			AttackerStamina: stamina,
+		}, snapshotSoldiers(s, opponent)...))
 	case nil:
 		if killed {
 			// F@ck yeah! I killed one!
@@ -247,33 +679,56 @@ func (s *soldier) takeAction() {
 			moraleBonus := 0.5
 			s.AddMorale(moraleBonus)
 			panicOnErr(s.battleLog.PushEvent(EventKill{
-				Attacker:    s,
-				Killed:      opponent,
-				DamageDealt: damageDealt,
-				MoraleBonus: moraleBonus,
-			}))
+				Attacker:        s,
+				Killed:          opponent,
+				DamageDealt:     damageDealt,
+				MoraleBonus:     moraleBonus,
+				AttackerStamina: stamina,
+				Critical:        critical,
+			}, snapshotSoldiers(s, opponent)...))
 		} else {
 			// Fine! I dealt some damage!
 			// Increase morale by 5%
 			moraleBonus := 0.05
 			s.AddMorale(moraleBonus)
 			panicOnErr(s.battleLog.PushEvent(EventHit{
-				Attacker:    s,
-				Attacked:    opponent,
-				DamageDealt: damageDealt,
-				MoraleBonus: moraleBonus,
-			}))
+				Attacker:        s,
+				Attacked:        opponent,
+				DamageDealt:     damageDealt,
+				MoraleBonus:     moraleBonus,
+				AttackerStamina: stamina,
+				Critical:        critical,
+			}, snapshotSoldiers(s, opponent)...))
+			s.inflictEffects(opponent)
+		}
+
+		if level, leveledUp := s.gainExperience(
+			damageDealt,
+			killed,
+		); leveledUp {
+			panicOnErr(s.battleLog.PushEvent(EventLevelUp{
+				Soldier:    s,
+				Level:      level,
+				Experience: s.Status().Experience,
+			}, snapshotSoldiers(s)...))
 		}
 	}
 }
 
 // calculateActionDelay calculates the delay for the next action based on
-// the current morale percentage
+// the current morale and stamina percentages
 func (s *soldier) calculateActionDelay(
 	baseDelay time.Duration,
 ) time.Duration {
+	if s.equipment.Weapon != nil && s.equipment.Weapon.AttackSpeed > 0 {
+		baseDelay = time.Duration(
+			float64(baseDelay) / s.equipment.Weapon.AttackSpeed,
+		)
+	}
 	penalty := time.Duration(float64(baseDelay) * s.status.Morale / 2)
-	return baseDelay - penalty
+
+	// Exhaustion up to doubles the delay
+	return time.Duration(float64(baseDelay-penalty) * (1 + s.fatigue()))
 }
 
 // JoinBattle implements the Soldier interface
@@ -282,7 +737,9 @@ func (s *soldier) JoinBattle(ctx context.Context) {
 		// Cleanup
 		s.actionTicker.Reset(0)
 	}()
-	s.ResetActionTicker()
+	if !s.resumeActionTicker() {
+		s.ResetActionTicker()
+	}
 
 LIFE_LOOP:
 	for {
@@ -292,8 +749,16 @@ LIFE_LOOP:
 			break LIFE_LOOP
 
 		case <-s.actionTicker.C():
-			// Time to take some action!
-			s.takeAction()
+			// Time to take some action unless the battle
+			// was decided in the meantime
+			if ctx.Err() == nil {
+				if s.pacer != nil {
+					s.pacer.act(s.takeAction)
+				} else {
+					s.takeAction()
+				}
+			}
+			s.actionTicker.Ack()
 
 		case <-s.endOfLife:
 			// Death
@@ -305,6 +770,7 @@ LIFE_LOOP:
 func (s *soldier) endLife(dueToDeath bool) {
 	// End the life-loop in case of a lethal strike
 	close(s.endOfLife)
+	s.actionTicker.Reset(0)
 
 	// Mark the soldier as killed
 	if dueToDeath {
@@ -330,17 +796,58 @@ func (s *soldier) TakeDamage(
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	if luck(random(s.attrs.DodgeChanceMin, s.attrs.DodgeChanceMax)) {
-		// Successfully dodged the attack
+	if s.status.Health <= 0 {
+		// Concurrent attackers might still target a soldier that just died
+		return 0, false, ErrDead
+	}
+	if s.status.Fled {
+		// Concurrent attackers might still target a soldier that just fled
+		return 0, false, ErrFled
+	}
+
+	attacker := from.ID()
+	s.status.LastAttacker = &attacker
+
+	// Exhaustion up to halves the dodge chance
+	s.recoverStamina()
+	dodgeChance := s.rng.random(
+		s.attrs.DodgeChanceMin,
+		s.attrs.DodgeChanceMax,
+	) * (1 - s.fatigue()/2)
+	dodgeChance += s.levelBonus(s.battleConfig.Veterancy.DodgeChanceBonus)
+	if s.rng.luck(math.Min(dodgeChance, 1)) {
+		// Successfully dodged the attack at the cost of some stamina
+		s.drainStamina(s.attrs.StaminaDodgeCost)
 		// Increase morale by 25%
 		s.addMorale(0.25)
 		s.stats.Dodges++
 		return 0, false, ErrDodged
 	}
 
+	if shield := s.equipment.Shield; shield != nil &&
+		s.rng.luck(shield.BlockChance) {
+		// Successfully blocked the attack
+		// Increase morale by 10%
+		s.addMorale(0.1)
+		s.stats.Blocks++
+		return 0, false, ErrBlocked
+	}
+
+	if armor := s.equipment.Armor; armor != nil {
+		// The equipment never changes and is read without locking
+		// the attacker
+		damageType := ""
+		if weapon := from.Equipment().Weapon; weapon != nil {
+			damageType = weapon.DamageType
+		}
+		mitigated := armor.mitigate(damage, damageType)
+		s.stats.DamageMitigated += damage - mitigated
+		damage = mitigated
+	}
+
 	s.status.Health -= damage
 	s.stats.DamageTaken += damage
-	if s.status.Health < 0 {
+	if s.status.Health <= 0 {
 		// Die
 		s.status.Health = 0
 		s.endLife(true)
@@ -359,29 +866,71 @@ func (s *soldier) Attack(opponent Soldier) (
 	damageDealt float64,
 	killed bool,
 	err error,
+) {
+	damageDealt, killed, _, err = s.attack(opponent)
+	return
+}
+
+// attack attacks the opponent and returns whether the hit was critical
+func (s *soldier) attack(opponent Soldier) (
+	damageDealt float64,
+	killed bool,
+	critical bool,
+	err error,
 ) {
 	if opponent == nil {
-		return 0, false, errors.New("no opponent to attack")
+		return 0, false, false, errors.New("no opponent to attack")
 	}
 
 	s.lock.Lock()
-	defer s.lock.Unlock()
 
-	if !luck(random(s.attrs.HitChanceMin, s.attrs.HitChanceMax)) {
+	// Exhaustion up to halves the hit chance
+	s.recoverStamina()
+	hitChance := s.rng.random(s.attrs.HitChanceMin, s.attrs.HitChanceMax) *
+		(1 - s.fatigue()/2)
+	s.drainStamina(s.attrs.StaminaAttackCost)
+	hitChance += s.hitChanceBonus +
+		s.levelBonus(s.battleConfig.Veterancy.HitChanceBonus)
+	if !s.rng.luck(math.Min(hitChance, 1)) {
 		// Miss, no luck
 		s.stats.Misses++
-		return 0, false, ErrMissed
+		s.lock.Unlock()
+		return 0, false, false, ErrMissed
 	}
 
-	potentialDamage := random(
+	potentialDamage := s.rng.random(
 		s.attrs.AttackStrengthMin,
 		s.attrs.AttackStrengthMax,
 	)
+	if weapon := s.equipment.Weapon; weapon != nil {
+		potentialDamage += s.rng.random(weapon.DamageMin, weapon.DamageMax)
+	}
+	potentialDamage *= s.battleConfig.DamageModifiers.Multiplier(
+		s.id.Unit,
+		opponent.ID().Unit,
+	)
+	if s.attrs.CriticalChance > 0 && s.rng.luck(s.attrs.CriticalChance) {
+		critical = true
+		potentialDamage *= s.attrs.CriticalMultiplier
+	}
+	s.lock.Unlock()
+
+	// The lock isn't held while the opponent takes the damage
+	// to not deadlock soldiers attacking each other
 	damageDealt, killed, err = opponent.TakeDamage(s, potentialDamage)
-	if err != nil {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch err {
+	case nil:
+	case ErrDead, ErrFled:
+		// The opponent left the battlefield in the meantime,
+		// the attack neither hit nor missed
+		return 0, false, false, err
+	default:
 		// Opponent dodged the attack
 		s.stats.Misses++
-		return 0, false, err
+		return 0, false, false, err
 	}
 
 	// Hit, damage dealt
@@ -391,7 +940,58 @@ func (s *soldier) Attack(opponent Soldier) (
 	}
 	s.stats.DamageCaused += damageDealt
 
-	return damageDealt, killed, nil
+	return damageDealt, killed, critical, nil
+}
+
+// ReceiveHealing implements the Soldier interface
+func (s *soldier) ReceiveHealing(
+	from Soldier,
+	healing float64,
+) (
+	healingReceived float64,
+	err error,
+) {
+	if from == nil {
+		return 0, errors.New("no medic to receive healing from")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.status.Health <= 0 {
+		return 0, ErrDead
+	}
+	if s.status.Fled {
+		return 0, ErrFled
+	}
+
+	healingReceived = math.Min(healing, s.maxHealth-s.status.Health)
+	s.status.Health += healingReceived
+	s.stats.HealingReceived += healingReceived
+	return healingReceived, nil
+}
+
+// Heal implements the Soldier interface
+func (s *soldier) Heal(ally Soldier) (healingDone float64, err error) {
+	if ally == nil {
+		return 0, errors.New("no ally to heal")
+	}
+	if !s.isMedic() {
+		return 0, errors.Errorf("%s is not a medic", s.id)
+	}
+
+	// The lock isn't held while healing
+	// to not deadlock medics healing each other
+	healing := s.rng.random(s.attrs.HealingMin, s.attrs.HealingMax)
+	healingDone, err = ally.ReceiveHealing(s, healing)
+	if err != nil {
+		return 0, err
+	}
+
+	s.lock.Lock()
+	s.stats.HealingDone += healingDone
+	s.lock.Unlock()
+	return healingDone, nil
 }
 
 // ID implements the Soldier interface
@@ -399,17 +999,41 @@ func (s *soldier) ID() SoldierID {
 	return s.id
 }
 
+// Squad implements the Soldier interface
+func (s *soldier) Squad() *Squad {
+	return s.squad
+}
+
+// IsCommander implements the Soldier interface
+func (s *soldier) IsCommander() bool {
+	return s.isCommander
+}
+
+// Equipment implements the Soldier interface
+func (s *soldier) Equipment() Equipment {
+	return s.equipment
+}
+
 // Stats implements the Soldier interface
 func (s *soldier) Stats() SoldierStatistics {
+	s.lock.Lock()
 	stats := s.stats
 	s.lock.Unlock()
 	return stats
 }
 
+// logSnapshot returns the snapshot of the soldier's current status
+// to log along with an event (see SoldierSnapshot).
+// Expects the soldier lock to be locked
+func (s *soldier) logSnapshot() SoldierSnapshot {
+	return newSoldierSnapshot(s.id, s.status)
+}
+
 // Status implements the Soldier interface
 func (s *soldier) Status() SoldierStatus {
 	s.lock.Lock()
 	status := s.status
+	status.Effects = append([]ActiveEffect(nil), s.status.Effects...)
 	s.lock.Unlock()
 	return status
 }
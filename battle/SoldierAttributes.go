@@ -14,19 +14,117 @@ type SoldierAttributes struct {
 	HitChanceMax          float64
 	MoraleIncrementFactor float64
 	MoraleDecrementFactor float64
+
+	// MovementSpeed and AttackRange define the distance a soldier moves
+	// per action and the maximum distance to attack an opponent from
+	// on a spatial battlefield. The movement speed must be positive
+	// on a spatial battlefield
+	MovementSpeed float64
+	AttackRange   float64
+
+	// MoraleBreakThreshold defines the morale below which the soldier routs.
+	// A routed soldier rallies as soon as its morale recovers
+	// to the threshold. A zero threshold makes the soldier fight to the death
+	MoraleBreakThreshold float64
+
+	// ComradeDeathMoralePenalty and ComradeKillMoraleBonus define the morale
+	// percentages the soldier loses when a comrade is killed and gains
+	// when a comrade kills an opponent. They're scaled by the morale
+	// decrement and increment factors respectively
+	ComradeDeathMoralePenalty float64
+	ComradeKillMoraleBonus    float64
+
+	// CriticalChance defines the chance of a hit to be critical.
+	// Critical hits deal CriticalMultiplier times the damage
+	CriticalChance     float64
+	CriticalMultiplier float64
+
+	// StaminaAttackCost and StaminaDodgeCost define the stamina percentages
+	// the soldier loses per attack and per dodged attack.
+	// StaminaRegeneration defines the stamina percentage the soldier
+	// regains per base action delay it spends without attacking or dodging
+	StaminaAttackCost   float64
+	StaminaDodgeCost    float64
+	StaminaRegeneration float64
+
+	// HealingMin and HealingMax define the health-points a medic restores
+	// per action. Soldiers with a zero HealingMax aren't medics
+	HealingMin float64
+	HealingMax float64
+
+	// HealRange defines the maximum distance to heal an ally from
+	// on a spatial battlefield
+	HealRange float64
+}
+
+// AttributeError represents a verification error of a soldier attribute
+type AttributeError struct {
+	// Attribute is the name of the invalid SoldierAttributes field
+	Attribute string
+
+	// Message describes the problem
+	Message string
+}
+
+// Error implements the error interface
+func (err *AttributeError) Error() string {
+	return err.Message
+}
+
+// attributeErrorf creates a new attribute error
+func attributeErrorf(
+	attribute string,
+	format string,
+	args ...interface{},
+) *AttributeError {
+	return &AttributeError{
+		Attribute: attribute,
+		Message:   errors.Errorf(format, args...).Error(),
+	}
+}
+
+// verifyMobility returns an *AttributeError if the soldier can't move
+// on a spatial battlefield and would never reach its opponents
+func (attrs *SoldierAttributes) verifyMobility() error {
+	if attrs.MovementSpeed <= 0 {
+		return attributeErrorf(
+			"MovementSpeed",
+			"movement speed: invalid %.1f on a spatial battlefield",
+			attrs.MovementSpeed,
+		)
+	}
+	return nil
 }
 
-// Verify verifies attribute values
+// Verify verifies attribute values.
+// Returns an *AttributeError in case of an invalid attribute value
 func (attrs *SoldierAttributes) Verify() error {
-	verifyPercentage := func(attrName string, min, max float64) error {
+	verifyPercentage := func(
+		attrName string,
+		minField string,
+		maxField string,
+		min,
+		max float64,
+	) error {
 		if min < 0 || min > 1 {
-			return errors.Errorf("%s: invalid minimum %%: %.1f", attrName, min)
+			return attributeErrorf(
+				minField,
+				"%s: invalid minimum %%: %.1f",
+				attrName,
+				min,
+			)
 		}
 		if max < 0 || max > 1 {
-			return errors.Errorf("%s: invalid maximum %%: %.1f", attrName, max)
+			return attributeErrorf(
+				maxField,
+				"%s: invalid maximum %%: %.1f",
+				attrName,
+				max,
+			)
 		}
 		if min > max {
-			return errors.Errorf(
+			return attributeErrorf(
+				minField,
 				"%s: minimum (%.1f) is greater maximum (%.1f)",
 				attrName,
 				min,
@@ -36,9 +134,16 @@ func (attrs *SoldierAttributes) Verify() error {
 		return nil
 	}
 
-	verifyMinMax := func(attrName string, allowedMin, min, max float64) error {
-		if min < 1 {
-			return errors.Errorf(
+	verifyMinMax := func(
+		attrName string,
+		minField string,
+		allowedMin,
+		min,
+		max float64,
+	) error {
+		if min < allowedMin {
+			return attributeErrorf(
+				minField,
 				"%s: invalid min: %.1f (allowed min: %.1f)",
 				attrName,
 				min,
@@ -46,7 +151,8 @@ func (attrs *SoldierAttributes) Verify() error {
 			)
 		}
 		if min > max {
-			return errors.Errorf(
+			return attributeErrorf(
+				minField,
 				"%s: min (%.1f) greater max (%.1f)",
 				attrName,
 				min,
@@ -58,6 +164,7 @@ func (attrs *SoldierAttributes) Verify() error {
 
 	if err := verifyMinMax(
 		"health",
+		"HealthMin",
 		1,
 		attrs.HealthMin,
 		attrs.HealthMax,
@@ -66,21 +173,124 @@ func (attrs *SoldierAttributes) Verify() error {
 	}
 
 	if attrs.MoraleIncrementFactor < 0 {
-		return errors.Errorf(
+		return attributeErrorf(
+			"MoraleIncrementFactor",
 			"morale increment factor: invalid %.1f",
 			attrs.MoraleIncrementFactor,
 		)
 	}
 
 	if attrs.MoraleDecrementFactor < 0 {
-		return errors.Errorf(
+		return attributeErrorf(
+			"MoraleDecrementFactor",
 			"morale decrement factor: invalid %.1f",
 			attrs.MoraleDecrementFactor,
 		)
 	}
 
+	if attrs.MoraleBreakThreshold < 0 || attrs.MoraleBreakThreshold > 1 {
+		return attributeErrorf(
+			"MoraleBreakThreshold",
+			"morale break threshold: invalid %%: %.1f",
+			attrs.MoraleBreakThreshold,
+		)
+	}
+
+	if attrs.ComradeDeathMoralePenalty < 0 ||
+		attrs.ComradeDeathMoralePenalty > 1 {
+		return attributeErrorf(
+			"ComradeDeathMoralePenalty",
+			"comrade death morale penalty: invalid %%: %.1f",
+			attrs.ComradeDeathMoralePenalty,
+		)
+	}
+
+	if attrs.ComradeKillMoraleBonus < 0 || attrs.ComradeKillMoraleBonus > 1 {
+		return attributeErrorf(
+			"ComradeKillMoraleBonus",
+			"comrade kill morale bonus: invalid %%: %.1f",
+			attrs.ComradeKillMoraleBonus,
+		)
+	}
+
+	if attrs.CriticalChance < 0 || attrs.CriticalChance > 1 {
+		return attributeErrorf(
+			"CriticalChance",
+			"critical chance: invalid %%: %.1f",
+			attrs.CriticalChance,
+		)
+	}
+
+	if attrs.CriticalMultiplier < 0 ||
+		attrs.CriticalChance > 0 && attrs.CriticalMultiplier < 1 {
+		return attributeErrorf(
+			"CriticalMultiplier",
+			"critical multiplier: invalid %.1f (allowed min: 1)",
+			attrs.CriticalMultiplier,
+		)
+	}
+
+	if attrs.StaminaAttackCost < 0 || attrs.StaminaAttackCost > 1 {
+		return attributeErrorf(
+			"StaminaAttackCost",
+			"stamina attack cost: invalid %%: %.1f",
+			attrs.StaminaAttackCost,
+		)
+	}
+
+	if attrs.StaminaDodgeCost < 0 || attrs.StaminaDodgeCost > 1 {
+		return attributeErrorf(
+			"StaminaDodgeCost",
+			"stamina dodge cost: invalid %%: %.1f",
+			attrs.StaminaDodgeCost,
+		)
+	}
+
+	if attrs.StaminaRegeneration < 0 || attrs.StaminaRegeneration > 1 {
+		return attributeErrorf(
+			"StaminaRegeneration",
+			"stamina regeneration: invalid %%: %.1f",
+			attrs.StaminaRegeneration,
+		)
+	}
+
+	if attrs.MovementSpeed < 0 {
+		return attributeErrorf(
+			"MovementSpeed",
+			"movement speed: invalid %.1f",
+			attrs.MovementSpeed,
+		)
+	}
+
+	if attrs.AttackRange < 0 {
+		return attributeErrorf(
+			"AttackRange",
+			"attack range: invalid %.1f",
+			attrs.AttackRange,
+		)
+	}
+
+	if err := verifyMinMax(
+		"healing",
+		"HealingMin",
+		0,
+		attrs.HealingMin,
+		attrs.HealingMax,
+	); err != nil {
+		return err
+	}
+
+	if attrs.HealRange < 0 {
+		return attributeErrorf(
+			"HealRange",
+			"heal range: invalid %.1f",
+			attrs.HealRange,
+		)
+	}
+
 	if err := verifyMinMax(
 		"attack strength",
+		"AttackStrengthMin",
 		1,
 		attrs.AttackStrengthMin,
 		attrs.AttackStrengthMax,
@@ -90,6 +300,8 @@ func (attrs *SoldierAttributes) Verify() error {
 
 	if err := verifyPercentage(
 		"dodge chance",
+		"DodgeChanceMin",
+		"DodgeChanceMax",
 		attrs.DodgeChanceMin,
 		attrs.DodgeChanceMax,
 	); err != nil {
@@ -98,6 +310,8 @@ func (attrs *SoldierAttributes) Verify() error {
 
 	return verifyPercentage(
 		"hit chance",
+		"HitChanceMin",
+		"HitChanceMax",
 		attrs.HitChanceMin,
 		attrs.HitChanceMax,
 	)
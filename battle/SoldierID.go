@@ -6,9 +6,15 @@ import "fmt"
 type SoldierID struct {
 	Faction string
 	Name    string
+
+	// Unit is the unit type of the soldier (empty if unnamed)
+	Unit string
 }
 
 // String stringifies the soldier ID
 func (id SoldierID) String() string {
+	if id.Unit != "" {
+		return fmt.Sprintf("%s (%s %s)", id.Name, id.Faction, id.Unit)
+	}
 	return fmt.Sprintf("%s (%s)", id.Name, id.Faction)
 }
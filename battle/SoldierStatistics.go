@@ -19,4 +19,19 @@ type SoldierStatistics struct {
 
 	// Dodges represents the amount of dodged attacks
 	Dodges uint
+
+	// Blocks represents the amount of attacks blocked by the shield
+	Blocks uint
+
+	// DamageMitigated represents the total sum of damage
+	// absorbed by the armor
+	DamageMitigated float64
+
+	// HealingDone represents the total sum of health-points
+	// restored to allies
+	HealingDone float64
+
+	// HealingReceived represents the total sum of health-points
+	// restored by medics
+	HealingReceived float64
 }
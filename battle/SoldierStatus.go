@@ -5,6 +5,34 @@ type SoldierStatus struct {
 	// Health represents the health status in health-points
 	Health float64
 
+	// MaxHealth represents the health-points of the unharmed soldier
+	MaxHealth float64
+
 	// Morale represents the morale status in percent
 	Morale float64
+
+	// Stamina represents the stamina status in percent.
+	// Exhausted soldiers act slower, hit less often and dodge less often
+	Stamina float64
+
+	// LastAttacker represents the soldier that most recently attacked,
+	// it's nil if the soldier was never attacked
+	LastAttacker *SoldierID
+
+	// Routed is true while the soldier's morale is broken.
+	// Routed soldiers don't attack and try to flee the battlefield
+	Routed bool
+
+	// Fled is true if the soldier left the battlefield after routing
+	Fled bool
+
+	// Experience represents the experience gained by the soldier
+	// including the experience of previous battles
+	Experience float64
+
+	// Level represents the veterancy level reached (see Veterancy)
+	Level uint
+
+	// Effects represents the status effects currently affecting the soldier
+	Effects []ActiveEffect
 }
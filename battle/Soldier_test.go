@@ -0,0 +1,295 @@
+package battle
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestStaminaRecovery makes sure fighting soldiers regain stamina
+// for the time they spend between their attacks
+func TestStaminaRecovery(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		regeneration float64
+		exhausted    bool
+	}{
+		{name: "no regeneration", exhausted: true},
+		{name: "regeneration outpacing the costs", regeneration: .5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factions := testFactions(5, 200)
+			for i := range factions {
+				factions[i].SoldierAttributes.StaminaAttackCost = .1
+				factions[i].SoldierAttributes.StaminaRegeneration = tc.regeneration
+			}
+			btl := newTestBattle(t, Config{
+				BaseActionDelay: 10 * time.Millisecond,
+				Seed:            1,
+				Clock:           NewVirtualClock(time.Unix(0, 0)),
+			}, factions...)
+			awaitResult(t, runAsync(context.Background(), btl))
+
+			// The stamina of the attackers is logged after the attack
+			lowest := 1.0
+			for _, entry := range btl.Statistics().Log() {
+				stamina := lowest
+				switch ev := entry.Event.(type) {
+				case EventHit:
+					stamina = ev.AttackerStamina
+				case EventKill:
+					stamina = ev.AttackerStamina
+				case EventMiss:
+					stamina = ev.AttackerStamina
+				case EventDodge:
+					stamina = ev.AttackerStamina
+				case EventBlock:
+					stamina = ev.AttackerStamina
+				}
+				if stamina < lowest {
+					lowest = stamina
+				}
+			}
+			switch {
+			case tc.exhausted && lowest > 0:
+				t.Errorf("lowest stamina %.2f, expected exhaustion", lowest)
+			case !tc.exhausted && lowest < .9-1e-9:
+				t.Errorf("lowest stamina %.2f, expected at least .9", lowest)
+			}
+		})
+	}
+}
+
+// TestAttackAbsentOpponent makes sure attacks on opponents
+// that already left the battlefield aren't counted as misses
+func TestAttackAbsentOpponent(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		leave func(status *SoldierStatus)
+		err   error
+	}{
+		{
+			name:  "dead opponent",
+			leave: func(status *SoldierStatus) { status.Health = 0 },
+			err:   ErrDead,
+		},
+		{
+			name:  "fled opponent",
+			leave: func(status *SoldierStatus) { status.Fled = true },
+			err:   ErrFled,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factions := testFactions(1, 50)
+			factions[0].SoldierAttributes.HitChanceMin = 1
+			factions[0].SoldierAttributes.HitChanceMax = 1
+			btl := newTestBattle(t, Config{Seed: 1}, factions...)
+			attacker := btl.Soldiers("A")[0]
+			opponent := btl.Soldiers("B")[0].(*soldier)
+			opponent.lock.Lock()
+			tc.leave(&opponent.status)
+			opponent.lock.Unlock()
+
+			if _, _, err := attacker.Attack(opponent); err != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stats := attacker.Stats(); stats != (SoldierStatistics{}) {
+				t.Errorf("unexpected statistics: %+v", stats)
+			}
+		})
+	}
+}
+
+// TestExactKill makes sure hits leaving the opponent
+// at exactly zero health kill it
+func TestExactKill(t *testing.T) {
+	attrs := SoldierAttributes{
+		HealthMin:         10,
+		HealthMax:         10,
+		AttackStrengthMin: 5,
+		AttackStrengthMax: 5,
+		HitChanceMin:      1,
+		HitChanceMax:      1,
+	}
+	btl := newTestBattle(
+		t,
+		Config{
+			BaseActionDelay: 10 * time.Millisecond,
+			Seed:            1,
+			Clock:           NewVirtualClock(time.Unix(0, 0)),
+		},
+		Faction{Name: "A", ArmySize: 1, SoldierAttributes: attrs},
+		Faction{Name: "B", ArmySize: 1, SoldierAttributes: attrs},
+	)
+	result := awaitResult(t, runAsync(context.Background(), btl))
+	if result.Outcome != OutcomeVictory {
+		t.Fatalf("unexpected outcome: %s", result.Outcome)
+	}
+
+	loser := "A"
+	if result.Winners[0] == "A" {
+		loser = "B"
+	}
+	status := btl.Soldiers(loser)[0].Status()
+	if status.Health != 0 {
+		t.Errorf("loser left with %.2f health", status.Health)
+	}
+	kills := 0
+	for _, entry := range btl.Statistics().Log() {
+		if _, ok := entry.Event.(EventKill); ok {
+			kills++
+		}
+	}
+	if kills != 1 {
+		t.Errorf("%d kills logged, expected 1", kills)
+	}
+}
+
+// TestMorale makes sure soldiers rout once their morale breaks,
+// rally once it recovers and flee the battlefield while routed
+func TestMorale(t *testing.T) {
+	attrs := testAttributes(50)
+	attrs.MoraleBreakThreshold = .5
+	attrs.HitChanceMin = 0
+	attrs.HitChanceMax = 0
+	fleeing := attrs
+	fleeing.DodgeChanceMin = 1
+	fleeing.DodgeChanceMax = 1
+	steady := attrs
+	steady.DodgeChanceMin = 0
+	steady.DodgeChanceMax = 0
+
+	for _, tc := range []struct {
+		name   string
+		attrs  SoldierAttributes
+		rally  bool
+		routed bool
+		fled   bool
+		logged []Event
+	}{
+		{
+			name:   "rout",
+			attrs:  steady,
+			routed: true,
+			logged: []Event{EventRout{}},
+		},
+		{
+			name:   "rally",
+			attrs:  steady,
+			rally:  true,
+			logged: []Event{EventRout{}, EventRally{}, EventMiss{}},
+		},
+		{
+			name:   "flee",
+			attrs:  fleeing,
+			routed: true,
+			fled:   true,
+			logged: []Event{EventRout{}, EventFlee{}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			btl := newTestBattle(
+				t,
+				Config{BaseActionDelay: 10 * time.Millisecond, Seed: 1},
+				Faction{Name: "A", ArmySize: 1, SoldierAttributes: tc.attrs},
+				Faction{Name: "B", ArmySize: 1, SoldierAttributes: attrs},
+			)
+			s := btl.Soldiers("A")[0].(*soldier)
+
+			s.AddMorale(-.6)
+			s.takeAction()
+			if tc.rally {
+				s.AddMorale(.5)
+				s.takeAction()
+			}
+
+			status := s.Status()
+			if status.Routed != tc.routed || status.Fled != tc.fled {
+				t.Errorf("unexpected status: %+v", status)
+			}
+			btl.lock.Lock()
+			alive := len(btl.alive["A"])
+			routed := len(btl.routed["A"])
+			fled := len(btl.fled["A"])
+			btl.lock.Unlock()
+			switch {
+			case tc.fled && fled != 1,
+				!tc.fled && tc.routed && routed != 1,
+				!tc.routed && alive != 1:
+				t.Errorf("%d alive, %d routed, %d fled", alive, routed, fled)
+			}
+
+			log := btl.Statistics().Log()
+			if len(log) != len(tc.logged) {
+				t.Fatalf("%d events logged, expected %d", len(log), len(tc.logged))
+			}
+			for i, entry := range log {
+				if reflect.TypeOf(entry.Event) != reflect.TypeOf(tc.logged[i]) {
+					t.Errorf("logged %T, expected %T", entry.Event, tc.logged[i])
+				}
+			}
+		})
+	}
+}
+
+// TestHeal makes sure medics heal their wounded allies
+// up to their maximum health
+func TestHeal(t *testing.T) {
+	attrs := testAttributes(50)
+	attrs.DodgeChanceMin = 0
+	attrs.DodgeChanceMax = 0
+	medic := attrs
+	medic.HealingMin = 10
+	medic.HealingMax = 10
+
+	for _, tc := range []struct {
+		name     string
+		damage   float64
+		expected float64
+	}{
+		{name: "heavily wounded", damage: 30, expected: 10},
+		{name: "lightly wounded", damage: 4, expected: 4},
+		{name: "unwounded"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			btl := newTestBattle(
+				t,
+				Config{Seed: 1},
+				Faction{Name: "A", Units: []Unit{
+					{Type: "medic", Count: 1, SoldierAttributes: medic},
+					{Type: "infantry", Count: 1, SoldierAttributes: attrs},
+				}},
+				Faction{Name: "B", ArmySize: 1, SoldierAttributes: attrs},
+			)
+			healer, patient := btl.Soldiers("A")[0], btl.Soldiers("A")[1]
+			if tc.damage > 0 {
+				if _, _, err := patient.TakeDamage(
+					btl.Soldiers("B")[0],
+					tc.damage,
+				); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			healing, err := healer.Heal(patient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if healing != tc.expected {
+				t.Errorf("healed %.2f, expected %.2f", healing, tc.expected)
+			}
+			if health := patient.Status().Health; health !=
+				50-tc.damage+tc.expected {
+				t.Errorf("%.2f health after healing", health)
+			}
+			if done := healer.Stats().HealingDone; done != tc.expected {
+				t.Errorf("%.2f healing done", done)
+			}
+			if received := patient.Stats().HealingReceived; received !=
+				tc.expected {
+				t.Errorf("%.2f healing received", received)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package battle
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -11,28 +12,72 @@ import (
 type LogEntry struct {
 	Time  time.Time
 	Event Event
+
+	// Soldiers represents the status of the soldiers involved in the event
+	// at the time the event was logged in the order they're referenced
+	Soldiers []SoldierSnapshot
+}
+
+// SoldierSnapshot represents the status of a soldier at the time of an event
+type SoldierSnapshot struct {
+	ID      SoldierID
+	Health  float64
+	Morale  float64
+	Stamina float64
+	Routed  bool
+}
+
+// Soldier returns the snapshot of the soldier of the given ID
+// and false if the soldier isn't involved in the event
+func (entry *LogEntry) Soldier(id SoldierID) (SoldierSnapshot, bool) {
+	for _, snapshot := range entry.Soldiers {
+		if snapshot.ID == id {
+			return snapshot, true
+		}
+	}
+	return SoldierSnapshot{}, false
 }
 
 // LogWriter allows writing to battle statistics
 type LogWriter interface {
-	PushEvent(event Event) error
+	// PushEvent logs an event along with the snapshots of the soldiers
+	// it references in the order they're referenced (see LogEntry.Soldiers).
+	// The snapshots are taken by the caller since the log never locks
+	// any soldiers
+	PushEvent(event Event, soldiers ...SoldierSnapshot) error
 }
 
+// LogStreamBufferSize defines the buffer size of the log stream
+const LogStreamBufferSize = 64
+
 // Statistics represents the battle statistics
 type Statistics struct {
 	lock          *sync.Mutex
+	clock         Clock
 	ended         bool
 	winnerFaction string
 	log           []LogEntry
-	logStream     chan LogEntry
+
+	// publishLock keeps the order of the published entries
+	// without blocking readers of the log. The entries before
+	// the published index were published to all subscribers.
+	// canceled stops blocking subscriptions from blocking a canceled battle
+	publishLock *sync.Mutex
+	published   int
+	canceled    <-chan struct{}
+	closed      bool
+	subscribers []*Subscription
+	logStream   *Subscription
 }
 
 // NewStatistics creates a new battle statistics instance
-func NewStatistics() *Statistics {
+// timestamping events according to the given clock
+func NewStatistics(clock Clock) *Statistics {
 	return &Statistics{
-		lock:      &sync.Mutex{},
-		ended:     false,
-		logStream: make(chan LogEntry),
+		lock:        &sync.Mutex{},
+		clock:       clock,
+		ended:       false,
+		publishLock: &sync.Mutex{},
 	}
 }
 
@@ -44,6 +89,13 @@ func (bstat *Statistics) WinnerFaction() string {
 	return winnerFaction
 }
 
+// setWinnerFaction sets the name of the winner faction
+func (bstat *Statistics) setWinnerFaction(factionName string) {
+	bstat.lock.Lock()
+	bstat.winnerFaction = factionName
+	bstat.lock.Unlock()
+}
+
 // Log implements the interface StatisticsReader
 func (bstat *Statistics) Log() []LogEntry {
 	bstat.lock.Lock()
@@ -54,52 +106,232 @@ func (bstat *Statistics) Log() []LogEntry {
 	return log
 }
 
+// restoreLog replaces the log by the given entries without publishing them
+func (bstat *Statistics) restoreLog(log []LogEntry) {
+	bstat.publishLock.Lock()
+	defer bstat.publishLock.Unlock()
+	bstat.lock.Lock()
+	defer bstat.lock.Unlock()
+	bstat.log = log
+	bstat.published = len(log)
+}
+
+// cancelOn makes blocking subscriptions stop blocking the battle
+// once the given context is canceled
+func (bstat *Statistics) cancelOn(ctx context.Context) {
+	bstat.publishLock.Lock()
+	bstat.canceled = ctx.Done()
+	bstat.publishLock.Unlock()
+}
+
 // LogStream implements the interface StatisticsReader
 func (bstat *Statistics) LogStream() <-chan LogEntry {
-	return bstat.logStream
+	bstat.publishLock.Lock()
+	defer bstat.publishLock.Unlock()
+
+	if bstat.logStream == nil {
+		bstat.logStream = bstat.subscribe(LogStreamBufferSize, OverflowBlock)
+	}
+	return bstat.logStream.C()
 }
 
-// PushEvent pushes a new log entry into the battle statistics
-func (bstat *Statistics) PushEvent(event Event) error {
+// Subscribe implements the interface StatisticsReader
+func (bstat *Statistics) Subscribe(
+	bufferSize uint,
+	policy OverflowPolicy,
+) *Subscription {
+	bstat.publishLock.Lock()
+	defer bstat.publishLock.Unlock()
+	return bstat.subscribe(bufferSize, policy)
+}
+
+// subscribe creates a new subscription.
+// Expects the publish lock to be locked
+func (bstat *Statistics) subscribe(
+	bufferSize uint,
+	policy OverflowPolicy,
+) *Subscription {
+	sub := newSubscription(bufferSize, policy)
+	if bstat.closed {
+		// Nothing will ever be published
+		close(sub.c)
+		return sub
+	}
+	bstat.subscribers = append(bstat.subscribers, sub)
+	return sub
+}
+
+// SubscribeWithLog implements the interface StatisticsReader
+func (bstat *Statistics) SubscribeWithLog(
+	bufferSize uint,
+	policy OverflowPolicy,
+) ([]LogEntry, *Subscription) {
+	// Holding the publish lock while subscribing keeps the entries
+	// not yet published from being missed or received twice
+	bstat.publishLock.Lock()
+	defer bstat.publishLock.Unlock()
+
 	bstat.lock.Lock()
-	defer bstat.lock.Unlock()
+	log := make([]LogEntry, bstat.published)
+	copy(log, bstat.log)
+	bstat.lock.Unlock()
+	return log, bstat.subscribe(bufferSize, policy)
+}
 
-	if bstat.ended {
-		return errors.New("the battle is already over")
+// Unsubscribe implements the interface StatisticsReader
+func (bstat *Statistics) Unsubscribe(sub *Subscription) {
+	// Release the publisher in case it's blocked on this subscription
+	sub.unblock()
+
+	bstat.publishLock.Lock()
+	defer bstat.publishLock.Unlock()
+
+	for i, s := range bstat.subscribers {
+		if s == sub {
+			bstat.subscribers = append(
+				bstat.subscribers[:i],
+				bstat.subscribers[i+1:]...,
+			)
+			close(sub.c)
+			return
+		}
 	}
+}
 
-	entry := LogEntry{
-		Time:  time.Now(),
-		Event: event,
+// PushEvent pushes a new log entry into the battle statistics
+// and publishes it to all subscribers
+func (bstat *Statistics) PushEvent(
+	event Event,
+	soldiers ...SoldierSnapshot,
+) error {
+	return bstat.pushEntry(event, soldiers)
+}
+
+// pushEntry pushes a new log entry with the given snapshots
+// of the involved soldiers
+func (bstat *Statistics) pushEntry(
+	event Event,
+	snapshots []SoldierSnapshot,
+) error {
+	bstat.lock.Lock()
+	if bstat.ended {
+		bstat.lock.Unlock()
+		return errors.New("the battle is already over")
 	}
 
 	// Push log entry
-	bstat.log = append(bstat.log, entry)
+	bstat.log = append(bstat.log, LogEntry{
+		Time:     bstat.clock.Now(),
+		Event:    event,
+		Soldiers: snapshots,
+	})
+	bstat.lock.Unlock()
 
-	// Push stream (non-blocking)
-	select {
-	case bstat.logStream <- entry:
-	default:
+	// The log isn't locked while publishing to not block readers
+	// of the log while the subscribers block the battle
+	bstat.publishLock.Lock()
+	defer bstat.publishLock.Unlock()
+	bstat.publishPending()
+	return nil
+}
+
+// publishPending publishes the pushed entries not yet published
+// in the order of the log.
+// Expects the publish lock to be locked
+func (bstat *Statistics) publishPending() {
+	for {
+		bstat.lock.Lock()
+		if bstat.published >= len(bstat.log) {
+			bstat.lock.Unlock()
+			return
+		}
+		entry := bstat.log[bstat.published]
+		bstat.published++
+		bstat.lock.Unlock()
+
+		for _, sub := range bstat.subscribers {
+			sub.publish(entry, bstat.canceled)
+		}
 	}
+}
 
-	return nil
+// newSoldierSnapshot creates the snapshot of a soldier's status
+func newSoldierSnapshot(id SoldierID, status SoldierStatus) SoldierSnapshot {
+	return SoldierSnapshot{
+		ID:      id,
+		Health:  status.Health,
+		Morale:  status.Morale,
+		Stamina: status.Stamina,
+		Routed:  status.Routed,
+	}
+}
+
+// snapshotSoldiers returns the snapshots of the given soldiers
+// skipping nil soldiers and duplicates.
+// Locks the soldiers one at a time, the caller mustn't hold any soldier lock
+func snapshotSoldiers(soldiers ...Soldier) []SoldierSnapshot {
+	snapshots := make([]SoldierSnapshot, 0, len(soldiers))
+SOLDIERS:
+	for _, soldier := range soldiers {
+		if soldier == nil {
+			continue
+		}
+		id := soldier.ID()
+		for _, snapshot := range snapshots {
+			if snapshot.ID == id {
+				continue SOLDIERS
+			}
+		}
+		snapshots = append(snapshots, newSoldierSnapshot(id, soldier.Status()))
+	}
+	return snapshots
 }
 
 // StopRecording stops recording the battle
+// and closes all subscriptions
 func (bstat *Statistics) StopRecording() {
 	bstat.lock.Lock()
 	bstat.ended = true
 	bstat.lock.Unlock()
+
+	bstat.publishLock.Lock()
+	defer bstat.publishLock.Unlock()
+
+	// Entries pushed right before might not be published yet
+	bstat.publishPending()
+	for _, sub := range bstat.subscribers {
+		close(sub.c)
+	}
+	bstat.subscribers = nil
+	bstat.closed = true
 }
 
 // StatisticsReader intefaces battle statistics in read-only mode
 type StatisticsReader interface {
 	// WinnerFaction returns the name of the winner faction
+	// or an empty string if the battle wasn't won by a faction
 	WinnerFaction() string
 
 	// Log returns a copy of the battle log
 	Log() []LogEntry
 
-	// LogStream returns the log streaming channel
+	// LogStream returns the log streaming channel which is closed
+	// when the battle ends. The stream blocks the battle while its buffer
+	// is full, use Subscribe for other overflow policies
 	LogStream() <-chan LogEntry
+
+	// Subscribe subscribes to the log entries pushed from now on.
+	// The subscription channel is buffered by the given size
+	// and is closed when the battle ends
+	Subscribe(bufferSize uint, policy OverflowPolicy) *Subscription
+
+	// SubscribeWithLog returns a copy of the battle log and subscribes
+	// to the log entries following it (see Subscribe)
+	SubscribeWithLog(
+		bufferSize uint,
+		policy OverflowPolicy,
+	) ([]LogEntry, *Subscription)
+
+	// Unsubscribe cancels the given subscription and closes its channel
+	Unsubscribe(sub *Subscription)
 }
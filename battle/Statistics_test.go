@@ -0,0 +1,108 @@
+package battle
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestStalledSubscriber makes sure a blocking subscriber that never reads
+// neither blocks the readers of the log nor a canceled battle
+func TestStalledSubscriber(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "concurrent",
+			config: Config{BaseActionDelay: time.Millisecond},
+		},
+		{
+			name:   "sequential",
+			config: Config{BaseActionDelay: time.Millisecond, Seed: 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			btl := newTestBattle(t, tc.config, testFactions(5, 100)...)
+			stats := btl.Statistics()
+			sub := stats.Subscribe(0, OverflowBlock)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := runAsync(ctx, btl)
+
+			// The first event blocks the battle
+			waitFor(t, "the first event", func() bool {
+				return len(stats.Log()) > 0
+			})
+
+			cancel()
+			result := awaitResult(t, done)
+			if result.Outcome != OutcomeCanceled {
+				t.Errorf("unexpected outcome: %s", result.Outcome)
+			}
+			if sub.Dropped() < 1 {
+				t.Error("no entries dropped")
+			}
+			if _, open := <-sub.C(); open {
+				t.Error("the subscription wasn't closed")
+			}
+		})
+	}
+}
+
+// TestSubscribeWithLog makes sure subscribing in the middle of a battle
+// neither misses any entry nor receives an entry twice
+func TestSubscribeWithLog(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config Config
+		after  int
+	}{
+		{
+			name:   "concurrent",
+			config: Config{BaseActionDelay: time.Millisecond},
+			after:  10,
+		},
+		{
+			name:   "sequential",
+			config: Config{BaseActionDelay: time.Millisecond, Seed: 1},
+			after:  10,
+		},
+		{
+			name:   "before the battle",
+			config: Config{BaseActionDelay: time.Millisecond, Seed: 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			btl := newTestBattle(t, tc.config, testFactions(5, 50)...)
+			stats := btl.Statistics()
+
+			done := runAsync(context.Background(), btl)
+			waitFor(t, "the events", func() bool {
+				return len(stats.Log()) >= tc.after
+			})
+
+			log, sub := stats.SubscribeWithLog(0, OverflowBlock)
+			for entry := range sub.C() {
+				log = append(log, entry)
+			}
+			awaitResult(t, done)
+
+			expected := stats.Log()
+			if len(log) != len(expected) {
+				t.Fatalf(
+					"received %d entries, expected %d",
+					len(log),
+					len(expected),
+				)
+			}
+			for i := range expected {
+				if !reflect.DeepEqual(log[i], expected[i]) {
+					t.Fatalf("unexpected entry %d: %#v", i, log[i])
+				}
+			}
+		})
+	}
+}
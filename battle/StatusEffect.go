@@ -0,0 +1,368 @@
+package battle
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EffectKind represents the kind of a status effect
+type EffectKind int
+
+const (
+	// EffectBleeding makes the soldier lose health-points every tick
+	EffectBleeding EffectKind = iota
+
+	// EffectStun makes the soldier skip its actions until it expires
+	EffectStun
+
+	// EffectFear makes the soldier lose morale every tick
+	EffectFear
+)
+
+// String stringifies the effect kind
+func (k EffectKind) String() string {
+	switch k {
+	case EffectBleeding:
+		return "bleeding"
+	case EffectStun:
+		return "stun"
+	case EffectFear:
+		return "fear"
+	}
+	return "unknown"
+}
+
+// StatusEffect represents a status effect inflicted by the hits
+// of a weapon (see Weapon.Effects)
+type StatusEffect struct {
+	Kind EffectKind
+
+	// Chance defines the chance of a hit to inflict the effect
+	Chance float64
+
+	// Interval and Ticks define how often and how many times
+	// the effect ticks before it expires. Effects tick independently
+	// of the actions of the affected soldier
+	Interval time.Duration
+	Ticks    uint
+
+	// Magnitude defines the damage per tick of bleeding
+	// and the morale percentage drained per tick of fear.
+	// It's ignored by stuns
+	Magnitude float64
+}
+
+// Verify returns an error if the status effect is invalid
+func (e *StatusEffect) Verify() error {
+	if e.Kind < EffectBleeding || e.Kind > EffectFear {
+		return errors.Errorf("invalid effect kind: %d", e.Kind)
+	}
+	if e.Chance < 0 || e.Chance > 1 {
+		return errors.Errorf("invalid %s chance: %.2f", e.Kind, e.Chance)
+	}
+	if e.Interval <= 0 {
+		return errors.Errorf("invalid %s interval: %s", e.Kind, e.Interval)
+	}
+	if e.Ticks < 1 {
+		return errors.Errorf("invalid number of %s ticks: %d", e.Kind, e.Ticks)
+	}
+	if e.Magnitude < 0 || e.Kind == EffectFear && e.Magnitude > 1 {
+		return errors.Errorf(
+			"invalid %s magnitude: %.2f",
+			e.Kind,
+			e.Magnitude,
+		)
+	}
+	return nil
+}
+
+// ActiveEffect represents a status effect affecting a soldier
+type ActiveEffect struct {
+	Kind      EffectKind
+	Source    SoldierID
+	Magnitude float64
+
+	// TicksLeft represents the number of ticks before the effect expires
+	TicksLeft uint
+
+	id       uint64
+	interval time.Duration
+	ticker   *DynamicTicker
+	source   Soldier
+}
+
+// repeater runs functions repeatedly while the battle is running
+type repeater interface {
+	// repeat runs fn every interval until it returns true
+	// or the battle ends and returns the ticker driving it
+	repeat(interval time.Duration, fn func() (done bool)) *DynamicTicker
+}
+
+// repeat implements the repeater interface.
+// Does nothing and returns nil unless the battle is running
+func (b *Battle) repeat(
+	interval time.Duration,
+	fn func() (done bool),
+) *DynamicTicker {
+	b.lock.Lock()
+	ctx, wg := b.runCtx, b.wg
+	b.lock.Unlock()
+	if ctx == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	tk := b.newTicker()
+	b.repeatAfter(ctx, wg, tk, interval, interval, fn)
+	return tk
+}
+
+// repeatAfter runs fn the first time after the given delay
+// and then every interval using the given ticker until fn returns true
+// or the battle ends
+func (b *Battle) repeatAfter(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	tk *DynamicTicker,
+	first time.Duration,
+	interval time.Duration,
+	fn func() (done bool),
+) {
+	tk.resetAfter(first, interval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer b.releaseTicker(tk)
+		for {
+			select {
+			case <-ctx.Done():
+				tk.Reset(0)
+				return
+			case <-tk.C():
+				done := false
+				b.act(func() { done = fn() })
+				if done {
+					// Stop the ticker before acknowledging the last tick
+					tk.Reset(0)
+					tk.Ack()
+					return
+				}
+				tk.Ack()
+			}
+		}
+	}()
+}
+
+// isStunned returns true if the soldier is affected by a stun
+//
+// This method is thread-safe
+func (s *soldier) isStunned() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, effect := range s.status.Effects {
+		if effect.Kind == EffectStun {
+			return true
+		}
+	}
+	return false
+}
+
+// inflictEffects rolls the status effects of the soldier's weapon
+// after a hit and inflicts them on the opponent
+func (s *soldier) inflictEffects(opponent Soldier) {
+	weapon := s.equipment.Weapon
+	if weapon == nil {
+		return
+	}
+	for _, effect := range weapon.Effects {
+		if effect.Chance <= 0 || !s.rng.luck(effect.Chance) {
+			continue
+		}
+		switch err := opponent.Afflict(s, effect); err {
+		case nil:
+		case ErrDead, ErrFled:
+			// The opponent left the battlefield in the meantime
+			return
+		default:
+			panic(errors.Wrap(err, "unexpected affliction err"))
+		}
+	}
+}
+
+// Afflict implements the Soldier interface.
+// The effect only ticks while the battle is running
+func (s *soldier) Afflict(from Soldier, effect StatusEffect) error {
+	if from == nil {
+		return errors.New("no soldier to be afflicted by")
+	}
+	if err := effect.Verify(); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	if s.status.Health <= 0 {
+		s.lock.Unlock()
+		return ErrDead
+	}
+	if s.status.Fled {
+		s.lock.Unlock()
+		return ErrFled
+	}
+	s.effectSeq++
+	id := s.effectSeq
+	s.status.Effects = append(s.status.Effects, ActiveEffect{
+		Kind:      effect.Kind,
+		Source:    from.ID(),
+		Magnitude: effect.Magnitude,
+		TicksLeft: effect.Ticks,
+		id:        id,
+		interval:  effect.Interval,
+		source:    from,
+	})
+	s.lock.Unlock()
+
+	if err := s.battleLog.PushEvent(EventEffectApplied{
+		Soldier: s,
+		Source:  from,
+		Effect:  effect,
+	}, snapshotSoldiers(s, from)...); err != nil {
+		return err
+	}
+
+	if s.repeater != nil {
+		tk := s.repeater.repeat(effect.Interval, func() bool {
+			return s.tickEffect(id)
+		})
+
+		// Keep the ticker to be able to snapshot the effect
+		s.lock.Lock()
+		if index := s.effectIndex(id); index >= 0 {
+			s.status.Effects[index].ticker = tk
+		}
+		s.lock.Unlock()
+	}
+	return nil
+}
+
+// effectIndex returns the index of the active effect of the given id
+// or -1 if the effect isn't active.
+// Expects the soldier lock to be locked
+func (s *soldier) effectIndex(id uint64) int {
+	for i, effect := range s.status.Effects {
+		if effect.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// tickEffect applies a tick of the active effect of the given id
+// and returns true once the effect expired
+func (s *soldier) tickEffect(id uint64) (expired bool) {
+	s.lock.Lock()
+	index := s.effectIndex(id)
+	if index < 0 {
+		s.lock.Unlock()
+		return true
+	}
+	if s.status.Health <= 0 || s.status.Fled {
+		// Effects end with the soldier leaving the battlefield
+		s.removeEffect(index)
+		s.lock.Unlock()
+		return true
+	}
+
+	effect := &s.status.Effects[index]
+	effect.TicksLeft--
+	tick := EventEffectTick{
+		Soldier:   s,
+		Effect:    effect.Kind,
+		Magnitude: effect.Magnitude,
+		TicksLeft: effect.TicksLeft,
+	}
+	switch effect.Kind {
+	case EffectBleeding:
+		damage := math.Min(effect.Magnitude, s.status.Health)
+		s.status.Health -= damage
+		s.stats.DamageTaken += damage
+		tick.Magnitude = damage
+		if s.status.Health <= 0 {
+			tick.Killed = true
+		}
+	case EffectFear:
+		s.addMorale(-effect.Magnitude)
+	}
+	kind, source := effect.Kind, effect.source
+	expired = effect.TicksLeft < 1 || tick.Killed
+	if expired {
+		s.removeEffect(index)
+	}
+	if tick.Killed {
+		s.status.Effects = nil
+		s.endLife(true)
+	}
+	snapshot := s.logSnapshot()
+	s.lock.Unlock()
+
+	if err := s.battleLog.PushEvent(tick, snapshot); err != nil {
+		panic(err)
+	}
+	switch {
+	case tick.Killed:
+		if err := s.battleLog.PushEvent(EventEffectKill{
+			Source:      source,
+			Killed:      s,
+			Effect:      kind,
+			DamageDealt: tick.Magnitude,
+		}, snapshotSoldiers(source, s)...); err != nil {
+			panic(err)
+		}
+		creditKill(source, s.battleLog)
+	case expired:
+		if err := s.battleLog.PushEvent(EventEffectExpired{
+			Soldier: s,
+			Effect:  kind,
+		}, snapshot); err != nil {
+			panic(err)
+		}
+	}
+	return expired
+}
+
+// creditKill credits the source of a deadly status effect with the kill.
+// Only a source still on the battlefield gains the experience
+func creditKill(source Soldier, log LogWriter) {
+	s, ok := source.(*soldier)
+	if !ok {
+		return
+	}
+	s.lock.Lock()
+	s.stats.Kills++
+	onBattlefield := s.status.Health > 0 && !s.status.Fled
+	s.lock.Unlock()
+	if !onBattlefield {
+		return
+	}
+
+	if level, leveledUp := s.gainExperience(0, true); leveledUp {
+		if err := log.PushEvent(EventLevelUp{
+			Soldier:    s,
+			Level:      level,
+			Experience: s.Status().Experience,
+		}, snapshotSoldiers(s)...); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// removeEffect removes the active effect at the given index.
+// Expects the soldier lock to be locked
+func (s *soldier) removeEffect(index int) {
+	s.status.Effects = append(
+		s.status.Effects[:index:index],
+		s.status.Effects[index+1:]...,
+	)
+}
@@ -0,0 +1,84 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestEffectKill makes sure soldiers killed by status effects
+// are credited to the source of the effect
+func TestEffectKill(t *testing.T) {
+	bleeding := Loadout{Weight: 1, Equipment: Equipment{Weapon: &Weapon{
+		Effects: []StatusEffect{{
+			Kind:      EffectBleeding,
+			Chance:    1,
+			Interval:  time.Millisecond,
+			Ticks:     1,
+			Magnitude: 1000,
+		}},
+	}}}
+	for _, tc := range []struct {
+		name      string
+		commander bool
+	}{
+		{name: "soldiers"},
+		{name: "commander", commander: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Only the bleeding can kill the tough soldiers
+			factions := testFactions(5, 1000)
+			factions[0].Loadouts = []Loadout{bleeding}
+			if tc.commander {
+				commander := testAttributes(1000)
+				factions[1].Command = Command{
+					Commander:          &commander,
+					DeathMoralePenalty: .1,
+				}
+			}
+			btl := newTestBattle(t, Config{
+				BaseActionDelay: time.Millisecond,
+				Seed:            1,
+				Veterancy:       Veterancy{KillExperience: 1},
+			}, factions...)
+			awaitResult(t, runAsync(context.Background(), btl))
+
+			effectKills, commanderKills := 0, 0
+			for _, entry := range btl.Statistics().Log() {
+				switch ev := entry.Event.(type) {
+				case EventEffectKill:
+					effectKills++
+					if ev.Source.ID().Faction != "A" {
+						t.Errorf("unexpected source: %s", ev.Source.ID())
+					}
+				case EventCommanderKilled:
+					commanderKills++
+				}
+			}
+			if effectKills < 1 {
+				t.Fatal("no effect kills logged")
+			}
+
+			kills, experience := uint(0), 0.0
+			for _, s := range btl.Soldiers("A") {
+				kills += s.Stats().Kills
+				experience += s.Status().Experience
+			}
+			if kills != uint(effectKills) {
+				t.Errorf("%d kills credited, expected %d", kills, effectKills)
+			}
+			if experience != float64(effectKills) {
+				t.Errorf("unexpected experience: %.1f", experience)
+			}
+
+			expectedCommanderKills := 0
+			if commander := btl.Commander("B"); commander != nil &&
+				!commander.IsAlive() {
+				expectedCommanderKills = 1
+			}
+			if commanderKills != expectedCommanderKills {
+				t.Errorf("%d commander kills logged", commanderKills)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package battle
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy defines how a subscription treats new log entries
+// when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the battle until the subscriber catches up.
+	// No log entries are dropped unless the battle is canceled
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest drops the oldest buffered log entry
+	// to make room for the new one
+	OverflowDropOldest
+
+	// OverflowDropNewest drops the new log entry
+	OverflowDropNewest
+)
+
+// Subscription represents a subscription to the battle log
+type Subscription struct {
+	c       chan LogEntry
+	policy  OverflowPolicy
+	dropped uint64
+	done    chan struct{}
+	cancel  *sync.Once
+}
+
+// newSubscription creates a new subscription instance
+func newSubscription(bufferSize uint, policy OverflowPolicy) *Subscription {
+	return &Subscription{
+		c:      make(chan LogEntry, bufferSize),
+		policy: policy,
+		done:   make(chan struct{}),
+		cancel: &sync.Once{},
+	}
+}
+
+// C returns the channel of the subscription.
+// The channel is closed when either the battle ends
+// or the subscription is canceled
+func (sub *Subscription) C() <-chan LogEntry {
+	return sub.c
+}
+
+// Dropped returns the number of log entries dropped due to buffer overflows
+func (sub *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&sub.dropped)
+}
+
+// publish publishes a log entry according to the overflow policy.
+// Blocking subscriptions drop the entry once canceled is closed
+func (sub *Subscription) publish(entry LogEntry, canceled <-chan struct{}) {
+	switch sub.policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case sub.c <- entry:
+				return
+			default:
+			}
+			// Drop the oldest entry (non-blocking,
+			// the subscriber might just have read it)
+			select {
+			case <-sub.c:
+				atomic.AddUint64(&sub.dropped, 1)
+			default:
+			}
+		}
+	case OverflowDropNewest:
+		select {
+		case sub.c <- entry:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+	default:
+		select {
+		case sub.c <- entry:
+		case <-sub.done:
+			// Unsubscribed in the meantime
+		case <-canceled:
+			// Don't block the canceled battle any longer
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+	}
+}
+
+// unblock releases a publisher blocked on the subscription
+func (sub *Subscription) unblock() {
+	sub.cancel.Do(func() { close(sub.done) })
+}
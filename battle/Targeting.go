@@ -0,0 +1,185 @@
+package battle
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// TargetingStrategy represents a strategy of selecting opponents
+type TargetingStrategy interface {
+	// SelectTarget selects the opponent to attack from the given
+	// non-empty list of candidates. rnd must be used for random decisions
+	// to keep seeded battles reproducible
+	SelectTarget(
+		attacker Soldier,
+		candidates []Soldier,
+		rnd *rand.Rand,
+	) Soldier
+}
+
+// targetingStrategies maps the names of the built-in targeting strategies
+// to their constructors
+var targetingStrategies = map[string]func() TargetingStrategy{
+	"random":       func() TargetingStrategy { return RandomTargeting{} },
+	"weakest":      func() TargetingStrategy { return WeakestTargeting{} },
+	"strongest":    func() TargetingStrategy { return StrongestTargeting{} },
+	"lowestMorale": func() TargetingStrategy { return LowestMoraleTargeting{} },
+	"focusFire":    func() TargetingStrategy { return NewFocusFireTargeting() },
+	"retaliation":  func() TargetingStrategy { return RetaliationTargeting{} },
+}
+
+// NewTargetingStrategy creates a new instance of the built-in targeting
+// strategy of the given name (see TargetingStrategyNames)
+func NewTargetingStrategy(name string) (TargetingStrategy, error) {
+	constructor, known := targetingStrategies[name]
+	if !known {
+		return nil, errors.Errorf("unknown targeting strategy: '%s'", name)
+	}
+	return constructor(), nil
+}
+
+// TargetingStrategyNames returns the sorted names
+// of the built-in targeting strategies
+func TargetingStrategyNames() []string {
+	names := make([]string, 0, len(targetingStrategies))
+	for name := range targetingStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectBy returns the first candidate with the best score
+func selectBy(candidates []Soldier, better func(a, b float64) bool) Soldier {
+	best := candidates[0]
+	bestScore := 0.0
+	for i, candidate := range candidates {
+		status := candidate.Status()
+		if i == 0 || better(status.Health, bestScore) {
+			best = candidate
+			bestScore = status.Health
+		}
+	}
+	return best
+}
+
+// RandomTargeting selects a uniformly random opponent
+type RandomTargeting struct{}
+
+// SelectTarget implements the TargetingStrategy interface
+func (RandomTargeting) SelectTarget(
+	attacker Soldier,
+	candidates []Soldier,
+	rnd *rand.Rand,
+) Soldier {
+	return candidates[rnd.Intn(len(candidates))]
+}
+
+// WeakestTargeting selects the opponent with the lowest health
+type WeakestTargeting struct{}
+
+// SelectTarget implements the TargetingStrategy interface
+func (WeakestTargeting) SelectTarget(
+	attacker Soldier,
+	candidates []Soldier,
+	rnd *rand.Rand,
+) Soldier {
+	return selectBy(candidates, func(a, b float64) bool { return a < b })
+}
+
+// StrongestTargeting selects the opponent with the highest health
+type StrongestTargeting struct{}
+
+// SelectTarget implements the TargetingStrategy interface
+func (StrongestTargeting) SelectTarget(
+	attacker Soldier,
+	candidates []Soldier,
+	rnd *rand.Rand,
+) Soldier {
+	return selectBy(candidates, func(a, b float64) bool { return a > b })
+}
+
+// LowestMoraleTargeting selects the opponent with the lowest morale
+type LowestMoraleTargeting struct{}
+
+// SelectTarget implements the TargetingStrategy interface
+func (LowestMoraleTargeting) SelectTarget(
+	attacker Soldier,
+	candidates []Soldier,
+	rnd *rand.Rand,
+) Soldier {
+	best := candidates[0]
+	bestMorale := best.Status().Morale
+	for _, candidate := range candidates[1:] {
+		if morale := candidate.Status().Morale; morale < bestMorale {
+			best = candidate
+			bestMorale = morale
+		}
+	}
+	return best
+}
+
+// FocusFireTargeting makes all soldiers of a faction attack the faction's
+// current priority target until it's dead. The weakest opponent is selected
+// as the next priority target
+type FocusFireTargeting struct {
+	lock    *sync.Mutex
+	targets map[string]SoldierID
+}
+
+// NewFocusFireTargeting creates a new focus-fire targeting strategy.
+// The priority targets are tracked per faction
+func NewFocusFireTargeting() *FocusFireTargeting {
+	return &FocusFireTargeting{
+		lock:    &sync.Mutex{},
+		targets: make(map[string]SoldierID),
+	}
+}
+
+// SelectTarget implements the TargetingStrategy interface
+func (ff *FocusFireTargeting) SelectTarget(
+	attacker Soldier,
+	candidates []Soldier,
+	rnd *rand.Rand,
+) Soldier {
+	faction := attacker.ID().Faction
+
+	ff.lock.Lock()
+	defer ff.lock.Unlock()
+
+	if target, hasTarget := ff.targets[faction]; hasTarget {
+		for _, candidate := range candidates {
+			if candidate.ID() == target {
+				return candidate
+			}
+		}
+	}
+
+	// The priority target is dead, select a new one
+	target := WeakestTargeting{}.SelectTarget(attacker, candidates, rnd)
+	ff.targets[faction] = target.ID()
+	return target
+}
+
+// RetaliationTargeting selects the last attacker of the soldier
+// if it's still alive and a random opponent otherwise
+type RetaliationTargeting struct{}
+
+// SelectTarget implements the TargetingStrategy interface
+func (RetaliationTargeting) SelectTarget(
+	attacker Soldier,
+	candidates []Soldier,
+	rnd *rand.Rand,
+) Soldier {
+	if lastAttacker := attacker.Status().LastAttacker; lastAttacker != nil {
+		for _, candidate := range candidates {
+			if candidate.ID() == *lastAttacker {
+				return candidate
+			}
+		}
+	}
+	return RandomTargeting{}.SelectTarget(attacker, candidates, rnd)
+}
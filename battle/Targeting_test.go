@@ -0,0 +1,192 @@
+package battle
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// newTargetingBattle creates a battle of a single attacker of faction A
+// and the candidates of faction B differing in health
+func newTargetingBattle(t *testing.T, config Config) *Battle {
+	t.Helper()
+	attacker := testAttributes(50)
+	attacker.DodgeChanceMin = 0
+	attacker.DodgeChanceMax = 0
+	attacker.MovementSpeed = 1
+	candidates := testAttributes(10)
+	candidates.HealthMax = 100
+	candidates.MovementSpeed = 1
+	config.Seed = 1
+	return newTestBattle(
+		t,
+		config,
+		Faction{Name: "A", ArmySize: 1, SoldierAttributes: attacker},
+		Faction{Name: "B", ArmySize: 5, SoldierAttributes: candidates},
+	)
+}
+
+// byHealth returns the candidate with either the lowest
+// or the highest health
+func byHealth(candidates []Soldier, lowest bool) Soldier {
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		health := candidate.Status().Health
+		if lowest && health < best.Status().Health ||
+			!lowest && health > best.Status().Health {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// TestSelectTarget makes sure the targeting strategies
+// select the expected opponents
+func TestSelectTarget(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy TargetingStrategy
+		prepare  func(attacker Soldier, candidates []Soldier)
+		expected func(candidates []Soldier) Soldier
+	}{
+		{
+			name:     "random",
+			strategy: RandomTargeting{},
+			expected: func(candidates []Soldier) Soldier {
+				rnd := rand.New(rand.NewSource(1))
+				return candidates[rnd.Intn(len(candidates))]
+			},
+		},
+		{
+			name:     "weakest",
+			strategy: WeakestTargeting{},
+			expected: func(candidates []Soldier) Soldier {
+				return byHealth(candidates, true)
+			},
+		},
+		{
+			name:     "strongest",
+			strategy: StrongestTargeting{},
+			expected: func(candidates []Soldier) Soldier {
+				return byHealth(candidates, false)
+			},
+		},
+		{
+			name:     "lowest morale",
+			strategy: LowestMoraleTargeting{},
+			prepare: func(attacker Soldier, candidates []Soldier) {
+				candidates[1].AddMorale(-.2)
+				candidates[3].AddMorale(-.5)
+			},
+			expected: func(candidates []Soldier) Soldier {
+				return candidates[3]
+			},
+		},
+		{
+			name:     "retaliation",
+			strategy: RetaliationTargeting{},
+			prepare: func(attacker Soldier, candidates []Soldier) {
+				if _, _, err := attacker.TakeDamage(candidates[2], 1); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expected: func(candidates []Soldier) Soldier {
+				return candidates[2]
+			},
+		},
+		{
+			name:     "retaliation without an attacker",
+			strategy: RetaliationTargeting{},
+			expected: func(candidates []Soldier) Soldier {
+				rnd := rand.New(rand.NewSource(1))
+				return candidates[rnd.Intn(len(candidates))]
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			btl := newTargetingBattle(t, Config{})
+			attacker := btl.Soldiers("A")[0]
+			candidates := btl.Soldiers("B")
+			if tc.prepare != nil {
+				tc.prepare(attacker, candidates)
+			}
+			target := tc.strategy.SelectTarget(
+				attacker,
+				candidates,
+				rand.New(rand.NewSource(1)),
+			)
+			if expected := tc.expected(candidates); target != expected {
+				t.Errorf("selected %s, expected %s", target.ID(), expected.ID())
+			}
+		})
+	}
+}
+
+// TestFocusFireRetargeting makes sure focus-fire targeting keeps attacking
+// the priority target until it's dead and selects the weakest opponent next
+func TestFocusFireRetargeting(t *testing.T) {
+	btl := newTargetingBattle(t, Config{})
+	attacker := btl.Soldiers("A")[0]
+	candidates := btl.Soldiers("B")
+	strategy := NewFocusFireTargeting()
+	rnd := rand.New(rand.NewSource(1))
+
+	target := strategy.SelectTarget(attacker, candidates, rnd)
+	if expected := byHealth(candidates, true); target != expected {
+		t.Fatalf("selected %s, expected %s", target.ID(), expected.ID())
+	}
+
+	// Another opponent becoming weaker doesn't change the priority target
+	strongest := byHealth(candidates, false)
+	strongest.(*soldier).status.Health = 1
+	if next := strategy.SelectTarget(attacker, candidates, rnd); next != target {
+		t.Fatalf("switched to %s while %s is alive", next.ID(), target.ID())
+	}
+
+	// The dead priority target is no longer a candidate
+	var left []Soldier
+	for _, candidate := range candidates {
+		if candidate != target {
+			left = append(left, candidate)
+		}
+	}
+	if next := strategy.SelectTarget(attacker, left, rnd); next != strongest {
+		t.Errorf(
+			"selected %s after the kill, expected %s",
+			next.ID(),
+			strongest.ID(),
+		)
+	}
+}
+
+// TestNearestTargeting makes sure soldiers without a targeting strategy
+// attack the nearest opponent on a spatial battlefield
+func TestNearestTargeting(t *testing.T) {
+	btl := newTargetingBattle(t, Config{Field: &Field{Width: 50, Height: 50}})
+	field := btl.Battlefield().(SpatialBattlefield)
+	attacker := btl.Soldiers("A")[0]
+	from, err := field.Position(attacker.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var nearest Soldier
+	distance := 0.0
+	for _, candidate := range btl.Soldiers("B") {
+		to, err := field.Position(candidate.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := from.Distance(to); nearest == nil || d < distance {
+			nearest = candidate
+			distance = d
+		}
+	}
+
+	target, err := field.FindOpponent(attacker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != nearest {
+		t.Errorf("selected %s, expected %s", target.ID(), nearest.ID())
+	}
+}
@@ -0,0 +1,57 @@
+package battle
+
+import "github.com/pkg/errors"
+
+// Unit represents a group of soldiers of the same unit type
+// sharing the same attributes within a faction
+type Unit struct {
+	// Type is the name of the unit type such as "infantry" or "archers"
+	Type              string
+	Count             uint
+	SoldierAttributes SoldierAttributes
+
+	// Loadouts defines the equipment the soldiers of the unit carry.
+	// Each soldier is equipped with one of the loadouts
+	// selected randomly by their weights
+	Loadouts []Loadout
+
+	// Veterans defines the soldiers of the unit joining the battle
+	// with experience. The remaining soldiers are recruits
+	Veterans []Veteran
+}
+
+// DamageMatrix maps the attacking unit types to the damage multipliers
+// applied against the defending unit types, such as:
+//
+//	DamageMatrix{
+//		"cavalry": {"archers": 1.5, "pikemen": 0.5},
+//	}
+//
+// Combinations not defined in the matrix deal regular damage
+type DamageMatrix map[string]map[string]float64
+
+// Multiplier returns the damage multiplier of the given attacking unit type
+// against the given defending unit type
+func (m DamageMatrix) Multiplier(attacker, defender string) float64 {
+	if multiplier, defined := m[attacker][defender]; defined {
+		return multiplier
+	}
+	return 1
+}
+
+// Verify returns an error if any of the multipliers is negative
+func (m DamageMatrix) Verify() error {
+	for attacker, multipliers := range m {
+		for defender, multiplier := range multipliers {
+			if multiplier < 0 {
+				return errors.Errorf(
+					"invalid damage multiplier of '%s' against '%s': %.2f",
+					attacker,
+					defender,
+					multiplier,
+				)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package battle
+
+import "testing"
+
+// TestDamageModifiers makes sure the damage multipliers
+// between the unit types are applied to the dealt damage
+func TestDamageModifiers(t *testing.T) {
+	attrs := SoldierAttributes{
+		HealthMin:         100,
+		HealthMax:         100,
+		AttackStrengthMin: 10,
+		AttackStrengthMax: 10,
+		HitChanceMin:      1,
+		HitChanceMax:      1,
+	}
+	btl := newTestBattle(
+		t,
+		Config{
+			Seed: 1,
+			DamageModifiers: DamageMatrix{
+				"cavalry": {"archers": 1.5, "pikemen": .5},
+			},
+		},
+		Faction{Name: "A", Units: []Unit{
+			{Type: "cavalry", Count: 1, SoldierAttributes: attrs},
+		}},
+		Faction{Name: "B", Units: []Unit{
+			{Type: "archers", Count: 1, SoldierAttributes: attrs},
+			{Type: "pikemen", Count: 1, SoldierAttributes: attrs},
+			{Type: "infantry", Count: 1, SoldierAttributes: attrs},
+		}},
+	)
+	attacker := btl.Soldiers("A")[0]
+	defenders := make(map[string]Soldier)
+	for _, s := range btl.Soldiers("B") {
+		defenders[s.ID().Unit] = s
+	}
+
+	for _, tc := range []struct {
+		name     string
+		unit     string
+		expected float64
+	}{
+		{name: "bonus", unit: "archers", expected: 15},
+		{name: "penalty", unit: "pikemen", expected: 5},
+		{name: "undefined", unit: "infantry", expected: 10},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			damage, _, err := attacker.Attack(defenders[tc.unit])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if damage != tc.expected {
+				t.Errorf("dealt %.2f damage, expected %.2f", damage, tc.expected)
+			}
+			if health := defenders[tc.unit].Status().Health; health !=
+				100-tc.expected {
+				t.Errorf("%.2f health left, expected %.2f", health, 100-tc.expected)
+			}
+		})
+	}
+}
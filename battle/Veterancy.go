@@ -0,0 +1,210 @@
+package battle
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Veterancy defines how soldiers gain experience
+// and how their level affects the way they fight
+type Veterancy struct {
+	// KillExperience and DamageExperience define the experience gained
+	// per kill and per point of damage dealt
+	KillExperience   float64
+	DamageExperience float64
+
+	// LevelExperience defines the experience required per level.
+	// Soldiers never level up if zero
+	LevelExperience float64
+
+	// MaxLevel limits the level the soldiers can reach.
+	// Zero means no limit
+	MaxLevel uint
+
+	// HitChanceBonus and DodgeChanceBonus define the chances
+	// added per level
+	HitChanceBonus   float64
+	DodgeChanceBonus float64
+
+	// MoraleResilience defines the percentage per level
+	// the morale losses of the soldier are reduced by
+	MoraleResilience float64
+}
+
+// Verify returns an error if the veterancy is invalid
+func (v *Veterancy) Verify() error {
+	if v.KillExperience < 0 {
+		return errors.Errorf(
+			"invalid kill experience: %.1f",
+			v.KillExperience,
+		)
+	}
+	if v.DamageExperience < 0 {
+		return errors.Errorf(
+			"invalid damage experience: %.1f",
+			v.DamageExperience,
+		)
+	}
+	if v.LevelExperience < 0 {
+		return errors.Errorf(
+			"invalid level experience: %.1f",
+			v.LevelExperience,
+		)
+	}
+	if v.HitChanceBonus < 0 || v.HitChanceBonus > 1 {
+		return errors.Errorf(
+			"invalid hit chance bonus %%: %.2f",
+			v.HitChanceBonus,
+		)
+	}
+	if v.DodgeChanceBonus < 0 || v.DodgeChanceBonus > 1 {
+		return errors.Errorf(
+			"invalid dodge chance bonus %%: %.2f",
+			v.DodgeChanceBonus,
+		)
+	}
+	if v.MoraleResilience < 0 || v.MoraleResilience > 1 {
+		return errors.Errorf(
+			"invalid morale resilience %%: %.2f",
+			v.MoraleResilience,
+		)
+	}
+	return nil
+}
+
+// Level returns the level reached with the given experience
+func (v *Veterancy) Level(experience float64) uint {
+	if v.LevelExperience <= 0 {
+		return 0
+	}
+	level := uint(math.Floor(experience / v.LevelExperience))
+	if v.MaxLevel > 0 && level > v.MaxLevel {
+		return v.MaxLevel
+	}
+	return level
+}
+
+// Veteran represents a soldier joining the battle with experience,
+// such as a survivor of a previous battle of a campaign
+type Veteran struct {
+	Name       string
+	Experience float64
+
+	// MaxHealth, Health and Morale define the condition the veteran
+	// joins the battle in. A veteran with a zero MaxHealth joins
+	// unharmed with a rolled max health and full morale
+	MaxHealth float64
+	Health    float64
+	Morale    float64
+
+	// Equipment replaces the loadout rolled for the veteran if defined
+	Equipment *Equipment
+
+	// Stats defines the statistics the veteran carries over
+	Stats SoldierStatistics
+}
+
+// Verify returns an error if the veteran is invalid
+func (v *Veteran) Verify() error {
+	if v.Name == "" {
+		return errors.New("veteran without a name")
+	}
+	if v.Experience < 0 {
+		return errors.Errorf(
+			"invalid experience of veteran %s: %.1f",
+			v.Name,
+			v.Experience,
+		)
+	}
+	if v.MaxHealth < 0 {
+		return errors.Errorf(
+			"invalid max health of veteran %s: %.1f",
+			v.Name,
+			v.MaxHealth,
+		)
+	}
+	if v.MaxHealth > 0 {
+		if v.Health <= 0 || v.Health > v.MaxHealth {
+			return errors.Errorf(
+				"invalid health of veteran %s: %.1f",
+				v.Name,
+				v.Health,
+			)
+		}
+		if v.Morale < 0 || v.Morale > 1 {
+			return errors.Errorf(
+				"invalid morale of veteran %s: %.2f",
+				v.Name,
+				v.Morale,
+			)
+		}
+	}
+	if v.Equipment != nil {
+		if err := v.Equipment.Verify(); err != nil {
+			return errors.Wrapf(
+				err,
+				"invalid equipment of veteran %s",
+				v.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// verifyVeterans returns an error if the veterans don't fit into a unit
+// of the given size or if any of them is invalid
+func verifyVeterans(veterans []Veteran, count uint) error {
+	if uint(len(veterans)) > count {
+		return errors.Errorf(
+			"more veterans (%d) than soldiers (%d)",
+			len(veterans),
+			count,
+		)
+	}
+	names := make(map[string]struct{}, len(veterans))
+	for _, veteran := range veterans {
+		if err := veteran.Verify(); err != nil {
+			return err
+		}
+		if _, duplicate := names[veteran.Name]; duplicate {
+			return errors.Errorf("duplicate veteran name: '%s'", veteran.Name)
+		}
+		names[veteran.Name] = struct{}{}
+	}
+	return nil
+}
+
+// gainExperience grants the soldier experience for the damage dealt
+// and returns the new level if the soldier leveled up
+//
+// This method is thread-safe
+func (s *soldier) gainExperience(
+	damageDealt float64,
+	killed bool,
+) (level uint, leveledUp bool) {
+	veterancy := &s.battleConfig.Veterancy
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	experience := damageDealt * veterancy.DamageExperience
+	if killed {
+		experience += veterancy.KillExperience
+	}
+	s.status.Experience += experience
+
+	level = veterancy.Level(s.status.Experience)
+	if level <= s.status.Level {
+		return s.status.Level, false
+	}
+	s.status.Level = level
+	return level, true
+}
+
+// levelBonus returns the bonus granted per level multiplied
+// by the soldier's level.
+// Expects the soldier lock to be locked
+func (s *soldier) levelBonus(perLevel float64) float64 {
+	return float64(s.status.Level) * perLevel
+}
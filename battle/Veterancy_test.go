@@ -0,0 +1,51 @@
+package battle
+
+import "testing"
+
+// TestLevel computes the levels reached with experience
+func TestLevel(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		veterancy  Veterancy
+		experience float64
+		level      uint
+	}{
+		{
+			name:       "no levels",
+			veterancy:  Veterancy{MaxLevel: 5},
+			experience: 100,
+			level:      0,
+		},
+		{
+			name:       "below the first level",
+			veterancy:  Veterancy{LevelExperience: 10, MaxLevel: 5},
+			experience: 9,
+			level:      0,
+		},
+		{
+			name:       "within the limit",
+			veterancy:  Veterancy{LevelExperience: 10, MaxLevel: 5},
+			experience: 35,
+			level:      3,
+		},
+		{
+			name:       "beyond the limit",
+			veterancy:  Veterancy{LevelExperience: 10, MaxLevel: 5},
+			experience: 100,
+			level:      5,
+		},
+		{
+			name:       "no limit",
+			veterancy:  Veterancy{LevelExperience: 10},
+			experience: 100,
+			level:      10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			level := tc.veterancy.Level(tc.experience)
+			if level != tc.level {
+				t.Errorf("reached level %d, expected %d", level, tc.level)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package battle
+
+import (
+	"sync"
+	"time"
+)
+
+// VirtualClock implements the Clock interface using a virtual time.
+// A virtual clock never waits, creating a new timer fast-forwards the clock
+// to the timer's deadline instead and fires the timer immediately.
+//
+// A battle using a virtual clock executes the soldier actions sequentially,
+// the clock thus always fast-forwards to the next scheduled soldier action.
+// A virtual clock must not be shared by multiple battles
+type VirtualClock struct {
+	lock *sync.Mutex
+	now  time.Time
+}
+
+// NewVirtualClock creates a new virtual clock starting at the given time
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{
+		lock: &sync.Mutex{},
+		now:  start,
+	}
+}
+
+// Now implements the Clock interface
+func (clock *VirtualClock) Now() time.Time {
+	clock.lock.Lock()
+	now := clock.now
+	clock.lock.Unlock()
+	return now
+}
+
+// Advance moves the clock forward by the given duration
+func (clock *VirtualClock) Advance(duration time.Duration) time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	if duration > 0 {
+		clock.now = clock.now.Add(duration)
+	}
+	return clock.now
+}
+
+// NewTimer implements the Clock interface
+func (clock *VirtualClock) NewTimer(duration time.Duration) Timer {
+	c := make(chan time.Time, 1)
+	c <- clock.Advance(duration)
+	return virtualTimer{c}
+}
+
+// virtualTimer implements the Timer interface for virtual clocks
+type virtualTimer struct {
+	c chan time.Time
+}
+
+// C implements the Timer interface
+func (tm virtualTimer) C() <-chan time.Time {
+	return tm.c
+}
+
+// Stop implements the Timer interface
+func (tm virtualTimer) Stop() bool {
+	// Virtual timers fire immediately
+	return false
+}
@@ -0,0 +1,45 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestVirtualClock makes sure battles on a virtual clock fast-forward
+// the time between the actions and measure their duration in virtual time
+func TestVirtualClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewVirtualClock(start)
+	btl := newTestBattle(t, Config{
+		BaseActionDelay: time.Hour,
+		Seed:            1,
+		Clock:           clock,
+	}, testFactions(5, 50)...)
+
+	began := time.Now()
+	result := awaitResult(t, runAsync(context.Background(), btl))
+	took := time.Since(began)
+
+	if result.Outcome != OutcomeVictory {
+		t.Fatalf("unexpected outcome: %s", result.Outcome)
+	}
+	if result.Duration < time.Hour {
+		t.Fatalf("took %s, expected at least one action delay", result.Duration)
+	}
+	if took*1000 > result.Duration {
+		t.Errorf("took %s of real time for %s", took, result.Duration)
+	}
+	if now := clock.Now(); result.Duration != now.Sub(start) {
+		t.Errorf(
+			"took %s, expected the %s the clock advanced",
+			result.Duration,
+			now.Sub(start),
+		)
+	}
+
+	log := btl.Statistics().Log()
+	if last := log[len(log)-1].Time.Sub(start); last > result.Duration {
+		t.Errorf("last entry logged after %s of %s", last, result.Duration)
+	}
+}
@@ -0,0 +1,69 @@
+package battle
+
+// battleLog is the log writer of the soldiers applying the reactions
+// of the battle to the logged events
+type battleLog struct {
+	LogWriter
+	battle *Battle
+}
+
+// log returns the log writer of the battle
+func (b *Battle) log() battleLog {
+	return battleLog{LogWriter: b.stats, battle: b}
+}
+
+// PushEvent implements the LogWriter interface
+func (bl battleLog) PushEvent(
+	event Event,
+	soldiers ...SoldierSnapshot,
+) error {
+	if err := bl.LogWriter.PushEvent(event, soldiers...); err != nil {
+		return err
+	}
+
+	switch ev := event.(type) {
+	case EventHit:
+		bl.battle.provoke(ev.Attacker, ev.Attacked)
+	case EventMiss:
+		bl.battle.provoke(ev.Attacker, ev.Attacked)
+	case EventDodge:
+		bl.battle.provoke(ev.Attacker, ev.Defernder)
+	case EventBlock:
+		bl.battle.provoke(ev.Attacker, ev.Defender)
+	case EventKill:
+		bl.battle.provoke(ev.Attacker, ev.Killed)
+		if err := bl.battle.spreadMorale(
+			ev.Attacker,
+			ev.Killed,
+			bl,
+		); err != nil {
+			return err
+		}
+		if err := bl.battle.reinforceIfNeeded(
+			ev.Killed.ID().Faction,
+		); err != nil {
+			return err
+		}
+	case EventEffectKill:
+		bl.battle.provoke(ev.Source, ev.Killed)
+		if err := bl.battle.spreadMorale(
+			ev.Source,
+			ev.Killed,
+			bl,
+		); err != nil {
+			return err
+		}
+		if err := bl.battle.reinforceIfNeeded(
+			ev.Killed.ID().Faction,
+		); err != nil {
+			return err
+		}
+	case EventRout:
+		if err := bl.battle.reinforceIfNeeded(
+			ev.Soldier.ID().Faction,
+		); err != nil {
+			return err
+		}
+	}
+	return bl.battle.checkTriggers(event)
+}
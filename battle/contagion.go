@@ -0,0 +1,81 @@
+package battle
+
+// spreadMorale spreads the morale effects of the kill among the comrades
+// of the soldiers involved and reports the death of a commander
+func (b *Battle) spreadMorale(attacker, killed Soldier, log LogWriter) error {
+	for _, comrade := range b.comrades(killed) {
+		if s, ok := comrade.(*soldier); ok {
+			s.shareMorale(true)
+		}
+	}
+	for _, comrade := range b.comrades(attacker) {
+		if s, ok := comrade.(*soldier); ok {
+			s.shareMorale(false)
+		}
+	}
+	if killed.IsCommander() {
+		return b.commanderKilled(attacker, killed, log)
+	}
+	return nil
+}
+
+// commanderKilled shocks the faction of the killed commander
+func (b *Battle) commanderKilled(
+	attacker Soldier,
+	commander Soldier,
+	log LogWriter,
+) error {
+	faction := commander.ID().Faction
+	penalty := b.commands[faction].config.DeathMoralePenalty
+
+	b.lock.Lock()
+	soldiers := append([]Soldier(nil), b.alive[faction]...)
+	soldiers = append(soldiers, b.routed[faction]...)
+	b.lock.Unlock()
+
+	for _, comrade := range soldiers {
+		if s, ok := comrade.(*soldier); ok {
+			s.demoralize(penalty)
+		}
+	}
+
+	return log.PushEvent(EventCommanderKilled{
+		Attacker:      attacker,
+		Commander:     commander,
+		MoralePenalty: penalty,
+	}, snapshotSoldiers(attacker, commander)...)
+}
+
+// comrades returns a copy of the soldiers of the given soldier's faction
+// still on the battlefield within the morale contagion radius
+func (b *Battle) comrades(of Soldier) []Soldier {
+	id := of.ID()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	radius := b.config.MoraleContagionRadius
+	field, isSpatial := b.battlefield.(*fieldBattlefield)
+	origin := Position{}
+	if isSpatial {
+		origin = field.positions[id]
+	}
+
+	var comrades []Soldier
+	for _, soldiers := range [][]Soldier{
+		b.alive[id.Faction],
+		b.routed[id.Faction],
+	} {
+		for _, comrade := range soldiers {
+			if comrade.ID() == id {
+				continue
+			}
+			if isSpatial && radius > 0 &&
+				origin.Distance(field.positions[comrade.ID()]) > radius {
+				continue
+			}
+			comrades = append(comrades, comrade)
+		}
+	}
+	return comrades
+}
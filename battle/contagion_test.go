@@ -0,0 +1,129 @@
+package battle
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMoraleContagion makes sure kills demoralize the comrades
+// of the killed soldier and encourage the comrades of the attacker
+// within the morale contagion radius
+func TestMoraleContagion(t *testing.T) {
+	attrs := testAttributes(10)
+	attrs.AttackStrengthMin = 100
+	attrs.AttackStrengthMax = 100
+	attrs.HitChanceMin = 1
+	attrs.HitChanceMax = 1
+	attrs.DodgeChanceMin = 0
+	attrs.DodgeChanceMax = 0
+	attrs.MovementSpeed = 1
+	attrs.ComradeDeathMoralePenalty = .3
+	attrs.ComradeKillMoraleBonus = .1
+
+	for _, tc := range []struct {
+		name   string
+		field  *Field
+		radius float64
+	}{
+		{name: "entire faction"},
+		{name: "radius", field: &Field{Width: 50, Height: 50}, radius: 10},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			zone := Zone{}
+			if tc.field != nil {
+				zone = tc.field.Zone()
+			}
+			btl := newTestBattle(
+				t,
+				Config{
+					Seed:                  1,
+					Field:                 tc.field,
+					MoraleContagionRadius: tc.radius,
+				},
+				Faction{
+					Name:              "A",
+					ArmySize:          8,
+					SoldierAttributes: attrs,
+					Deployment:        zone,
+				},
+				Faction{
+					Name:              "B",
+					ArmySize:          8,
+					SoldierAttributes: attrs,
+					Deployment:        zone,
+				},
+			)
+			attacker := btl.Soldiers("A")[0]
+			killed := btl.Soldiers("B")[0]
+
+			// Comrades of the attacker have room to gain morale
+			for _, comrade := range btl.Soldiers("A")[1:] {
+				comrade.AddMorale(-.5)
+			}
+
+			if _, isKilled, err := attacker.Attack(killed); err != nil {
+				t.Fatal(err)
+			} else if !isKilled {
+				t.Fatal("the opponent survived")
+			}
+			if err := btl.log().PushEvent(
+				EventKill{Attacker: attacker, Killed: killed},
+				snapshotSoldiers(attacker, killed)...,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			// within returns true if the soldiers are within
+			// the contagion radius of each other
+			within := func(a, b Soldier) bool {
+				if tc.field == nil {
+					return true
+				}
+				field := btl.battlefield.(*fieldBattlefield)
+				return field.positions[a.ID()].Distance(
+					field.positions[b.ID()],
+				) <= tc.radius
+			}
+
+			affected, unaffected := 0, 0
+			check := func(
+				comrade Soldier,
+				origin Soldier,
+				before float64,
+				change float64,
+			) {
+				expected := before
+				if within(comrade, origin) {
+					expected += change
+					affected++
+				} else {
+					unaffected++
+				}
+				if morale := comrade.Status().Morale; math.Abs(
+					morale-expected,
+				) > 1e-9 {
+					t.Errorf(
+						"%s: morale %.2f, expected %.2f",
+						comrade.ID(),
+						morale,
+						expected,
+					)
+				}
+			}
+			for _, comrade := range btl.Soldiers("A")[1:] {
+				check(comrade, attacker, .5, attrs.ComradeKillMoraleBonus)
+			}
+			for _, comrade := range btl.Soldiers("B")[1:] {
+				check(comrade, killed, 1, -attrs.ComradeDeathMoralePenalty)
+			}
+
+			if affected < 1 || tc.field != nil && unaffected < 1 {
+				t.Errorf(
+					"%d comrades affected, %d unaffected",
+					affected,
+					unaffected,
+				)
+			}
+		})
+	}
+}
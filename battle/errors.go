@@ -8,6 +8,22 @@ var ErrMissed = errors.New("missed")
 // ErrDodged is an error that's returned by TakeDamage when a figher dodges
 var ErrDodged = errors.New("dodged")
 
+// ErrBlocked is an error that's returned by TakeDamage when a fighter
+// blocks the attack with the shield
+var ErrBlocked = errors.New("blocked")
+
 // ErrNoMoreOpponents is an error that's returned by Battlefield.FindOpponent
 // when no more opponents are left
 var ErrNoMoreOpponents = errors.New("no more opponents left")
+
+// ErrDead is an error that's returned by TakeDamage when the soldier
+// is already dead
+var ErrDead = errors.New("already dead")
+
+// ErrFled is an error that's returned by TakeDamage when the soldier
+// already fled the battlefield
+var ErrFled = errors.New("fled")
+
+// ErrNoWoundedAllies is an error that's returned by Battlefield.FindAlly
+// when no wounded allies are left
+var ErrNoWoundedAllies = errors.New("no wounded allies left")
@@ -0,0 +1,78 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// testTimeout limits the time tests wait for battles
+const testTimeout = 10 * time.Second
+
+// testAttributes returns the attributes of evenly matched test soldiers
+// of the given health
+func testAttributes(health float64) SoldierAttributes {
+	return SoldierAttributes{
+		HealthMin:             health,
+		HealthMax:             health,
+		AttackStrengthMin:     5,
+		AttackStrengthMax:     15,
+		DodgeChanceMin:        .2,
+		DodgeChanceMax:        .4,
+		HitChanceMin:          .4,
+		HitChanceMax:          .8,
+		MoraleIncrementFactor: 1,
+		MoraleDecrementFactor: 1,
+	}
+}
+
+// testFactions returns the factions A and B of the given size
+func testFactions(size uint, health float64) []Faction {
+	return []Faction{
+		{Name: "A", ArmySize: size, SoldierAttributes: testAttributes(health)},
+		{Name: "B", ArmySize: size, SoldierAttributes: testAttributes(health)},
+	}
+}
+
+// newTestBattle creates a new battle failing the test on error
+func newTestBattle(t *testing.T, config Config, factions ...Faction) *Battle {
+	t.Helper()
+	btl, err := NewBattle(config, factions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return btl
+}
+
+// runAsync runs the battle in the background
+func runAsync(ctx context.Context, btl *Battle) <-chan Result {
+	done := make(chan Result, 1)
+	go func() { done <- btl.Run(ctx) }()
+	return done
+}
+
+// awaitResult returns the result of a battle run by runAsync
+// failing the test if the battle doesn't finish in time
+func awaitResult(t *testing.T, done <-chan Result) Result {
+	t.Helper()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(testTimeout):
+		t.Fatal("the battle didn't finish in time")
+	}
+	return Result{}
+}
+
+// waitFor polls the condition until it's met
+// failing the test if it isn't met in time
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,164 @@
+package battle
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// pacer provides the pace of the battle to its soldiers
+type pacer interface {
+	// baseActionDelay returns the current base action delay
+	// (see Config.BaseActionDelay) scaled by the speed of the battle
+	baseActionDelay() time.Duration
+
+	// act takes the action of a soldier
+	act(action func())
+
+	// battleTime returns the time elapsed since the battle began.
+	// Sequential battles measure it in the time of their schedule
+	battleTime() time.Duration
+}
+
+// Pause freezes the battle. No soldier acts, no status effect ticks
+// and no reinforcement arrives until the battle is resumed,
+// actions already being taken are still completed.
+// The tickers keep the time left until their next tick.
+// Pausing a battle before it runs makes it start paused.
+// The deadline of the context the battle runs in and the duration
+// of the battle keep running while it's paused
+//
+// This method is thread-safe
+func (b *Battle) Pause() {
+	b.pause()
+}
+
+// pause pauses the battle and returns true if it already was paused
+func (b *Battle) pause() (wasPaused bool) {
+	b.pauseLock.Lock()
+	defer b.pauseLock.Unlock()
+
+	if b.paused {
+		return true
+	}
+	b.paused = true
+	if b.sched != nil {
+		b.sched.pause()
+		return false
+	}
+	for tk := range b.tickers {
+		tk.Pause()
+	}
+	return false
+}
+
+// Resume continues a paused battle
+//
+// This method is thread-safe
+func (b *Battle) Resume() {
+	b.pauseLock.Lock()
+	defer b.pauseLock.Unlock()
+
+	if !b.paused {
+		return
+	}
+	b.paused = false
+	if b.sched != nil {
+		b.sched.resume()
+		return
+	}
+	for tk := range b.tickers {
+		tk.Resume()
+	}
+}
+
+// Paused returns true if the battle is paused
+//
+// This method is thread-safe
+func (b *Battle) Paused() bool {
+	b.pauseLock.Lock()
+	defer b.pauseLock.Unlock()
+	return b.paused
+}
+
+// SetSpeed changes the speed of the battle scaling the base action delay
+// of the soldiers: a speed of 2 halves the delay between their actions.
+// The pending actions of the fighting soldiers are rescheduled
+// with the new delay while the intervals of status effects
+// and the delays of reinforcements and alliance breakups remain unchanged
+//
+// This method is thread-safe
+func (b *Battle) SetSpeed(factor float64) error {
+	if factor <= 0 {
+		return errors.Errorf("invalid speed: %.2f", factor)
+	}
+	b.pauseLock.Lock()
+	b.speed = factor
+	b.pauseLock.Unlock()
+
+	b.lock.Lock()
+	running := b.runCtx != nil && b.runCtx.Err() == nil
+	var fighting []Soldier
+	if running {
+		for _, faction := range b.factions {
+			fighting = append(fighting, b.alive[faction]...)
+			fighting = append(fighting, b.routed[faction]...)
+		}
+	}
+	b.lock.Unlock()
+
+	for _, fighter := range fighting {
+		if s, ok := fighter.(*soldier); ok {
+			s.repace()
+		}
+	}
+	return nil
+}
+
+// Speed returns the speed of the battle (see SetSpeed)
+//
+// This method is thread-safe
+func (b *Battle) Speed() float64 {
+	b.pauseLock.Lock()
+	defer b.pauseLock.Unlock()
+	return b.speed
+}
+
+// baseActionDelay implements the pacer interface
+func (b *Battle) baseActionDelay() time.Duration {
+	b.pauseLock.Lock()
+	defer b.pauseLock.Unlock()
+	return time.Duration(float64(b.config.BaseActionDelay) / b.speed)
+}
+
+// battleTime implements the pacer interface
+func (b *Battle) battleTime() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.sched != nil {
+		return b.elapsed + b.sched.elapsed()
+	}
+	return b.clock.Now().Sub(b.start)
+}
+
+// act implements the pacer interface.
+// Actions are taken concurrently unless a snapshot is being taken
+func (b *Battle) act(action func()) {
+	b.actionLock.RLock()
+	defer b.actionLock.RUnlock()
+	action()
+}
+
+// repace resets the action ticker of a fighting soldier
+// to apply a changed pace of the battle
+//
+// This method is thread-safe
+func (s *soldier) repace() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.status.Health <= 0 || s.status.Fled {
+		return
+	}
+	s.resetActionTicker()
+}
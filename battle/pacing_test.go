@@ -0,0 +1,144 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TestPauseResumeConcurrent pauses and resumes a battle
+// whose soldiers act concurrently
+func TestPauseResumeConcurrent(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		snapshot bool
+		speed    float64
+	}{
+		{name: "pause and resume"},
+		{name: "snapshot while paused", snapshot: true},
+		{name: "change speed while paused", speed: 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The soldiers are tough enough to outlast the test
+			btl := newTestBattle(
+				t,
+				Config{BaseActionDelay: 5 * time.Millisecond},
+				testFactions(20, 10000)...,
+			)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := runAsync(ctx, btl)
+
+			logged := func() int { return len(btl.Statistics().Log()) }
+			waitFor(t, "the battle to begin", func() bool {
+				return logged() > 0
+			})
+
+			// Snapshots and pauses wait for the actions being taken
+			// and hang if the soldiers deadlock
+			paused := make(chan error, 1)
+			go func() {
+				paused <- pauseRepeatedly(btl, 50, tc.snapshot, tc.speed)
+			}()
+			select {
+			case err := <-paused:
+				if err != nil {
+					t.Fatal(err)
+				}
+			case <-time.After(testTimeout):
+				t.Fatal("pausing the battle hung")
+			}
+
+			before := logged()
+			waitFor(t, "the resumed battle to continue", func() bool {
+				return logged() > before
+			})
+
+			cancel()
+			result := awaitResult(t, done)
+			if result.Outcome != OutcomeCanceled {
+				t.Errorf("unexpected outcome: %s", result.Outcome)
+			}
+		})
+	}
+}
+
+// TestReleaseTickers makes sure the battle stops tracking the tickers
+// of status effects, reinforcements and soldiers that stopped ticking
+func TestReleaseTickers(t *testing.T) {
+	bleeding := Loadout{Weight: 1, Equipment: Equipment{Weapon: &Weapon{
+		Effects: []StatusEffect{{
+			Kind:      EffectBleeding,
+			Chance:    1,
+			Interval:  time.Millisecond,
+			Ticks:     2,
+			Magnitude: 1,
+		}},
+	}}}
+	factions := testFactions(5, 50)
+	factions[0].Loadouts = []Loadout{bleeding}
+	factions[1].Reinforcements = []Wave{{
+		Size:              2,
+		SoldierAttributes: testAttributes(50),
+		Delay:             5 * time.Millisecond,
+	}}
+	btl := newTestBattle(
+		t,
+		Config{BaseActionDelay: time.Millisecond},
+		factions...,
+	)
+	awaitResult(t, runAsync(context.Background(), btl))
+
+	effects := 0
+	for _, entry := range btl.Statistics().Log() {
+		if _, isEffect := entry.Event.(EventEffectApplied); isEffect {
+			effects++
+		}
+	}
+	if effects < 1 {
+		t.Fatal("no status effects applied")
+	}
+	btl.pauseLock.Lock()
+	defer btl.pauseLock.Unlock()
+	if len(btl.tickers) > 0 {
+		t.Errorf("%d tickers still tracked", len(btl.tickers))
+	}
+}
+
+// pauseRepeatedly pauses and resumes the running battle the given number
+// of times optionally snapshotting it or changing its speed while paused
+func pauseRepeatedly(
+	btl *Battle,
+	times int,
+	snapshot bool,
+	speed float64,
+) error {
+	for i := 0; i < times; i++ {
+		btl.Pause()
+		if !btl.Paused() {
+			return errors.New("the battle isn't paused")
+		}
+		if snapshot {
+			if _, err := btl.Snapshot(); err != nil {
+				return errors.Wrapf(err, "snapshot %d", i)
+			}
+		}
+		if speed > 0 {
+			factor := speed
+			if i%2 == 1 {
+				factor = 1 / speed
+			}
+			if err := btl.SetSpeed(factor); err != nil {
+				return err
+			}
+		}
+		btl.Resume()
+		if btl.Paused() {
+			return errors.New("the battle is still paused")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package battle
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// rng represents a thread-safe seedable pseudo-random number generator.
+// It implements rand.Source64 using the SplitMix64 algorithm which keeps its
+// entire state in a single integer
+type rng struct {
+	lock  *sync.Mutex
+	state uint64
+}
+
+// newRNG creates a new pseudo-random number generator instance
+func newRNG(seed int64) *rng {
+	return &rng{
+		lock:  &sync.Mutex{},
+		state: uint64(seed),
+	}
+}
+
+// Seed implements the rand.Source interface
+func (r *rng) Seed(seed int64) {
+	r.lock.Lock()
+	r.state = uint64(seed)
+	r.lock.Unlock()
+}
+
+// snapshot returns the current state of the generator,
+// seeding the generator with it restores the state
+func (r *rng) snapshot() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.state
+}
+
+// Uint64 implements the rand.Source64 interface
+func (r *rng) Uint64() uint64 {
+	r.lock.Lock()
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	r.lock.Unlock()
+
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 implements the rand.Source interface
+func (r *rng) Int63() int64 {
+	return int64(r.Uint64() >> 1)
+}
+
+// float64 returns a pseudo-random number in [0.0,1.0)
+func (r *rng) float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// intn returns a pseudo-random number in [0,n)
+func (r *rng) intn(n int) int {
+	if n < 1 {
+		panic(errors.Errorf("invalid argument to intn: %d", n))
+	}
+	return int(r.Uint64() % uint64(n))
+}
+
+// random returns a pseudo-random number in [min,max)
+func (r *rng) random(min, max float64) float64 {
+	if min > max {
+		panic(errors.Errorf("min (%.1f) greater max (%.1f)", min, max))
+	}
+	return min + r.float64()*(max-min)
+}
+
+// luck returns true if we had luck given the chance percentage
+func (r *rng) luck(chance float64) bool {
+	if chance > 1 || chance < 0 {
+		panic(errors.Errorf("invalid chance value: %.1f", chance))
+	}
+	return r.random(0, 1) < chance
+}
@@ -0,0 +1,322 @@
+package battle
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// scheduler drives a set of dynamic tickers sequentially.
+// Ticks are delivered one at a time in the order of their due time
+// (ties are resolved by the order of creation of the tickers)
+// and the next tick isn't delivered before the current one is acknowledged.
+// The scheduler keeps its own logical time which is why the order
+// of the delivered ticks doesn't depend on goroutine scheduling
+type scheduler struct {
+	lock    *sync.Mutex
+	clock   Clock
+	now     time.Duration
+	queue   tickQueue
+	tickers uint64
+	pending int
+	changed chan struct{}
+
+	// shift is the total time the scheduler was paused for,
+	// the logical time is mapped onto the time of the clock shifted by it
+	paused   bool
+	pausedAt time.Time
+	shift    time.Duration
+
+	// delivering is set while a tick is delivered and processed
+	delivering bool
+	idle       *sync.Cond
+}
+
+// newScheduler creates a new scheduler instance
+func newScheduler(clock Clock) *scheduler {
+	lock := &sync.Mutex{}
+	return &scheduler{
+		lock:    lock,
+		clock:   clock,
+		changed: make(chan struct{}, 1),
+		idle:    sync.NewCond(lock),
+	}
+}
+
+// newTicker creates a new dynamic ticker driven by the scheduler.
+// The scheduler won't deliver any ticks until all of its tickers
+// have been reset at least once
+func (sc *scheduler) newTicker() *DynamicTicker {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	return sc.addTicker(sc.tickers)
+}
+
+// restoreTicker creates a new dynamic ticker driven by the scheduler
+// taking the given place in the order of creation of the tickers
+// (see newTicker)
+func (sc *scheduler) restoreTicker(index uint64) *DynamicTicker {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	return sc.addTicker(index)
+}
+
+// addTicker creates a new pending ticker of the given index.
+// Expects the scheduler lock to be locked
+func (sc *scheduler) addTicker(index uint64) *DynamicTicker {
+	tk := NewDynamicTicker(sc.clock)
+	tk.sched = sc
+	tk.index = index
+	tk.ack = make(chan struct{}, 1)
+	tk.pending = true
+
+	if index >= sc.tickers {
+		sc.tickers = index + 1
+	}
+	sc.pending++
+	return tk
+}
+
+// reset (re)schedules the given ticker to first tick the given delay after
+// the current logical time or removes it from the queue if the interval is 0
+func (sc *scheduler) reset(tk *DynamicTicker, first, interval time.Duration) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	if tk.pending {
+		tk.pending = false
+		sc.pending--
+		if sc.pending < 1 {
+			sc.idle.Broadcast()
+		}
+	}
+
+	if interval == 0 {
+		if tk.entry != nil {
+			heap.Remove(&sc.queue, tk.entry.index)
+			tk.entry = nil
+		}
+	} else if tk.entry != nil {
+		tk.entry.interval = interval
+		tk.entry.due = sc.now + first
+		heap.Fix(&sc.queue, tk.entry.index)
+	} else {
+		tk.entry = &scheduledTick{
+			ticker:   tk,
+			due:      sc.now + first,
+			interval: interval,
+		}
+		heap.Push(&sc.queue, tk.entry)
+	}
+
+	sc.notify()
+}
+
+// timer returns the time left until the next tick of the given ticker
+// and its interval and false if the ticker isn't scheduled
+func (sc *scheduler) timer(tk *DynamicTicker) (
+	remaining time.Duration,
+	interval time.Duration,
+	scheduled bool,
+) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	if tk.entry == nil {
+		return 0, 0, false
+	}
+	return tk.entry.due - sc.now, tk.entry.interval, true
+}
+
+// elapsed returns the logical time the scheduler has reached
+func (sc *scheduler) elapsed() time.Duration {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	return sc.now
+}
+
+// pause stops delivering ticks until the scheduler is resumed.
+// A tick that's currently being delivered is still processed
+func (sc *scheduler) pause() {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	if sc.paused {
+		return
+	}
+	sc.paused = true
+	sc.pausedAt = sc.clock.Now()
+	sc.notify()
+}
+
+// resume continues delivering ticks keeping the time
+// that was left until the scheduled ticks when the scheduler was paused
+func (sc *scheduler) resume() {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	if !sc.paused {
+		return
+	}
+	sc.paused = false
+	if _, isVirtual := sc.clock.(*VirtualClock); !isVirtual {
+		// Virtual time only passes while waiting for due ticks
+		sc.shift += sc.clock.Now().Sub(sc.pausedAt)
+	}
+	sc.notify()
+}
+
+// waitIdle waits until the tick that's currently being delivered
+// was processed and all tickers have been reset at least once
+func (sc *scheduler) waitIdle() {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	for sc.delivering || sc.pending > 0 {
+		sc.idle.Wait()
+	}
+}
+
+// notify notifies the scheduler about a change (non-blocking).
+// Expects the scheduler lock to be locked
+func (sc *scheduler) notify() {
+	select {
+	case sc.changed <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers ticks until the context is canceled.
+// The logical time is mapped onto the time of the clock starting from now
+// excluding the time the scheduler was paused for
+func (sc *scheduler) run(ctx context.Context) {
+	sc.lock.Lock()
+	start := sc.clock.Now()
+	// The time paused before the start doesn't shift the schedule
+	sc.shift = 0
+	if sc.paused {
+		sc.pausedAt = start
+	}
+	sc.lock.Unlock()
+
+	for {
+		sc.lock.Lock()
+		if sc.paused || sc.pending > 0 || len(sc.queue) < 1 {
+			// Wait for the scheduler to be resumed
+			// and for the tickers to be reset
+			sc.lock.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case <-sc.changed:
+				continue
+			}
+		}
+		next := sc.queue[0]
+		due := next.due
+		origin := start.Add(sc.shift)
+		sc.lock.Unlock()
+
+		// Wait for the tick to become due
+		if wait := origin.Add(due).Sub(sc.clock.Now()); wait > 0 {
+			timer := sc.clock.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-sc.changed:
+				// Re-evaluate the queue
+				timer.Stop()
+				continue
+			case <-timer.C():
+			}
+		}
+
+		sc.lock.Lock()
+		if sc.paused ||
+			len(sc.queue) < 1 ||
+			sc.queue[0] != next ||
+			next.due != due {
+			// The queue changed in the meantime
+			sc.lock.Unlock()
+			continue
+		}
+		sc.now = due
+		next.due += next.interval
+		heap.Fix(&sc.queue, 0)
+		tk := next.ticker
+		sc.delivering = true
+		sc.lock.Unlock()
+
+		delivered := sc.deliver(ctx, tk, origin.Add(due))
+
+		sc.lock.Lock()
+		sc.delivering = false
+		sc.idle.Broadcast()
+		sc.lock.Unlock()
+		if !delivered {
+			return
+		}
+	}
+}
+
+// deliver delivers a tick and waits for it to be processed.
+// Returns false if the context was canceled in the meantime
+func (sc *scheduler) deliver(
+	ctx context.Context,
+	tk *DynamicTicker,
+	tm time.Time,
+) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case tk.c <- tm:
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-tk.ack:
+	}
+	return true
+}
+
+// scheduledTick represents a ticker scheduled for delivery
+type scheduledTick struct {
+	ticker   *DynamicTicker
+	due      time.Duration
+	interval time.Duration
+	index    int
+}
+
+// tickQueue implements heap.Interface
+type tickQueue []*scheduledTick
+
+func (q tickQueue) Len() int { return len(q) }
+
+func (q tickQueue) Less(i, j int) bool {
+	if q[i].due == q[j].due {
+		return q[i].ticker.index < q[j].ticker.index
+	}
+	return q[i].due < q[j].due
+}
+
+func (q tickQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *tickQueue) Push(x interface{}) {
+	entry := x.(*scheduledTick)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *tickQueue) Pop() interface{} {
+	old := *q
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,152 @@
+package battle
+
+import (
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/wire"
+)
+
+// WireEntry converts a log entry into its wire representation
+// (see package wire) referencing the soldiers by their IDs
+// and their status at the time of the event
+func WireEntry(entry LogEntry) (wire.Entry, error) {
+	soldier := func(s Soldier) wire.Soldier {
+		id := s.ID()
+		ws := wire.Soldier{ID: wire.SoldierID(id)}
+		if snapshot, found := entry.Soldier(id); found {
+			ws.Health = snapshot.Health
+			ws.Morale = snapshot.Morale
+			ws.Stamina = snapshot.Stamina
+			ws.Routed = snapshot.Routed
+		}
+		return ws
+	}
+
+	var event wire.Event
+	switch ev := entry.Event.(type) {
+	case EventDodge:
+		event = wire.Dodge{
+			Attacker:        soldier(ev.Attacker),
+			Defender:        soldier(ev.Defernder),
+			MoralePenalty:   ev.MoralePenalty,
+			AttackerStamina: ev.AttackerStamina,
+			DefenderStamina: ev.DefenderStamina,
+		}
+	case EventBlock:
+		event = wire.Block{
+			Attacker:        soldier(ev.Attacker),
+			Defender:        soldier(ev.Defender),
+			MoralePenalty:   ev.MoralePenalty,
+			AttackerStamina: ev.AttackerStamina,
+		}
+	case EventMiss:
+		event = wire.Miss{
+			Attacker:        soldier(ev.Attacker),
+			Attacked:        soldier(ev.Attacked),
+			MoralePenalty:   ev.MoralePenalty,
+			AttackerStamina: ev.AttackerStamina,
+		}
+	case EventHit:
+		event = wire.Hit{
+			Attacker:        soldier(ev.Attacker),
+			Attacked:        soldier(ev.Attacked),
+			DamageDealt:     ev.DamageDealt,
+			MoraleBonus:     ev.MoraleBonus,
+			AttackerStamina: ev.AttackerStamina,
+			Critical:        ev.Critical,
+		}
+	case EventKill:
+		event = wire.Kill{
+			Attacker:        soldier(ev.Attacker),
+			Killed:          soldier(ev.Killed),
+			DamageDealt:     ev.DamageDealt,
+			MoraleBonus:     ev.MoraleBonus,
+			AttackerStamina: ev.AttackerStamina,
+			Critical:        ev.Critical,
+		}
+	case EventMove:
+		move := wire.Move{
+			Soldier: soldier(ev.Soldier),
+			From:    wire.Position(ev.From),
+			To:      wire.Position(ev.To),
+		}
+		if ev.Target != nil {
+			target := soldier(ev.Target)
+			move.Target = &target
+		}
+		event = move
+	case EventRout:
+		event = wire.Rout{Soldier: soldier(ev.Soldier), Morale: ev.Morale}
+	case EventRally:
+		event = wire.Rally{Soldier: soldier(ev.Soldier), Morale: ev.Morale}
+	case EventFlee:
+		event = wire.Flee{Soldier: soldier(ev.Soldier)}
+	case EventCommanderKilled:
+		event = wire.CommanderKilled{
+			Attacker:      soldier(ev.Attacker),
+			Commander:     soldier(ev.Commander),
+			MoralePenalty: ev.MoralePenalty,
+		}
+	case EventReinforcementsArrived:
+		soldiers := make([]wire.Soldier, len(ev.Soldiers))
+		for i, s := range ev.Soldiers {
+			soldiers[i] = soldier(s)
+		}
+		event = wire.ReinforcementsArrived{
+			Faction:  ev.Faction,
+			Wave:     ev.Wave,
+			Soldiers: soldiers,
+		}
+	case EventAllianceBroken:
+		event = wire.AllianceBroken{Faction: ev.Faction, Team: ev.Team}
+	case EventHeal:
+		event = wire.Heal{
+			Healer:  soldier(ev.Healer),
+			Healed:  soldier(ev.Healed),
+			Healing: ev.Healing,
+		}
+	case EventEffectApplied:
+		event = wire.EffectApplied{
+			Soldier: soldier(ev.Soldier),
+			Source:  soldier(ev.Source),
+			Effect: wire.StatusEffect{
+				Kind:      ev.Effect.Kind.String(),
+				Chance:    ev.Effect.Chance,
+				Interval:  ev.Effect.Interval,
+				Ticks:     ev.Effect.Ticks,
+				Magnitude: ev.Effect.Magnitude,
+			},
+		}
+	case EventEffectTick:
+		event = wire.EffectTick{
+			Soldier:   soldier(ev.Soldier),
+			Effect:    ev.Effect.String(),
+			Magnitude: ev.Magnitude,
+			TicksLeft: ev.TicksLeft,
+			Killed:    ev.Killed,
+		}
+	case EventEffectExpired:
+		event = wire.EffectExpired{
+			Soldier: soldier(ev.Soldier),
+			Effect:  ev.Effect.String(),
+		}
+	case EventEffectKill:
+		event = wire.EffectKill{
+			Source:      soldier(ev.Source),
+			Killed:      soldier(ev.Killed),
+			Effect:      ev.Effect.String(),
+			DamageDealt: ev.DamageDealt,
+		}
+	case EventLevelUp:
+		event = wire.LevelUp{
+			Soldier:    soldier(ev.Soldier),
+			Level:      ev.Level,
+			Experience: ev.Experience,
+		}
+	default:
+		return wire.Entry{}, errors.Errorf(
+			"event type without a wire representation: %T",
+			entry.Event,
+		)
+	}
+	return wire.Entry{Time: entry.Time, Event: event}, nil
+}
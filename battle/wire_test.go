@@ -0,0 +1,78 @@
+package battle
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/romshark/go-battle-simulator/wire"
+)
+
+// TestWireEntry converts an event of every recordable type
+// into its wire representation and decodes it again
+func TestWireEntry(t *testing.T) {
+	btl := newTestBattle(t, Config{Seed: 1}, testFactions(1, 50)...)
+	soldiers := append(btl.Soldiers("A"), btl.Soldiers("B")...)
+
+	names := make([]string, 0, len(recordableEvents))
+	for name := range recordableEvents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			event := testEvent(recordableEvents[name], soldiers)
+			entry := LogEntry{
+				Time:     btl.clock.Now(),
+				Event:    event,
+				Soldiers: snapshotSoldiers(soldiers...),
+			}
+			wireEntry, err := WireEntry(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := wire.NewJSONEncoder(buf).Encode(wireEntry); err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := wire.NewJSONDecoder(buf, nil).Decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, isUnknown := decoded.Event.(wire.Unknown); isUnknown {
+				t.Fatalf(
+					"wire event type %s isn't registered",
+					decoded.Event.EventType(),
+				)
+			}
+			if !reflect.DeepEqual(decoded.Event, wireEntry.Event) {
+				t.Fatalf(
+					"decoded %#v\nexpected %#v",
+					decoded.Event,
+					wireEntry.Event,
+				)
+			}
+		})
+	}
+}
+
+// testEvent returns an event of the given type
+// involving the given soldiers
+func testEvent(tp reflect.Type, soldiers []Soldier) Event {
+	event := reflect.New(tp).Elem()
+	next := 0
+	for i := 0; i < tp.NumField(); i++ {
+		field := event.Field(i)
+		switch field.Type() {
+		case soldierType:
+			field.Set(reflect.ValueOf(soldiers[next%len(soldiers)]))
+			next++
+		case soldiersType:
+			field.Set(reflect.ValueOf(soldiers))
+		}
+	}
+	return event.Interface().(Event)
+}
@@ -0,0 +1,349 @@
+package campaign
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// ErrOver is an error that's returned by Next when the campaign is over
+var ErrOver = errors.New("campaign is over")
+
+// Campaign represents a sequence of battles between the same factions.
+// The soldiers surviving a battle, including those that routed or fled,
+// carry their condition, experience, equipment and statistics over
+// to the next battle while the commanders and the reinforcement waves
+// are deployed anew in every battle
+type Campaign struct {
+	config   Config
+	factions []battle.Faction
+	units    map[string]*units
+	state    *State
+}
+
+// units represents the unit definitions of a faction by unit type
+type units struct {
+	types []string
+	defs  map[string]battle.Unit
+}
+
+// add adds a unit definition unless its unit type is already defined
+func (u *units) add(unit battle.Unit) {
+	if _, defined := u.defs[unit.Type]; defined {
+		return
+	}
+	u.types = append(u.types, unit.Type)
+	u.defs[unit.Type] = unit
+}
+
+// factionUnits returns the unit definitions of the faction including
+// the units of its reinforcement waves. The faction's own units take
+// precedence over the units of the waves of the same type
+func factionUnits(faction battle.Faction) *units {
+	u := &units{defs: make(map[string]battle.Unit)}
+	for _, unit := range armyUnits(faction) {
+		u.add(unit)
+	}
+	for _, wave := range faction.Reinforcements {
+		if len(wave.Units) > 0 {
+			for _, unit := range wave.Units {
+				u.add(unit)
+			}
+			continue
+		}
+		u.add(battle.Unit{
+			Count:             wave.Size,
+			SoldierAttributes: wave.SoldierAttributes,
+			Loadouts:          wave.Loadouts,
+		})
+	}
+	return u
+}
+
+// armyUnits returns the unit groups of the faction's army
+// (see battle.Faction.Units)
+func armyUnits(faction battle.Faction) []battle.Unit {
+	if len(faction.Units) > 0 {
+		return faction.Units
+	}
+	return []battle.Unit{{
+		Count:             faction.ArmySize,
+		SoldierAttributes: faction.SoldierAttributes,
+		Loadouts:          faction.Loadouts,
+		Veterans:          faction.Veterans,
+	}}
+}
+
+// New creates a new campaign of the given factions.
+// The armies of the factions form the rosters of the first battle
+func New(config Config, factions ...battle.Faction) (*Campaign, error) {
+	if config.Battle.Seed == 0 {
+		config.Battle.Seed = time.Now().UnixNano()
+	}
+	state := &State{
+		Seed:    config.Battle.Seed,
+		Rosters: make(map[string]map[string]Roster, len(factions)),
+	}
+	for _, faction := range factions {
+		rosters := make(map[string]Roster)
+		for _, unit := range armyUnits(faction) {
+			roster := Roster{Veterans: unit.Veterans}
+			if count := uint(len(unit.Veterans)); unit.Count > count {
+				roster.Recruits = unit.Count - count
+			}
+			rosters[unit.Type] = roster
+		}
+		state.Rosters[faction.Name] = rosters
+	}
+	return Resume(config, state, factions...)
+}
+
+// Resume resumes a campaign of the given factions from a saved state.
+// The seed of the state replaces the seed of the battle configuration
+func Resume(
+	config Config,
+	state *State,
+	factions ...battle.Faction,
+) (*Campaign, error) {
+	if state == nil {
+		return nil, errors.New("missing campaign state")
+	}
+	if len(state.Rosters) != len(factions) {
+		return nil, errors.Errorf(
+			"campaign state defines %d factions, got %d",
+			len(state.Rosters),
+			len(factions),
+		)
+	}
+
+	c := &Campaign{
+		config:   config,
+		factions: factions,
+		units:    make(map[string]*units, len(factions)),
+		state:    state.copy(),
+	}
+	c.config.Battle.Seed = state.Seed
+	for _, faction := range factions {
+		rosters, known := state.Rosters[faction.Name]
+		if !known {
+			return nil, errors.Errorf(
+				"faction %s missing in the campaign state",
+				faction.Name,
+			)
+		}
+		u := factionUnits(faction)
+		for unitType := range rosters {
+			if _, defined := u.defs[unitType]; !defined {
+				return nil, errors.Errorf(
+					"unknown unit type '%s' of faction %s in the campaign state",
+					unitType,
+					faction.Name,
+				)
+			}
+		}
+		c.units[faction.Name] = u
+	}
+	for factionName, replenishment := range config.Replenishment {
+		u, known := c.units[factionName]
+		if !known {
+			return nil, errors.Errorf(
+				"replenishment of unknown faction '%s'",
+				factionName,
+			)
+		}
+		if err := replenishment.Verify(); err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"invalid replenishment of faction %s",
+				factionName,
+			)
+		}
+		for unitType := range replenishment.Recruits {
+			if _, defined := u.defs[unitType]; !defined {
+				return nil, errors.Errorf(
+					"recruits of unknown unit type '%s' of faction %s",
+					unitType,
+					factionName,
+				)
+			}
+		}
+	}
+
+	// Verify the setup of the next battle before starting the campaign
+	if !c.Over() {
+		if _, _, err := c.newBattle(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// State returns a copy of the current state of the campaign
+func (c *Campaign) State() *State {
+	return c.state.copy()
+}
+
+// Over returns true once a faction was eliminated
+// or the maximum number of battles was reached
+func (c *Campaign) Over() bool {
+	return len(c.state.Eliminated) > 0 ||
+		c.config.Battles > 0 && uint(len(c.state.Battles)) >= c.config.Battles
+}
+
+// newBattle creates the next battle of the campaign
+// and returns the factions taking part in it
+func (c *Campaign) newBattle() (*battle.Battle, []battle.Faction, error) {
+	battleConfig := c.config.Battle
+	battleConfig.Clock = battle.NewVirtualClock(time.Time{})
+	battleConfig.Seed += int64(len(c.state.Battles))
+	if battleConfig.Seed == 0 {
+		// Avoid falling back to the non-deterministic mode
+		// by using a seed outside the range of the campaign
+		battleConfig.Seed = c.config.Battle.Seed - 1
+	}
+
+	factions := make([]battle.Faction, len(c.factions))
+	for i, faction := range c.factions {
+		faction.ArmySize = 0
+		faction.SoldierAttributes = battle.SoldierAttributes{}
+		faction.Loadouts = nil
+		faction.Veterans = nil
+		faction.Units = nil
+
+		u := c.units[faction.Name]
+		rosters := c.state.Rosters[faction.Name]
+		for _, unitType := range u.types {
+			roster := rosters[unitType]
+			count := uint(len(roster.Veterans)) + roster.Recruits
+			if count < 1 {
+				continue
+			}
+			unit := u.defs[unitType]
+			unit.Count = count
+			unit.Veterans = roster.Veterans
+			faction.Units = append(faction.Units, unit)
+		}
+		factions[i] = faction
+	}
+
+	btl, err := battle.NewBattle(battleConfig, factions...)
+	if err != nil {
+		return nil, nil, errors.Wrapf(
+			err,
+			"creating battle %d",
+			len(c.state.Battles),
+		)
+	}
+	return btl, factions, nil
+}
+
+// Next runs the next battle of the campaign and replenishes the factions
+// with the survivors and the recruits for the battle after.
+// Returns the finished battle and its record
+// or ErrOver if the campaign is over.
+// A battle interrupted by the cancellation or the deadline of the context
+// isn't recorded
+func (c *Campaign) Next(ctx context.Context) (*battle.Battle, Record, error) {
+	if c.Over() {
+		return nil, Record{}, ErrOver
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, Record{}, errors.Wrap(err, "campaign interrupted")
+	}
+	btl, factions, err := c.newBattle()
+	if err != nil {
+		return nil, Record{}, err
+	}
+
+	battleCtx := ctx
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		battleCtx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	result := btl.Run(battleCtx)
+	if err := ctx.Err(); err != nil {
+		// Only the timeout of the battle itself concludes it
+		return nil, Record{}, errors.Wrap(err, "battle interrupted")
+	}
+
+	record := Record{
+		Outcome:   result.Outcome,
+		Winners:   result.Winners,
+		Deployed:  result.Deployed,
+		Survivors: make(map[string]int, len(factions)),
+		Duration:  result.Duration,
+	}
+	for _, faction := range factions {
+		replenishment := c.config.Replenishment[faction.Name]
+		rosters := make(map[string]Roster)
+		survivors := 0
+		var soldiers []battle.Soldier
+		soldiers = append(soldiers, result.Survivors[faction.Name]...)
+		soldiers = append(soldiers, result.Routed[faction.Name]...)
+		soldiers = append(soldiers, result.Fled[faction.Name]...)
+		for _, soldier := range soldiers {
+			if soldier.IsCommander() {
+				continue
+			}
+			veteran := newVeteran(soldier)
+			replenishment.recover(&veteran)
+			roster := rosters[soldier.ID().Unit]
+			roster.Veterans = append(roster.Veterans, veteran)
+			rosters[soldier.ID().Unit] = roster
+			survivors++
+		}
+		for _, roster := range rosters {
+			sort.Slice(roster.Veterans, func(i, j int) bool {
+				return roster.Veterans[i].Name < roster.Veterans[j].Name
+			})
+		}
+		for unitType, recruits := range replenishment.Recruits {
+			roster := rosters[unitType]
+			roster.Recruits = recruits
+			rosters[unitType] = roster
+		}
+
+		record.Survivors[faction.Name] = survivors
+		c.state.Rosters[faction.Name] = rosters
+		if survivors < 1 {
+			c.state.Eliminated = append(c.state.Eliminated, faction.Name)
+		}
+	}
+	c.state.Battles = append(c.state.Battles, record)
+
+	return btl, record, nil
+}
+
+// Run runs the remaining battles of the campaign until it's over
+// or the context is canceled or exceeds its deadline
+func (c *Campaign) Run(ctx context.Context) error {
+	for !c.Over() {
+		if err := ctx.Err(); err != nil {
+			return errors.Wrap(err, "campaign interrupted")
+		}
+		if _, _, err := c.Next(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newVeteran turns a soldier surviving a battle into a veteran
+func newVeteran(soldier battle.Soldier) battle.Veteran {
+	status := soldier.Status()
+	equipment := soldier.Equipment()
+	return battle.Veteran{
+		Name:       soldier.ID().Name,
+		Experience: status.Experience,
+		MaxHealth:  status.MaxHealth,
+		Health:     status.Health,
+		Morale:     math.Max(math.Min(status.Morale, 1), 0),
+		Equipment:  &equipment,
+		Stats:      soldier.Stats(),
+	}
+}
@@ -0,0 +1,260 @@
+package campaign
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// testAttributes returns the attributes of evenly matched test soldiers
+// who break before their armies are wiped out
+func testAttributes() battle.SoldierAttributes {
+	return battle.SoldierAttributes{
+		HealthMin:                 40,
+		HealthMax:                 60,
+		AttackStrengthMin:         5,
+		AttackStrengthMax:         15,
+		DodgeChanceMin:            .2,
+		DodgeChanceMax:            .4,
+		HitChanceMin:              .4,
+		HitChanceMax:              .8,
+		MoraleIncrementFactor:     1,
+		MoraleDecrementFactor:     1,
+		MoraleBreakThreshold:      .4,
+		ComradeDeathMoralePenalty: .1,
+	}
+}
+
+// testFactions returns the factions of the test campaign
+func testFactions() []battle.Faction {
+	return []battle.Faction{
+		{
+			Name: "A",
+			Units: []battle.Unit{
+				{Type: "infantry", Count: 8, SoldierAttributes: testAttributes()},
+				{Type: "archers", Count: 4, SoldierAttributes: testAttributes()},
+			},
+		},
+		{Name: "B", ArmySize: 12, SoldierAttributes: testAttributes()},
+	}
+}
+
+// testConfig returns the configuration of the test campaign
+func testConfig() Config {
+	return Config{
+		Battles: 4,
+		Battle: battle.Config{
+			BaseActionDelay: 10 * time.Millisecond,
+			Seed:            7,
+			Veterancy: battle.Veterancy{
+				KillExperience:  10,
+				LevelExperience: 20,
+			},
+		},
+		Replenishment: map[string]Replenishment{
+			"A": {
+				Recruits: map[string]uint{"infantry": 3},
+				Healing:  .5,
+				Morale:   .5,
+			},
+			"B": {Recruits: map[string]uint{"": 4}, Healing: 1},
+		},
+	}
+}
+
+// TestResume saves the campaign after every battle, resumes it
+// from the saved state and makes sure it continues the same way
+func TestResume(t *testing.T) {
+	ctx := context.Background()
+	original, err := New(testConfig(), testFactions()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := original.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expected := encodeState(t, original.State())
+	battles := len(original.State().Battles)
+	if battles < 2 {
+		t.Fatalf("the campaign is over after %d battles", battles)
+	}
+
+	dir, err := ioutil.TempDir("", "campaign")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for saveAfter := 0; saveAfter < battles; saveAfter++ {
+		t.Run(fmt.Sprintf("after %d battles", saveAfter), func(t *testing.T) {
+			c, err := New(testConfig(), testFactions()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < saveAfter; i++ {
+				if _, _, err := c.Next(ctx); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			path := filepath.Join(dir, "state.json")
+			if err := c.State().Save(path); err != nil {
+				t.Fatal(err)
+			}
+			state, err := LoadState(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resumed, err := Resume(testConfig(), state, testFactions()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := resumed.Run(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if state := encodeState(t, resumed.State()); state != expected {
+				t.Fatalf("resumed campaign:\n%s\nexpected:\n%s", state, expected)
+			}
+			if _, _, err := resumed.Next(ctx); err != ErrOver {
+				t.Fatalf("expected ErrOver, got: %v", err)
+			}
+		})
+	}
+}
+
+// TestResumeInvalid resumes a campaign from invalid states
+// and with invalid configurations
+func TestResumeInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(config *Config, state *State)
+	}{
+		{
+			name:   "empty state",
+			modify: func(config *Config, state *State) { *state = State{} },
+		},
+		{
+			name: "unknown faction",
+			modify: func(config *Config, state *State) {
+				state.Rosters["C"] = state.Rosters["B"]
+				delete(state.Rosters, "B")
+			},
+		},
+		{
+			name: "unknown unit type",
+			modify: func(config *Config, state *State) {
+				state.Rosters["A"]["cavalry"] = Roster{Recruits: 1}
+			},
+		},
+		{
+			name: "replenishment of unknown faction",
+			modify: func(config *Config, state *State) {
+				config.Replenishment["C"] = Replenishment{}
+			},
+		},
+		{
+			name: "recruits of unknown unit type",
+			modify: func(config *Config, state *State) {
+				config.Replenishment["A"].Recruits["cavalry"] = 1
+			},
+		},
+		{
+			name: "invalid healing",
+			modify: func(config *Config, state *State) {
+				config.Replenishment["B"] = Replenishment{Healing: 1.5}
+			},
+		},
+		{
+			name: "invalid morale recovery",
+			modify: func(config *Config, state *State) {
+				config.Replenishment["B"] = Replenishment{Morale: -1}
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := New(testConfig(), testFactions()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, state := testConfig(), c.State()
+			tc.modify(&config, state)
+			if _, err := Resume(config, state, testFactions()...); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+// encodeState encodes the campaign state failing the test on error
+func encodeState(t *testing.T, state *State) string {
+	t.Helper()
+	data, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestDeadline makes sure an unbounded campaign stops once its context
+// exceeds the deadline without recording any interrupted battles
+func TestDeadline(t *testing.T) {
+	config := testConfig()
+	config.Battles = 0
+	config.Timeout = 5 * time.Millisecond
+
+	// Soldiers who never hit nor rout fight until the battle times out
+	pacify := func(attrs *battle.SoldierAttributes) {
+		attrs.HitChanceMin, attrs.HitChanceMax = 0, 0
+		attrs.MoraleBreakThreshold = 0
+	}
+	factions := testFactions()
+	for i := range factions {
+		pacify(&factions[i].SoldierAttributes)
+		for j := range factions[i].Units {
+			pacify(&factions[i].Units[j].SoldierAttributes)
+		}
+	}
+	c, err := New(config, factions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+
+	select {
+	case err := <-done:
+		if errors.Cause(err) != context.DeadlineExceeded {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the campaign didn't stop")
+	}
+
+	battles := c.State().Battles
+	if len(battles) < 1 {
+		t.Fatal("no battles recorded")
+	}
+	for i, record := range battles {
+		if record.Outcome != battle.OutcomeTimeout {
+			t.Errorf("battle %d: unexpected outcome: %s", i, record.Outcome)
+		}
+	}
+	if _, _, err := c.Next(ctx); errors.Cause(err) != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recorded := len(c.State().Battles); recorded != len(battles) {
+		t.Errorf("%d battles recorded after the deadline", recorded-len(battles))
+	}
+}
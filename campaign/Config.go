@@ -0,0 +1,69 @@
+package campaign
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// Config represents the configuration of a campaign
+type Config struct {
+	// Battles defines the maximum number of battles of the campaign.
+	// Zero makes the campaign last until a faction is eliminated
+	Battles uint
+
+	// Battle defines the configuration of each individual battle.
+	// Its seed serves as the base seed of the campaign: the n-th battle
+	// is seeded with Seed+n. A zero seed is replaced by a time-based seed.
+	// The battles are always simulated using a virtual clock
+	Battle battle.Config
+
+	// Timeout defines the maximum real-time duration of a single battle.
+	// Zero means no timeout
+	Timeout time.Duration
+
+	// Replenishment maps the faction names to the replenishment
+	// the factions receive between the battles
+	Replenishment map[string]Replenishment
+}
+
+// Replenishment represents the reinforcement and recovery of a faction
+// between two battles of a campaign
+type Replenishment struct {
+	// Recruits maps the unit types to the number of fresh soldiers
+	// joining the unit. The unit type of a faction that defines no units
+	// is the empty string
+	Recruits map[string]uint
+
+	// Healing defines the percentage of the missing health
+	// the survivors recover
+	Healing float64
+
+	// Morale defines the percentage of the lost morale
+	// the survivors recover
+	Morale float64
+}
+
+// Verify returns an error if the replenishment is invalid
+func (r *Replenishment) Verify() error {
+	if r.Healing < 0 || r.Healing > 1 {
+		return errors.Errorf("invalid healing %%: %.2f", r.Healing)
+	}
+	if r.Morale < 0 || r.Morale > 1 {
+		return errors.Errorf("invalid morale recovery %%: %.2f", r.Morale)
+	}
+	return nil
+}
+
+// recover lets a survivor recover from the battle.
+// The recovered health and morale are capped since rounding
+// could push them past the limits
+func (r *Replenishment) recover(veteran *battle.Veteran) {
+	veteran.Health = math.Min(
+		veteran.Health+(veteran.MaxHealth-veteran.Health)*r.Healing,
+		veteran.MaxHealth,
+	)
+	veteran.Morale = math.Min(veteran.Morale+(1-veteran.Morale)*r.Morale, 1)
+}
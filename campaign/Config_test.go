@@ -0,0 +1,51 @@
+package campaign
+
+import (
+	"testing"
+
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// TestRecover lets veterans recover between battles
+func TestRecover(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		replenishment Replenishment
+		veteran       battle.Veteran
+		health        float64
+		morale        float64
+	}{
+		{
+			name:    "no recovery",
+			veteran: battle.Veteran{MaxHealth: 50, Health: 20, Morale: .5},
+			health:  20,
+			morale:  .5,
+		},
+		{
+			name:          "partial recovery",
+			replenishment: Replenishment{Healing: .5, Morale: .5},
+			veteran:       battle.Veteran{MaxHealth: 50, Health: 20, Morale: .5},
+			health:        35,
+			morale:        .75,
+		},
+		{
+			// Rounding would push the health past the max health
+			name:          "full recovery",
+			replenishment: Replenishment{Healing: 1, Morale: 1},
+			veteran:       battle.Veteran{MaxHealth: 55.9, Health: 23.7},
+			health:        55.9,
+			morale:        1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			veteran := tc.veteran
+			tc.replenishment.recover(&veteran)
+			if veteran.Health != tc.health {
+				t.Errorf("health %v, expected %v", veteran.Health, tc.health)
+			}
+			if veteran.Morale != tc.morale {
+				t.Errorf("morale %v, expected %v", veteran.Morale, tc.morale)
+			}
+		})
+	}
+}
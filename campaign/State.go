@@ -0,0 +1,108 @@
+package campaign
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// Roster represents the soldiers of a unit of a faction
+// joining the next battle of a campaign
+type Roster struct {
+	// Veterans represents the survivors of the previous battles
+	Veterans []battle.Veteran
+
+	// Recruits represents the number of fresh soldiers
+	Recruits uint
+}
+
+// Record represents the record of a battle of a campaign
+type Record struct {
+	Outcome battle.Outcome
+
+	// Winners represents the names of the winning factions (if any)
+	Winners []string
+
+	// Deployed represents the number of soldiers per faction name
+	// that took part in the battle
+	Deployed map[string]int
+
+	// Survivors represents the number of soldiers per faction name
+	// carried over to the next battle
+	Survivors map[string]int
+
+	// Duration represents the simulated duration of the battle
+	Duration time.Duration
+}
+
+// State represents the progress of a campaign
+// that can be saved and resumed
+type State struct {
+	// Scenario is the name of the scenario file the campaign
+	// was started from (if any)
+	Scenario string
+
+	// Seed represents the base seed of the campaign (see Config.Battle)
+	Seed int64
+
+	// Battles represents the records of the battles fought so far
+	Battles []Record
+
+	// Rosters maps the faction names to the rosters
+	// of their units by unit type
+	Rosters map[string]map[string]Roster
+
+	// Eliminated represents the names of the factions
+	// that lost all of their soldiers
+	Eliminated []string
+}
+
+// copy returns a deep copy of the state
+func (s *State) copy() *State {
+	cp := *s
+	cp.Battles = append([]Record(nil), s.Battles...)
+	cp.Eliminated = append([]string(nil), s.Eliminated...)
+	cp.Rosters = make(map[string]map[string]Roster, len(s.Rosters))
+	for faction, rosters := range s.Rosters {
+		cp.Rosters[faction] = make(map[string]Roster, len(rosters))
+		for unitType, roster := range rosters {
+			roster.Veterans = append([]battle.Veteran(nil), roster.Veterans...)
+			cp.Rosters[faction][unitType] = roster
+		}
+	}
+	return &cp
+}
+
+// LoadState loads a campaign state from a file
+func LoadState(path string) (*State, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading campaign state")
+	}
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.Wrap(err, "decoding campaign state")
+	}
+	return state, nil
+}
+
+// Save writes the campaign state to a file.
+// The file is replaced only once the state is completely written
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "encoding campaign state")
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Wrap(err, "writing campaign state")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrap(err, "replacing campaign state")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// saveSnapshot saves the snapshot of a battle to a file
+func saveSnapshot(snapshot *battle.Snapshot, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating snapshot file")
+	}
+	if err := snapshot.Encode(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// snapshotAfter runs the battle in virtual time, snapshots it
+// after the action that logged the given number of events
+// and lets it finish. Returns nil if the battle ended before
+func snapshotAfter(
+	config battle.Config,
+	factions []battle.Faction,
+	events int,
+) (*battle.Snapshot, battle.Result) {
+	config.Clock = battle.NewVirtualClock(time.Now())
+	btl, err := battle.NewBattle(config, factions...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The battle is paused by the subscriber and snapshotted by the caller
+	// since subscribers can't take snapshots of the battle they're blocking.
+	// The unbuffered subscription keeps the battle close to the subscriber
+	sub := btl.Statistics().Subscribe(0, battle.OverflowBlock)
+	reached := make(chan struct{})
+	go func() {
+		logged := 0
+		for range sub.C() {
+			logged++
+			if logged == events {
+				btl.Pause()
+				close(reached)
+			}
+		}
+	}()
+
+	done := make(chan battle.Result, 1)
+	go func() { done <- btl.Run(context.Background()) }()
+
+	select {
+	case <-reached:
+	case result := <-done:
+		return nil, result
+	}
+	snapshot, err := btl.Snapshot()
+	btl.Resume()
+	result := <-done
+	if err != nil {
+		// The battle ended in the meantime
+		return nil, result
+	}
+	return snapshot, result
+}
+
+// runBranches snapshots a battle in the middle and runs a batch
+// of differently seeded continuations from the snapshot
+func runBranches(args []string) {
+	flags := flag.NewFlagSet("branch", flag.ExitOnError)
+	events := flags.Int(
+		"at",
+		100,
+		"number of logged events to snapshot the battle after",
+	)
+	runs := flags.Uint("runs", 100, "number of continuations to run")
+	seed := flags.Int64(
+		"seed",
+		0,
+		"seed of the battle and base seed of the continuations "+
+			"(0 for a random seed)",
+	)
+	savePath := flags.String(
+		"save",
+		"",
+		"file to save the snapshot to",
+	)
+	scenarioPath := scenarioFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+	if *events < 1 {
+		log.Fatalf("invalid number of events: %d", *events)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	config, factions := loadConf(*scenarioPath, *seed)
+
+	snapshot, original := snapshotAfter(config, factions, *events)
+	if snapshot == nil {
+		log.Fatalf(
+			"The battle ended after %d events before the snapshot",
+			original.Events,
+		)
+	}
+	log.Printf(
+		"Snapshot taken after %d events (%s into the battle)",
+		len(snapshot.Recording.Entries),
+		snapshot.Elapsed,
+	)
+	log.Printf("Original outcome: %s", describeOutcome(original))
+	if *savePath != "" {
+		if err := saveSnapshot(snapshot, *savePath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	log.Printf("Running %d continuations...", *runs)
+	outcomes := make(map[string]uint)
+	var order []string
+	var duration time.Duration
+	for i := uint(0); i < *runs; i++ {
+		snapshot.Reseed(*seed + int64(i) + 1)
+		config.Clock = battle.NewVirtualClock(snapshot.Recording.Start)
+		btl, err := battle.RestoreBattle(snapshot, config, factions...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result := btl.Run(context.Background())
+		duration += result.Duration - snapshot.Elapsed
+
+		outcome := describeOutcome(result)
+		if _, seen := outcomes[outcome]; !seen {
+			order = append(order, outcome)
+		}
+		outcomes[outcome]++
+	}
+
+	if *runs > 0 {
+		log.Printf(
+			"Mean remaining battle duration: %s",
+			duration/time.Duration(*runs),
+		)
+	}
+	for _, outcome := range order {
+		log.Printf(
+			"  %s: %d (%.1f%%)",
+			outcome,
+			outcomes[outcome],
+			float64(outcomes[outcome])/float64(*runs)*100,
+		)
+	}
+}
+
+// describeOutcome describes the outcome of a battle
+func describeOutcome(result battle.Result) string {
+	if result.Outcome == battle.OutcomeVictory {
+		return "victory of " + strings.Join(result.Winners, ", ")
+	}
+	return result.Outcome.String()
+}
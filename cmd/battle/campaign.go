@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/campaign"
+	"github.com/romshark/go-battle-simulator/scenario"
+)
+
+// runCampaign runs a campaign of battles carrying the survivors over
+// from battle to battle and saves its progress after every battle
+func runCampaign(args []string) {
+	flags := flag.NewFlagSet("campaign", flag.ExitOnError)
+	battles := flags.Uint(
+		"battles",
+		0,
+		"maximum number of battles (0 for the number defined by the scenario)",
+	)
+	seed := flags.Int64(
+		"seed",
+		0,
+		"base seed for a reproducible campaign (0 for a random campaign)",
+	)
+	timeout := flags.Duration(
+		"timeout",
+		time.Second*10,
+		"maximum real-time duration of a single battle",
+	)
+	statePath := flags.String(
+		"state",
+		"",
+		"campaign state file to resume from and to save the progress to",
+	)
+	scenarioPath := scenarioFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	// Resume the campaign if the state file already exists
+	var state *campaign.State
+	if *statePath != "" {
+		if _, err := os.Stat(*statePath); err == nil {
+			if state, err = campaign.LoadState(*statePath); err != nil {
+				log.Fatal(err)
+			}
+			if *scenarioPath == "" {
+				*scenarioPath = state.Scenario
+			}
+			*seed = state.Seed
+		}
+	}
+	if *seed == 0 {
+		// Roll the armies reproducibly to be able to resume the campaign
+		*seed = time.Now().UnixNano()
+	}
+
+	config, factions := loadConf(*scenarioPath, *seed)
+	campaignConfig := campaign.Config{Battle: config}
+	if *scenarioPath != "" {
+		// Already verified by loadConf
+		scn, _ := scenario.Load(*scenarioPath)
+		campaignConfig = scn.Campaign()
+		campaignConfig.Battle = config
+	}
+	campaignConfig.Timeout = *timeout
+	if *battles > 0 {
+		campaignConfig.Battles = *battles
+	}
+
+	var cmp *campaign.Campaign
+	var err error
+	if state != nil {
+		cmp, err = campaign.Resume(campaignConfig, state, factions...)
+		log.Printf("Resuming the campaign after %d battles", len(state.Battles))
+	} else {
+		cmp, err = campaign.New(campaignConfig, factions...)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for !cmp.Over() {
+		_, record, err := cmp.Next(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		state := cmp.State()
+		printRecord(len(state.Battles), record, factions)
+
+		if *statePath != "" {
+			state.Scenario = *scenarioPath
+			if err := state.Save(*statePath); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	state = cmp.State()
+	if len(state.Eliminated) > 0 {
+		log.Printf(
+			"The campaign ended after %d battles! Eliminated: %s",
+			len(state.Battles),
+			strings.Join(state.Eliminated, ", "),
+		)
+		return
+	}
+	log.Printf("The campaign ended after %d battles!", len(state.Battles))
+}
+
+// printRecord prints the record of the n-th battle of a campaign
+func printRecord(n int, record campaign.Record, factions []battle.Faction) {
+	if record.Outcome == battle.OutcomeVictory {
+		log.Printf(
+			"Battle %d ended after %s! Winners: %s",
+			n,
+			record.Duration,
+			strings.Join(record.Winners, ", "),
+		)
+	} else {
+		log.Printf(
+			"Battle %d ended after %s without a winner (%s)",
+			n,
+			record.Duration,
+			record.Outcome,
+		)
+	}
+	for _, faction := range factions {
+		log.Printf(
+			"  Faction '%s': %d of %d carried over",
+			faction.Name,
+			record.Survivors[faction.Name],
+			record.Deployed[faction.Name],
+		)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// controlBattle reads the commands controlling the running battle
+// line by line until the reader is exhausted:
+//
+//	pause           pauses the battle
+//	resume          resumes the battle
+//	speed <factor>  changes the speed of the battle
+//	status          prints the status of the soldiers still fighting
+func controlBattle(
+	btl *battle.Battle,
+	factions []battle.Faction,
+	r io.Reader,
+) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 1 {
+			continue
+		}
+		switch fields[0] {
+		case "pause", "p":
+			btl.Pause()
+			log.Print("Battle paused")
+		case "resume", "r":
+			btl.Resume()
+			log.Print("Battle resumed")
+		case "speed":
+			if len(fields) != 2 {
+				log.Print("usage: speed <factor>")
+				continue
+			}
+			factor, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				log.Printf("invalid speed: %s", fields[1])
+				continue
+			}
+			if err := btl.SetSpeed(factor); err != nil {
+				log.Print(err)
+				continue
+			}
+			log.Printf("Battle speed set to %.2f", factor)
+		case "status", "s":
+			printStatus(btl, factions)
+		default:
+			log.Printf("unknown command: %s", fields[0])
+		}
+	}
+}
+
+// printStatus prints the status of the soldiers still fighting
+func printStatus(btl *battle.Battle, factions []battle.Faction) {
+	for _, faction := range factions {
+		for _, soldier := range btl.Soldiers(faction.Name) {
+			status := soldier.Status()
+			if status.Health <= 0 || status.Fled {
+				continue
+			}
+			routed := ""
+			if status.Routed {
+				routed = ", routed"
+			}
+			log.Printf(
+				"%s: %.1f/%.1f health, %.0f%% morale, %.0f%% stamina%s",
+				soldier.ID(),
+				status.Health,
+				status.MaxHealth,
+				status.Morale*100,
+				status.Stamina*100,
+				routed,
+			)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/wire"
+)
+
+// wireEncoder writes wire log entries
+type wireEncoder interface {
+	Encode(entry wire.Entry) error
+}
+
+// wireDecoder reads wire log entries
+type wireDecoder interface {
+	Decode() (wire.Entry, error)
+}
+
+// isJSONLog returns true if the log file at the given path
+// is in the JSON lines format, otherwise it's in the binary format
+func isJSONLog(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json", ".ndjson":
+		return true
+	}
+	return false
+}
+
+// saveLog saves the battle log to a file in the wire format
+// selected by the extension of the file
+func saveLog(entries []battle.LogEntry, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating log file")
+	}
+	buf := bufio.NewWriter(file)
+
+	var encoder wireEncoder = wire.NewBinaryEncoder(buf)
+	if isJSONLog(path) {
+		encoder = wire.NewJSONEncoder(buf)
+	}
+	for _, entry := range entries {
+		wireEntry, err := battle.WireEntry(entry)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if err := encoder.Encode(wireEntry); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		file.Close()
+		return errors.Wrap(err, "writing log file")
+	}
+	return file.Close()
+}
+
+// runEvents prints a battle log saved in the wire format
+// (see the -log flag of the run command)
+func runEvents(args []string) {
+	flags := flag.NewFlagSet("events", flag.ExitOnError)
+	logPath := flags.String(
+		"log",
+		"",
+		"log file (.jsonl for JSON lines, binary otherwise)",
+	)
+	filter := flags.String(
+		"type",
+		"",
+		"print only the events of the given type such as \"kill\"",
+	)
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+	if *logPath == "" {
+		log.Fatal("missing log file")
+	}
+
+	file, err := os.Open(*logPath)
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "opening log file"))
+	}
+	defer file.Close()
+
+	var decoder wireDecoder = wire.NewBinaryDecoder(file, nil)
+	if isJSONLog(*logPath) {
+		decoder = wire.NewJSONDecoder(file, nil)
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	for {
+		entry, err := decoder.Decode()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			out.Flush()
+			log.Fatal(err)
+		}
+		eventType := entry.Event.EventType()
+		if *filter != "" && eventType != *filter {
+			continue
+		}
+		data, err := json.Marshal(entry.Event)
+		if err != nil {
+			out.Flush()
+			log.Fatal(err)
+		}
+		fmt.Fprintf(
+			out,
+			"%s %s %s\n",
+			entry.Time.Format("15:04:05.000"),
+			eventType,
+			data,
+		)
+	}
+}
@@ -2,15 +2,20 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"math/rand"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/scenario"
 )
 
-func init()                           { rand.Seed(time.Now().Unix()) }
-func random(min, max float64) float64 { return min + rand.Float64()*(max-min) }
+var rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func random(min, max float64) float64 { return min + rnd.Float64()*(max-min) }
 
 /*************************************************************\
 	CONF
@@ -18,60 +23,200 @@ func random(min, max float64) float64 { return min + rand.Float64()*(max-min) }
 
 var confBaseActionDelay = time.Millisecond * 100
 
-var confFactions = []battle.Faction{
-	battle.Faction{
-		Name:     "A",
-		ArmySize: 1,
-		SoldierAttributes: battle.SoldierAttributes{
-			HealthMin:             random(25, 50),
-			HealthMax:             random(50, 70),
-			AttackStrengthMin:     random(5, 10),
-			AttackStrengthMax:     random(10, 20),
-			DodgeChanceMin:        random(.25, .5),
-			DodgeChanceMax:        random(.5, .75),
-			HitChanceMin:          random(.25, .5),
-			HitChanceMax:          random(.5, .75),
-			MoraleIncrementFactor: random(1, 1.5),
-			MoraleDecrementFactor: random(1, 1.5),
+func confFactions() []battle.Faction {
+	return []battle.Faction{
+		battle.Faction{
+			Name:     "A",
+			ArmySize: 1,
+			SoldierAttributes: battle.SoldierAttributes{
+				HealthMin:             random(25, 50),
+				HealthMax:             random(50, 70),
+				AttackStrengthMin:     random(5, 10),
+				AttackStrengthMax:     random(10, 20),
+				DodgeChanceMin:        random(.25, .5),
+				DodgeChanceMax:        random(.5, .75),
+				HitChanceMin:          random(.25, .5),
+				HitChanceMax:          random(.5, .75),
+				MoraleIncrementFactor: random(1, 1.5),
+				MoraleDecrementFactor: random(1, 1.5),
+			},
 		},
-	},
-	battle.Faction{
-		Name:     "B",
-		ArmySize: 1,
-		SoldierAttributes: battle.SoldierAttributes{
-			HealthMin:             random(25, 50),
-			HealthMax:             random(50, 70),
-			AttackStrengthMin:     random(5, 10),
-			AttackStrengthMax:     random(10, 20),
-			DodgeChanceMin:        random(.25, .5),
-			DodgeChanceMax:        random(.5, .75),
-			HitChanceMin:          random(.25, .5),
-			HitChanceMax:          random(.5, .75),
-			MoraleIncrementFactor: random(1, 1.5),
-			MoraleDecrementFactor: random(1, 1.5),
+		battle.Faction{
+			Name:     "B",
+			ArmySize: 1,
+			SoldierAttributes: battle.SoldierAttributes{
+				HealthMin:             random(25, 50),
+				HealthMax:             random(50, 70),
+				AttackStrengthMin:     random(5, 10),
+				AttackStrengthMax:     random(10, 20),
+				DodgeChanceMin:        random(.25, .5),
+				DodgeChanceMax:        random(.5, .75),
+				HitChanceMin:          random(.25, .5),
+				HitChanceMax:          random(.5, .75),
+				MoraleIncrementFactor: random(1, 1.5),
+				MoraleDecrementFactor: random(1, 1.5),
+			},
 		},
-	},
+	}
+}
+
+// loadConf returns the battle configuration and the factions either
+// from the given scenario file or from the built-in configuration.
+// A non-zero seed overrides the seed of the scenario
+// and makes the rolled faction attributes reproducible
+func loadConf(scenarioPath string, seed int64) (
+	battle.Config,
+	[]battle.Faction,
+) {
+	config := battle.Config{
+		BaseActionDelay: confBaseActionDelay,
+	}
+	var scn *scenario.Scenario
+	if scenarioPath != "" {
+		var err error
+		if scn, err = scenario.Load(scenarioPath); err != nil {
+			log.Fatal(err)
+		}
+		config = scn.Config()
+	}
+	if seed != 0 {
+		config.Seed = seed
+	}
+
+	if config.Seed != 0 {
+		rnd = rand.New(rand.NewSource(config.Seed))
+	}
+	if scn != nil {
+		return config, scn.Roll(rnd)
+	}
+	return config, confFactions()
+}
+
+// scenarioFlag defines the scenario file flag of a command
+func scenarioFlag(flags *flag.FlagSet) *string {
+	return flags.String(
+		"scenario",
+		"",
+		"scenario file (.json, .yaml or .yml) defining the factions",
+	)
+}
+
+/*************************************************************\
+	COMMANDS
+\*************************************************************/
+
+// commands maps the command names to their implementations
+var commands = map[string]func(args []string){
+	"run":        runBattle,
+	"montecarlo": runMonteCarlo,
+	"campaign":   runCampaign,
+	"replay":     runReplay,
+	"events":     runEvents,
+	"branch":     runBranches,
+	"serve":      runServer,
 }
 
 func main() {
-	btl, err := battle.NewBattle(
-		battle.Config{
-			BaseActionDelay: confBaseActionDelay,
-		},
-		confFactions...,
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			command(os.Args[2:])
+			return
+		}
+	}
+
+	// Run a single battle by default
+	runBattle(os.Args[1:])
+}
+
+// runBattle runs a single battle streaming the battle log to the console
+func runBattle(args []string) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	seed := flags.Int64(
+		"seed",
+		0,
+		"seed for a reproducible battle (0 for a random battle)",
+	)
+	virtualTime := flags.Bool(
+		"virtual",
+		false,
+		"simulate the battle in virtual time as fast as possible",
+	)
+	recordPath := flags.String(
+		"record",
+		"",
+		"file to save the recording of the battle to (see the replay command)",
+	)
+	logPath := flags.String(
+		"log",
+		"",
+		"file to save the battle log to (.jsonl for JSON lines, binary otherwise)",
+	)
+	interactive := flags.Bool(
+		"interactive",
+		false,
+		"control the battle through the standard input "+
+			"(pause, resume, speed <factor> and status)",
 	)
+	scenarioPath := scenarioFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	config, factions := loadConf(*scenarioPath, *seed)
+	if *virtualTime {
+		config.Clock = battle.NewVirtualClock(time.Now())
+	}
+
+	btl, err := battle.NewBattle(config, factions...)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	ctx, can := context.WithTimeout(context.Background(), time.Second*6)
+	var ctx context.Context
+	var can context.CancelFunc
+	if *interactive {
+		// Pauses mustn't make the battle time out
+		ctx, can = context.WithCancel(context.Background())
+	} else {
+		ctx, can = context.WithTimeout(context.Background(), time.Second*6)
+	}
 	defer can()
 
-	statistics := btl.Statistics()
-
 	// Start real-time log stream listener
+	streamDone := printLog(btl.Statistics())
+
+	if *interactive {
+		go controlBattle(btl, factions, os.Stdin)
+	}
+
+	log.Print("The battle begins!")
+	result := btl.Run(ctx)
+
+	// Wait for the log stream to be printed
+	<-streamDone
+
+	printResult(result, factions)
+
+	if *recordPath != "" {
+		if err := saveRecording(btl, *recordPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *logPath != "" {
+		if err := saveLog(btl.Statistics().Log(), *logPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// printLog prints the log stream of the given statistics
+// and returns a channel that's closed once the stream ended
+func printLog(statistics battle.StatisticsReader) <-chan struct{} {
+	logStream := statistics.LogStream()
+	streamDone := make(chan struct{})
 	go func() {
-		for battleLogEntry := range statistics.LogStream() {
+		defer close(streamDone)
+		for battleLogEntry := range logStream {
 			tm := battleLogEntry.Time
 			log.Printf(
 				"%d:%d:%d - %s",
@@ -82,8 +227,54 @@ func main() {
 			)
 		}
 	}()
+	return streamDone
+}
 
-	log.Print("The battle begins!")
-	btl.Run(ctx)
-	log.Printf("Battle ended! Faction '%s' wins!", statistics.WinnerFaction())
+// printResult prints the outcome and the survivors of a battle
+func printResult(result battle.Result, factions []battle.Faction) {
+	switch {
+	case result.Outcome == battle.OutcomeVictory && len(result.Winners) == 1:
+		log.Printf(
+			"Battle ended after %s! Faction '%s' wins!",
+			result.Duration,
+			result.Winners[0],
+		)
+	case result.Outcome == battle.OutcomeVictory:
+		log.Printf(
+			"Battle ended after %s! Team '%s' (%s) wins!",
+			result.Duration,
+			result.WinnerTeam,
+			strings.Join(result.Winners, ", "),
+		)
+	default:
+		log.Printf(
+			"Battle ended after %s without a winner (%s)",
+			result.Duration,
+			result.Outcome,
+		)
+	}
+	for _, faction := range factions {
+		log.Printf(
+			"Faction '%s': %d of %d survived (%d routed, %d fled)",
+			faction.Name,
+			len(result.Survivors[faction.Name]),
+			result.Deployed[faction.Name],
+			len(result.Routed[faction.Name]),
+			len(result.Fled[faction.Name]),
+		)
+
+		// Break the survivors down by unit type
+		survivors := make(map[string]uint, len(faction.Units))
+		for _, soldier := range result.Survivors[faction.Name] {
+			survivors[soldier.ID().Unit]++
+		}
+		for _, unit := range faction.Units {
+			log.Printf(
+				"  %s: %d of %d survived",
+				unit.Type,
+				survivors[unit.Type],
+				unit.Count,
+			)
+		}
+	}
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/romshark/go-battle-simulator/montecarlo"
+)
+
+// runMonteCarlo runs a batch of battles and prints the win probabilities
+func runMonteCarlo(args []string) {
+	flags := flag.NewFlagSet("montecarlo", flag.ExitOnError)
+	runs := flags.Uint("runs", 1000, "number of battles to run")
+	parallelism := flags.Uint(
+		"parallel",
+		0,
+		"maximum number of parallel battles (0 for the number of CPU cores)",
+	)
+	seed := flags.Int64(
+		"seed",
+		0,
+		"base seed for a reproducible batch (0 for a random batch)",
+	)
+	timeout := flags.Duration(
+		"timeout",
+		time.Second*10,
+		"maximum real-time duration of a single battle",
+	)
+	confidence := flags.Float64(
+		"confidence",
+		0.95,
+		"confidence level of the win rate intervals",
+	)
+	scenarioPath := scenarioFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	config, factions := loadConf(*scenarioPath, *seed)
+
+	log.Printf("Running %d battles...", *runs)
+	start := time.Now()
+
+	report, err := montecarlo.Run(
+		context.Background(),
+		montecarlo.Config{
+			Runs:            *runs,
+			Parallelism:     *parallelism,
+			Battle:          config,
+			Timeout:         *timeout,
+			ConfidenceLevel: *confidence,
+		},
+		factions...,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf(
+		"%d battles simulated in %s (mean battle duration: %s, undecided: %d)",
+		report.Runs,
+		time.Since(start),
+		report.MeanDuration,
+		report.Undecided,
+	)
+	for _, faction := range report.Factions {
+		log.Printf(
+			"Faction '%s': win rate %.1f%% (%.0f%% CI: %.1f%% - %.1f%%)",
+			faction.Name,
+			faction.WinRate*100,
+			report.ConfidenceLevel*100,
+			faction.WinRateInterval.Low*100,
+			faction.WinRateInterval.High*100,
+		)
+		log.Printf(
+			"  survivors: %.2f of %d (mean)",
+			faction.MeanSurvivors,
+			faction.ArmySize,
+		)
+		log.Printf(
+			"  casualties: min %d, max %d, mean %.2f, median %.1f, stddev %.2f",
+			faction.Casualties.Min,
+			faction.Casualties.Max,
+			faction.Casualties.Mean,
+			faction.Casualties.Median,
+			faction.Casualties.StdDev,
+		)
+		for casualties, count := range faction.Casualties.Histogram {
+			log.Printf(
+				"    %d casualties: %.1f%%",
+				casualties,
+				float64(count)/float64(report.Runs)*100,
+			)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// saveRecording saves the recording of a battle to a file
+func saveRecording(btl *battle.Battle, path string) error {
+	recording, err := btl.Recording()
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating recording file")
+	}
+	if err := recording.Encode(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// loadRecording loads the recording of a battle from a file
+func loadRecording(path string) (*battle.Recording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening recording file")
+	}
+	defer file.Close()
+	return battle.DecodeRecording(file)
+}
+
+// runReplay replays a recorded battle printing the same output
+// as the recorded run
+func runReplay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := flags.Float64(
+		"speed",
+		1,
+		"replay speed multiplier (0 to replay without delay)",
+	)
+	step := flags.Bool(
+		"step",
+		false,
+		"replay the events one by one, pressing enter for the next event",
+	)
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+	if flags.NArg() != 1 {
+		log.Fatal("usage: battle replay [-speed factor] [-step] <recording>")
+	}
+
+	recording, err := loadRecording(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	replay, err := battle.NewReplay(recording)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	streamDone := printLog(replay.Statistics())
+
+	log.Print("The battle begins!")
+	if *step {
+		input := bufio.NewScanner(os.Stdin)
+		for input.Scan() {
+			if _, err := replay.Step(); err == battle.ErrReplayOver {
+				break
+			} else if err != nil {
+				log.Fatal(err)
+			}
+		}
+		// Finish the replay in case the input ended first
+		if err := replay.Play(context.Background(), 0); err != nil {
+			log.Fatal(err)
+		}
+	} else if err := replay.Play(context.Background(), *speed); err != nil {
+		log.Fatal(err)
+	}
+
+	<-streamDone
+
+	if result, ok := replay.Result(); ok {
+		printResult(result, replay.Factions())
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/romshark/go-battle-simulator/server"
+)
+
+// runServer serves the HTTP API (see package server)
+// until it's interrupted
+func runServer(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	scenarioDir := flags.String(
+		"scenarios",
+		"scenarios",
+		"directory of the scenario files battles can be created from",
+	)
+	timeout := flags.Duration(
+		"timeout",
+		0,
+		"maximum duration of a battle (0 for no limit)",
+	)
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	srv := server.New(server.Config{
+		ScenarioDir: *scenarioDir,
+		Timeout:     *timeout,
+	})
+	httpServer := &http.Server{Addr: *addr, Handler: srv}
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	shutdownDone := make(chan struct{})
+	go func() {
+		<-interrupted
+		log.Print("Shutting down...")
+		// Cancel the battles first to end their event streams
+		srv.Close()
+		// Streams of battles that were never started don't end by themselves
+		ctx, can := context.WithTimeout(context.Background(), time.Second*5)
+		defer can()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			httpServer.Close()
+		}
+		close(shutdownDone)
+	}()
+
+	log.Printf("Listening on %s", *addr)
+	if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	<-shutdownDone
+}
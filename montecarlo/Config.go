@@ -0,0 +1,31 @@
+package montecarlo
+
+import (
+	"time"
+
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// Config represents the configuration of a batch of battles
+type Config struct {
+	// Runs defines the number of battles to run
+	Runs uint
+
+	// Parallelism defines the maximum number of battles running in parallel
+	// and defaults to the number of available CPU cores
+	Parallelism uint
+
+	// Battle defines the configuration of each individual battle.
+	// Its seed serves as the base seed of the batch: the n-th battle
+	// is seeded with Seed+n. A zero seed is replaced by a time-based seed.
+	// The battles are always simulated using a virtual clock
+	Battle battle.Config
+
+	// Timeout defines the maximum real-time duration of a single battle.
+	// A battle exceeding it is counted as undecided. Zero means no timeout
+	Timeout time.Duration
+
+	// ConfidenceLevel defines the confidence level of the win rate
+	// confidence intervals and defaults to 0.95
+	ConfidenceLevel float64
+}
@@ -0,0 +1,67 @@
+package montecarlo
+
+import "time"
+
+// Report represents the aggregated results of a batch of battles
+type Report struct {
+	// Runs represents the number of battles run
+	Runs uint
+
+	// Undecided represents the number of battles that ended without a winner
+	Undecided uint
+
+	// MeanDuration represents the mean simulated duration of a battle
+	MeanDuration time.Duration
+
+	// ConfidenceLevel represents the confidence level of the intervals
+	ConfidenceLevel float64
+
+	// Factions represents the faction reports
+	// in the order the factions were defined
+	Factions []FactionReport
+}
+
+// FactionReport represents the aggregated results of a single faction
+type FactionReport struct {
+	// Name represents the name of the faction
+	Name string
+
+	// ArmySize represents the initial size of the faction's army
+	ArmySize uint
+
+	// Wins represents the number of battles won by the faction
+	// or by its team
+	Wins uint
+
+	// WinRate represents the share of battles won
+	WinRate float64
+
+	// WinRateInterval represents the Wilson score confidence interval
+	// of the win rate
+	WinRateInterval Interval
+
+	// MeanSurvivors represents the mean number of surviving soldiers
+	MeanSurvivors float64
+
+	// Casualties represents the distribution of the number of casualties
+	Casualties Distribution
+}
+
+// Interval represents a closed interval
+type Interval struct {
+	Low  float64
+	High float64
+}
+
+// Distribution represents the distribution of a discrete value
+type Distribution struct {
+	Min    uint
+	Max    uint
+	Mean   float64
+	StdDev float64
+	Median float64
+
+	// Histogram represents the number of occurrences of each value,
+	// the n-th element is the number of occurrences of the value n
+	Histogram []uint
+}
@@ -0,0 +1,220 @@
+package montecarlo
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// outcome represents the outcome of a single battle
+type outcome struct {
+	winners    []string
+	duration   time.Duration
+	casualties []uint
+	survivors  []uint
+}
+
+// Run runs a batch of independent battles of the given factions in parallel
+// and aggregates their outcomes into a report
+func Run(
+	ctx context.Context,
+	config Config,
+	factions ...battle.Faction,
+) (*Report, error) {
+	if config.Runs < 1 {
+		return nil, errors.New("invalid number of runs: 0")
+	}
+	if config.Parallelism < 1 {
+		config.Parallelism = uint(runtime.NumCPU())
+	}
+	if config.ConfidenceLevel == 0 {
+		config.ConfidenceLevel = 0.95
+	}
+	if config.ConfidenceLevel <= 0 || config.ConfidenceLevel >= 1 {
+		return nil, errors.Errorf(
+			"invalid confidence level: %.3f",
+			config.ConfidenceLevel,
+		)
+	}
+	if config.Battle.Seed == 0 {
+		config.Battle.Seed = time.Now().UnixNano()
+	}
+
+	// Verify the battle setup before starting the batch
+	if _, err := newBattle(config, 0, factions); err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]outcome, config.Runs)
+	jobs := make(chan uint)
+	errs := make(chan error, config.Parallelism)
+	wg := &sync.WaitGroup{}
+
+	wg.Add(int(config.Parallelism))
+	for i := uint(0); i < config.Parallelism; i++ {
+		go func() {
+			defer wg.Done()
+			for run := range jobs {
+				out, err := runBattle(ctx, config, run, factions)
+				if err != nil {
+					errs <- err
+					return
+				}
+				outcomes[run] = out
+			}
+		}()
+	}
+
+	var err error
+DISPATCH:
+	for run := uint(0); run < config.Runs; run++ {
+		select {
+		case <-ctx.Done():
+			err = errors.Wrap(ctx.Err(), "batch canceled")
+			break DISPATCH
+		case err = <-errs:
+			break DISPATCH
+		case jobs <- run:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err == nil {
+		// Check for errors of the last runs
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		// Battles canceled during the last runs would be counted as undecided
+		return nil, errors.Wrap(err, "batch canceled")
+	}
+
+	return newReport(config, factions, outcomes), nil
+}
+
+// newBattle creates the n-th battle of the batch
+func newBattle(
+	config Config,
+	run uint,
+	factions []battle.Faction,
+) (*battle.Battle, error) {
+	battleConfig := config.Battle
+	battleConfig.Clock = battle.NewVirtualClock(time.Time{})
+	battleConfig.Seed += int64(run)
+	if battleConfig.Seed == 0 {
+		// Avoid falling back to the non-deterministic mode
+		// by using a seed outside the range of the batch
+		battleConfig.Seed = config.Battle.Seed + int64(config.Runs)
+	}
+
+	// Don't share the priority targets with the other battles of the batch
+	own := make([]battle.Faction, len(factions))
+	copy(own, factions)
+	for i := range own {
+		if _, ok := own[i].Targeting.(*battle.FocusFireTargeting); ok {
+			own[i].Targeting = battle.NewFocusFireTargeting()
+		}
+	}
+
+	return battle.NewBattle(battleConfig, own...)
+}
+
+// runBattle runs the n-th battle of the batch
+func runBattle(
+	ctx context.Context,
+	config Config,
+	run uint,
+	factions []battle.Faction,
+) (outcome, error) {
+	btl, err := newBattle(config, run, factions)
+	if err != nil {
+		return outcome{}, errors.Wrapf(err, "creating battle %d", run)
+	}
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	result := btl.Run(ctx)
+
+	out := outcome{
+		duration:   result.Duration,
+		casualties: make([]uint, len(factions)),
+		survivors:  make([]uint, len(factions)),
+	}
+	if result.Outcome == battle.OutcomeVictory {
+		out.winners = result.Winners
+	}
+	for i, faction := range factions {
+		out.survivors[i] = uint(len(result.Survivors[faction.Name]))
+		out.casualties[i] = uint(result.Deployed[faction.Name]) -
+			out.survivors[i]
+	}
+
+	return out, nil
+}
+
+// newReport aggregates the outcomes of a batch
+func newReport(
+	config Config,
+	factions []battle.Faction,
+	outcomes []outcome,
+) *Report {
+	report := &Report{
+		Runs:            config.Runs,
+		ConfidenceLevel: config.ConfidenceLevel,
+		Factions:        make([]FactionReport, len(factions)),
+	}
+
+	totalDuration := time.Duration(0)
+	wins := make(map[string]uint, len(factions))
+	for _, out := range outcomes {
+		totalDuration += out.duration
+		if len(out.winners) < 1 {
+			report.Undecided++
+			continue
+		}
+		// Allied factions win together
+		for _, winner := range out.winners {
+			wins[winner]++
+		}
+	}
+	report.MeanDuration = totalDuration / time.Duration(len(outcomes))
+
+	casualties := make([]uint, len(outcomes))
+	for i, faction := range factions {
+		survivors := uint(0)
+		for run, out := range outcomes {
+			casualties[run] = out.casualties[i]
+			survivors += out.survivors[i]
+		}
+
+		report.Factions[i] = FactionReport{
+			Name:     faction.Name,
+			ArmySize: faction.Size(),
+			Wins:     wins[faction.Name],
+			WinRate:  float64(wins[faction.Name]) / float64(len(outcomes)),
+			WinRateInterval: wilsonInterval(
+				wins[faction.Name],
+				uint(len(outcomes)),
+				config.ConfidenceLevel,
+			),
+			MeanSurvivors: float64(survivors) / float64(len(outcomes)),
+			Casualties:    newDistribution(casualties, faction.MaxSize()),
+		}
+	}
+
+	return report
+}
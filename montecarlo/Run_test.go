@@ -0,0 +1,189 @@
+package montecarlo
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// testFactions returns the factions A and B of the given size
+func testFactions(size uint) []battle.Faction {
+	attrs := battle.SoldierAttributes{
+		HealthMin:             50,
+		HealthMax:             50,
+		AttackStrengthMin:     5,
+		AttackStrengthMax:     15,
+		DodgeChanceMin:        .2,
+		DodgeChanceMax:        .4,
+		HitChanceMin:          .4,
+		HitChanceMax:          .8,
+		MoraleIncrementFactor: 1,
+		MoraleDecrementFactor: 1,
+	}
+	return []battle.Faction{
+		{Name: "A", ArmySize: size, SoldierAttributes: attrs},
+		{Name: "B", ArmySize: size, SoldierAttributes: attrs},
+	}
+}
+
+// TestReproducible makes sure batches of the same seed yield the same report
+// regardless of the order the parallel battles are run in
+func TestReproducible(t *testing.T) {
+	factions := testFactions(5)
+	focusFire, err := battle.NewTargetingStrategy("focusFire")
+	if err != nil {
+		t.Fatal(err)
+	}
+	factions[0].Targeting = focusFire
+
+	config := Config{
+		Runs:        20,
+		Parallelism: 4,
+		Battle: battle.Config{
+			BaseActionDelay: 10 * time.Millisecond,
+			Seed:            42,
+		},
+	}
+	expected, err := Run(context.Background(), config, factions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		report, err := Run(context.Background(), config, factions...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(report, expected) {
+			t.Fatalf("report %#v\nexpected %#v", report, expected)
+		}
+	}
+}
+
+// TestSharedTargeting makes sure the battles of a batch don't share
+// the state of the targeting strategies of the factions
+func TestSharedTargeting(t *testing.T) {
+	config := Config{
+		Runs:        5,
+		Parallelism: 1,
+		Battle: battle.Config{
+			BaseActionDelay: 10 * time.Millisecond,
+			Seed:            42,
+		},
+	}
+
+	// The opponents differ in health for the priority targets to matter
+	newFactions := func() []battle.Faction {
+		factions := testFactions(5)
+		factions[1].SoldierAttributes.HealthMin = 10
+		factions[1].SoldierAttributes.HealthMax = 100
+		return factions
+	}
+	report := func(focusFire battle.TargetingStrategy) *Report {
+		factions := newFactions()
+		factions[0].Targeting = focusFire
+		report, err := Run(context.Background(), config, factions...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+	expected := report(battle.NewFocusFireTargeting())
+
+	// Leave the strongest opponent of the first battle
+	// as the priority target in the strategy
+	leftover := battle.NewFocusFireTargeting()
+	first, err := newBattle(config, 0, newFactions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	strongest := battle.StrongestTargeting{}.SelectTarget(
+		first.Soldiers("A")[0],
+		first.Soldiers("B"),
+		nil,
+	)
+	leftover.SelectTarget(
+		first.Soldiers("A")[0],
+		[]battle.Soldier{strongest},
+		nil,
+	)
+
+	if r := report(leftover); !reflect.DeepEqual(r, expected) {
+		t.Fatalf("report %#v\nexpected %#v", r, expected)
+	}
+}
+
+// TestCasualties makes sure every deployed soldier is counted
+// as either a survivor or a casualty
+func TestCasualties(t *testing.T) {
+	reinforced := testFactions(5)
+	reinforced[1].Reinforcements = []battle.Wave{{
+		Size:              3,
+		SoldierAttributes: reinforced[1].SoldierAttributes,
+		ArmyThreshold:     .5,
+	}}
+
+	for _, tc := range []struct {
+		name     string
+		factions []battle.Faction
+	}{
+		{name: "fixed armies", factions: testFactions(5)},
+		{name: "reinforcements", factions: reinforced},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := Config{
+				Runs: 20,
+				Battle: battle.Config{
+					BaseActionDelay: 10 * time.Millisecond,
+					Seed:            1,
+				},
+			}
+			report, err := Run(context.Background(), config, tc.factions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, faction := range report.Factions {
+				casualties := faction.Casualties
+				runs := uint(0)
+				for _, n := range casualties.Histogram {
+					runs += n
+				}
+				if runs != config.Runs {
+					t.Errorf(
+						"%s: %d runs in the histogram, expected %d",
+						faction.Name,
+						runs,
+						config.Runs,
+					)
+				}
+
+				// Reinforcements are only deployed if they arrive in time
+				deployed := casualties.Mean + faction.MeanSurvivors
+				minSize := float64(tc.factions[i].Size())
+				maxSize := float64(tc.factions[i].MaxSize())
+				if deployed < minSize-1e-9 || deployed > maxSize+1e-9 {
+					t.Errorf(
+						"%s: %.2f soldiers deployed on average, "+
+							"expected between %.0f and %.0f",
+						faction.Name,
+						deployed,
+						minSize,
+						maxSize,
+					)
+				}
+				if tc.factions[i].Size() == tc.factions[i].MaxSize() &&
+					math.Abs(deployed-minSize) > 1e-9 {
+					t.Errorf(
+						"%s: %.2f soldiers deployed on average, expected %.0f",
+						faction.Name,
+						deployed,
+						minSize,
+					)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package montecarlo
+
+import (
+	"math"
+	"sort"
+)
+
+// wilsonInterval computes the Wilson score interval of a binomial proportion
+// for the given confidence level
+func wilsonInterval(successes, trials uint, confidenceLevel float64) Interval {
+	if trials < 1 {
+		return Interval{Low: 0, High: 1}
+	}
+	z := math.Sqrt2 * math.Erfinv(confidenceLevel)
+	n := float64(trials)
+	p := float64(successes) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return Interval{
+		Low:  math.Max(0, center-margin),
+		High: math.Min(1, center+margin),
+	}
+}
+
+// newDistribution computes the distribution of the given values
+func newDistribution(values []uint, maxValue uint) Distribution {
+	dist := Distribution{
+		Histogram: make([]uint, maxValue+1),
+	}
+	if len(values) < 1 {
+		return dist
+	}
+
+	sorted := make([]uint, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	dist.Min = sorted[0]
+	dist.Max = sorted[len(sorted)-1]
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += float64(v)
+		dist.Histogram[v]++
+	}
+	dist.Mean = sum / float64(len(sorted))
+
+	variance := 0.0
+	for _, v := range sorted {
+		d := float64(v) - dist.Mean
+		variance += d * d
+	}
+	dist.StdDev = math.Sqrt(variance / float64(len(sorted)))
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		dist.Median = float64(sorted[middle-1]+sorted[middle]) / 2
+	} else {
+		dist.Median = float64(sorted[middle])
+	}
+
+	return dist
+}
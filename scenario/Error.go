@@ -0,0 +1,39 @@
+package scenario
+
+import "fmt"
+
+// Error represents an error located in a scenario file
+type Error struct {
+	// File is the name of the scenario file
+	File string
+
+	// Line and Column locate the error in the file,
+	// both are 0 if the location is unknown
+	Line   int
+	Column int
+
+	// Field is the path of the erroneous field
+	// (such as factions[0].soldierAttributes.healthMin),
+	// it's empty for syntax errors
+	Field string
+
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface
+func (err *Error) Error() string {
+	location := err.File
+	if err.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", err.File, err.Line, err.Column)
+	}
+	if err.Field == "" {
+		return fmt.Sprintf("%s: %s", location, err.Err)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, err.Field, err.Err)
+}
+
+// Cause returns the underlying error
+func (err *Error) Cause() error {
+	return err.Err
+}
@@ -0,0 +1,303 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/campaign"
+	"gopkg.in/yaml.v3"
+)
+
+// Format represents a scenario file format
+type Format string
+
+const (
+	// FormatJSON represents the JSON scenario file format
+	FormatJSON Format = "json"
+
+	// FormatYAML represents the YAML scenario file format
+	FormatYAML Format = "yaml"
+)
+
+// FormatOf determines the format of a scenario file by its extension
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	}
+	return "", errors.Errorf("unsupported scenario file extension: '%s'", path)
+}
+
+// Scenario represents a battle scenario
+type Scenario struct {
+	// File is the name of the scenario file
+	File string
+
+	BaseActionDelay       time.Duration
+	Seed                  int64
+	Field                 *battle.Field
+	DamageModifiers       battle.DamageMatrix
+	MoraleContagionRadius float64
+	Veterancy             battle.Veterancy
+	Factions              []Faction
+
+	// Battles is the maximum number of battles of a campaign
+	// (see campaign.Config)
+	Battles uint
+}
+
+// Load loads a scenario file in either the JSON or the YAML format
+// depending on the file extension
+func Load(path string) (*Scenario, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading scenario file")
+	}
+	return Parse(path, format, data)
+}
+
+// Parse parses and validates a scenario. Errors located in the file
+// are returned as *Error
+func Parse(file string, format Format, data []byte) (*Scenario, error) {
+	switch format {
+	case FormatJSON:
+		// Enforce strict JSON syntax before parsing the node tree
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			scErr := &Error{File: file, Err: err}
+			if syntaxErr, ok := err.(*json.SyntaxError); ok {
+				scErr.Line, scErr.Column = position(data, syntaxErr.Offset)
+			}
+			return nil, scErr
+		}
+	case FormatYAML:
+	default:
+		return nil, errors.Errorf("unsupported scenario format: '%s'", format)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, &Error{File: file, Err: err}
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) < 1 {
+		return nil, &Error{File: file, Err: errors.New("empty scenario")}
+	}
+
+	d := &decoder{file: file}
+	return d.scenario(root.Content[0])
+}
+
+// Config returns the battle configuration of the scenario
+func (s *Scenario) Config() battle.Config {
+	return battle.Config{
+		BaseActionDelay:       s.BaseActionDelay,
+		Seed:                  s.Seed,
+		Field:                 s.Field,
+		DamageModifiers:       s.DamageModifiers,
+		MoraleContagionRadius: s.MoraleContagionRadius,
+		Veterancy:             s.Veterancy,
+	}
+}
+
+// Campaign returns the campaign configuration of the scenario
+func (s *Scenario) Campaign() campaign.Config {
+	config := campaign.Config{
+		Battles:       s.Battles,
+		Battle:        s.Config(),
+		Replenishment: make(map[string]campaign.Replenishment),
+	}
+	for _, faction := range s.Factions {
+		if faction.Replenishment != nil {
+			config.Replenishment[faction.Name] = *faction.Replenishment
+		}
+	}
+	return config
+}
+
+// Roll rolls the soldier attributes of all factions
+// within their defined ranges
+func (s *Scenario) Roll(rnd *rand.Rand) []battle.Faction {
+	factions := make([]battle.Faction, len(s.Factions))
+	for i, faction := range s.Factions {
+		factions[i] = battle.Faction{
+			Name:           faction.Name,
+			Deployment:     faction.Deployment,
+			Team:           faction.Team,
+			Neutral:        faction.Neutral,
+			AttackNeutrals: faction.AttackNeutrals,
+			Command: battle.Command{
+				SquadSize:          faction.Command.SquadSize,
+				Order:              battle.Order{Kind: faction.Command.Order},
+				Aura:               faction.Command.Aura,
+				DeathMoralePenalty: faction.Command.DeathMoralePenalty,
+			},
+		}
+		if len(faction.Units) > 0 {
+			factions[i].Units = rollUnits(faction.Units, rnd)
+		} else {
+			factions[i].ArmySize = faction.ArmySize
+			factions[i].SoldierAttributes = roll(
+				faction.SoldierAttributes,
+				rnd,
+			)
+			factions[i].Loadouts = faction.Loadouts
+			factions[i].Veterans = faction.Veterans
+		}
+		for _, wave := range faction.Reinforcements {
+			w := battle.Wave{
+				Size:          wave.Size,
+				Units:         rollUnits(wave.Units, rnd),
+				Delay:         wave.Delay,
+				ArmyThreshold: wave.ArmyThreshold,
+			}
+			if len(wave.Units) < 1 {
+				w.SoldierAttributes = roll(wave.SoldierAttributes, rnd)
+				w.Loadouts = wave.Loadouts
+			}
+			factions[i].Reinforcements = append(
+				factions[i].Reinforcements,
+				w,
+			)
+		}
+		if faction.Command.Commander != nil {
+			commander := roll(faction.Command.Commander, rnd)
+			factions[i].Command.Commander = &commander
+		}
+		if faction.BreakAlliance > 0 {
+			factions[i].BreakAlliance = &battle.Trigger{
+				Delay: faction.BreakAlliance,
+			}
+		}
+		if faction.Targeting != "" {
+			// Verified during parsing
+			factions[i].Targeting, _ = battle.NewTargetingStrategy(
+				faction.Targeting,
+			)
+		}
+	}
+	return factions
+}
+
+// position translates a byte offset into a line and a column number
+func position(data []byte, offset int64) (line, column int) {
+	line, column = 1, 1
+	for i := int64(0); i < offset && i < int64(len(data)); i++ {
+		if data[i] == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+	return
+}
+
+// scenario decodes and validates the scenario root node
+func (d *decoder) scenario(node *yaml.Node) (*Scenario, error) {
+	fields, err := d.mapping(node, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scenario{File: d.file}
+	var delayNode, factionsNode, modifiersNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "baseActionDelay":
+			delayNode = f.value
+			s.BaseActionDelay, err = d.duration(f.value, f.path)
+		case "seed":
+			s.Seed, err = d.int(f.value, f.path)
+		case "field":
+			s.Field, err = d.field(f.value, f.path)
+		case "moraleContagionRadius":
+			s.MoraleContagionRadius, err = d.float(f.value, f.path)
+			if err == nil && s.MoraleContagionRadius < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		case "veterancy":
+			s.Veterancy, err = d.veterancy(f.value, f.path)
+		case "campaign":
+			s.Battles, err = d.campaign(f.value, f.path)
+		case "damageModifiers":
+			modifiersNode = f.value
+			s.DamageModifiers, err = d.damageModifiers(f.value, f.path)
+		case "factions":
+			factionsNode = f.value
+			s.Factions, err = d.factions(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if delayNode == nil {
+		return nil, d.errorf(node, "baseActionDelay", "missing")
+	}
+	if s.BaseActionDelay <= 0 {
+		return nil, d.errorf(
+			delayNode,
+			"baseActionDelay",
+			"must be greater 0",
+		)
+	}
+	if factionsNode == nil {
+		return nil, d.errorf(node, "factions", "missing")
+	}
+	if len(s.Factions) < 2 {
+		return nil, d.errorf(
+			factionsNode,
+			"factions",
+			"at least 2 factions required, got %d",
+			len(s.Factions),
+		)
+	}
+
+	if modifiersNode != nil {
+		if err := d.verifyUnitTypes(s, modifiersNode); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.Field != nil {
+		// Verify the deployment zones
+		fieldZone := s.Field.Zone()
+		for i, faction := range s.Factions {
+			path := fmt.Sprintf("factions[%d].deployment", i)
+			if faction.deploymentNode == nil {
+				return nil, d.errorf(
+					factionsNode.Content[i],
+					path,
+					"missing (required on a field)",
+				)
+			}
+			if !fieldZone.Contains(faction.Deployment.Min) ||
+				!fieldZone.Contains(faction.Deployment.Max) {
+				return nil, d.errorf(
+					faction.deploymentNode,
+					path,
+					"exceeds the field (%.1fx%.1f)",
+					s.Field.Width,
+					s.Field.Height,
+				)
+			}
+		}
+	}
+
+	return s, nil
+}
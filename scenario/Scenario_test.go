@@ -0,0 +1,119 @@
+package scenario
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// TestLoad loads every scenario file of the repository
+// and creates a battle from it
+func TestLoad(t *testing.T) {
+	files, err := ioutil.ReadDir("../scenarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		t.Run(file.Name(), func(t *testing.T) {
+			scn, err := Load(filepath.Join("../scenarios", file.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			factions := scn.Roll(rand.New(rand.NewSource(1)))
+			_, err = battle.NewBattle(scn.Config(), factions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestParseErrors parses invalid scenarios
+// and makes sure the errors are located
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format Format
+		data   string
+		line   int
+		field  string
+	}{
+		{
+			name:   "invalid JSON",
+			format: FormatJSON,
+			data:   "{\n  \"factions\": [\n}",
+			line:   3,
+		},
+		{
+			name:   "empty",
+			format: FormatYAML,
+		},
+		{
+			name:   "invalid duration",
+			format: FormatYAML,
+			data:   "baseActionDelay: soon\n",
+			line:   1,
+			field:  "baseActionDelay",
+		},
+		{
+			name:   "invalid range",
+			format: FormatYAML,
+			data: "factions:\n" +
+				"  - name: A\n" +
+				"    armySize: 1\n" +
+				"    soldierAttributes:\n" +
+				"      healthMin: [50, 10]\n",
+			line:  5,
+			field: "factions[0].soldierAttributes.healthMin",
+		},
+		{
+			name:   "neutral raider",
+			format: FormatYAML,
+			data: "factions:\n" +
+				"  - name: A\n" +
+				"    neutral: true\n" +
+				"    attackNeutrals: true\n",
+			line:  4,
+			field: "factions[0].attackNeutrals",
+		},
+		{
+			name:   "team named after another faction",
+			format: FormatYAML,
+			data: "factions:\n" +
+				"  - name: A\n" +
+				"    armySize: 1\n" +
+				"    soldierAttributes:\n" +
+				"      {healthMin: 10, healthMax: 10,\n" +
+				"       attackStrengthMin: 1, attackStrengthMax: 1}\n" +
+				"  - name: B\n" +
+				"    team: A\n" +
+				"    armySize: 1\n" +
+				"    soldierAttributes:\n" +
+				"      {healthMin: 10, healthMax: 10,\n" +
+				"       attackStrengthMin: 1, attackStrengthMax: 1}\n",
+			line:  8,
+			field: "factions[1].team",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse("test", tc.format, []byte(tc.data))
+			scErr, isScenarioErr := err.(*Error)
+			if !isScenarioErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if scErr.Line != tc.line || scErr.Field != tc.field {
+				t.Fatalf(
+					"line %d in '%s', expected line %d in '%s': %s",
+					scErr.Line,
+					scErr.Field,
+					tc.line,
+					tc.field,
+					err,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package scenario
+
+import (
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// trigger decodes and validates a delayed trigger
+// and returns its delay
+func (d *decoder) trigger(node *yaml.Node, path string) (
+	time.Duration,
+	error,
+) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return 0, err
+	}
+
+	var delayNode *yaml.Node
+	var delay time.Duration
+	for _, f := range fields {
+		switch f.key {
+		case "delay":
+			delayNode = f.value
+			delay, err = d.duration(f.value, f.path)
+			if err == nil && delay <= 0 {
+				err = d.errorf(f.value, f.path, "must be greater 0")
+			}
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if delayNode == nil {
+		return 0, d.errorf(node, path+".delay", "missing")
+	}
+	return delay, nil
+}
@@ -0,0 +1,133 @@
+package scenario
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// Range represents a range of values a soldier attribute is rolled from
+type Range struct {
+	Min float64
+	Max float64
+}
+
+// roll returns a random value within the range
+func (r Range) roll(rnd *rand.Rand) float64 {
+	return r.Min + rnd.Float64()*(r.Max-r.Min)
+}
+
+// attributeNames lists the names of all numeric soldier attributes
+var attributeNames = func() []string {
+	tp := reflect.TypeOf(battle.SoldierAttributes{})
+	names := make([]string, 0, tp.NumField())
+	for i := 0; i < tp.NumField(); i++ {
+		if tp.Field(i).Type.Kind() == reflect.Float64 {
+			names = append(names, tp.Field(i).Name)
+		}
+	}
+	return names
+}()
+
+// attributes creates soldier attributes getting the values from get
+func attributes(get func(name string) float64) battle.SoldierAttributes {
+	attrs := battle.SoldierAttributes{}
+	value := reflect.ValueOf(&attrs).Elem()
+	for _, name := range attributeNames {
+		value.FieldByName(name).SetFloat(get(name))
+	}
+	return attrs
+}
+
+// roll rolls soldier attributes within the given ranges
+func roll(ranges map[string]Range, rnd *rand.Rand) battle.SoldierAttributes {
+	return attributes(func(name string) float64 {
+		return ranges[name].roll(rnd)
+	})
+}
+
+// attributeKey returns the scenario file key of a soldier attribute
+func attributeKey(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// isMin returns true for attributes defining the lower end of a value range
+func isMin(name string) bool { return strings.HasSuffix(name, "Min") }
+
+// isMax returns true for attributes defining the upper end of a value range
+func isMax(name string) bool { return strings.HasSuffix(name, "Max") }
+
+// soldierAttributes decodes the soldier attribute ranges and verifies them
+// using battle.SoldierAttributes.Verify
+func (d *decoder) soldierAttributes(
+	node *yaml.Node,
+	path string,
+) (map[string]Range, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]string, len(attributeNames))
+	for _, name := range attributeNames {
+		keys[attributeKey(name)] = name
+	}
+
+	ranges := make(map[string]Range, len(attributeNames))
+	nodes := make(map[string]*yaml.Node, len(attributeNames))
+	for _, f := range fields {
+		name, known := keys[f.key]
+		if !known {
+			return nil, d.errorf(f.node, f.path, "unknown soldier attribute")
+		}
+		if ranges[name], err = d.valueRange(f.value, f.path); err != nil {
+			return nil, err
+		}
+		nodes[name] = f.value
+	}
+
+	// Verify the worst-case combinations of the ranges: all lower ends,
+	// all upper ends and the upper ends of the minimums combined with
+	// the lower ends of the maximums
+	low := func(name string) float64 { return ranges[name].Min }
+	high := func(name string) float64 { return ranges[name].Max }
+	crossed := func(name string) float64 {
+		if isMin(name) {
+			return ranges[name].Max
+		} else if isMax(name) {
+			return ranges[name].Min
+		}
+		return ranges[name].Min
+	}
+	for _, get := range []func(string) float64{low, high, crossed} {
+		attrs := attributes(get)
+		err := attrs.Verify()
+		if err == nil {
+			continue
+		}
+
+		attrErr, ok := errors.Cause(err).(*battle.AttributeError)
+		if !ok {
+			return nil, d.errorf(node, path, "%s", err)
+		}
+		attrPath := path + "." + attributeKey(attrErr.Attribute)
+		if attrNode, defined := nodes[attrErr.Attribute]; defined {
+			return nil, d.errorf(attrNode, attrPath, "%s", attrErr.Message)
+		}
+		return nil, d.errorf(
+			node,
+			attrPath,
+			"missing (%s)",
+			attrErr.Message,
+		)
+	}
+
+	return ranges, nil
+}
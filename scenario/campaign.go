@@ -0,0 +1,123 @@
+package scenario
+
+import (
+	"github.com/romshark/go-battle-simulator/campaign"
+	"gopkg.in/yaml.v3"
+)
+
+// campaign decodes the campaign settings
+// and returns the maximum number of battles
+func (d *decoder) campaign(node *yaml.Node, path string) (uint, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return 0, err
+	}
+
+	var battles uint
+	for _, f := range fields {
+		switch f.key {
+		case "battles":
+			battles, err = d.uint(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return battles, nil
+}
+
+// replenishment decodes and validates the replenishment of a faction.
+// The recruits are either a number for factions without units
+// or a mapping of unit types to numbers
+func (d *decoder) replenishment(node *yaml.Node, path string) (
+	*campaign.Replenishment,
+	error,
+) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	replenishment := &campaign.Replenishment{}
+	for _, f := range fields {
+		switch f.key {
+		case "recruits":
+			replenishment.Recruits, err = d.recruits(f.value, f.path)
+		case "healing":
+			replenishment.Healing, err = d.percentage(f.value, f.path)
+		case "morale":
+			replenishment.Morale, err = d.percentage(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return replenishment, nil
+}
+
+// recruits decodes the recruits of a replenishment
+func (d *decoder) recruits(node *yaml.Node, path string) (
+	map[string]uint,
+	error,
+) {
+	if node.Kind == yaml.ScalarNode {
+		count, err := d.uint(node, path)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]uint{"": count}, nil
+	}
+
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+	recruits := make(map[string]uint, len(fields))
+	for _, f := range fields {
+		if recruits[f.key], err = d.uint(f.value, f.path); err != nil {
+			return nil, err
+		}
+	}
+	return recruits, nil
+}
+
+// verifyRecruits returns an error if the faction recruits soldiers
+// for unit types neither its army nor its reinforcements define
+func (d *decoder) verifyRecruits(faction Faction, path string) error {
+	types := make(map[string]struct{})
+	addUnits := func(units []Unit) {
+		if len(units) < 1 {
+			types[""] = struct{}{}
+		}
+		for _, unit := range units {
+			types[unit.Type] = struct{}{}
+		}
+	}
+	addUnits(faction.Units)
+	for _, wave := range faction.Reinforcements {
+		addUnits(wave.Units)
+	}
+
+	for unitType := range faction.Replenishment.Recruits {
+		if _, defined := types[unitType]; defined {
+			continue
+		}
+		if unitType == "" {
+			return d.errorf(
+				faction.replenishmentNode,
+				path+".replenishment.recruits",
+				"expected recruits per unit type",
+			)
+		}
+		return d.errorf(
+			faction.replenishmentNode,
+			path+".replenishment.recruits."+unitType,
+			"undefined unit type",
+		)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package scenario
+
+import (
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// Command represents the definition of a faction's command structure
+// (see battle.Command)
+type Command struct {
+	SquadSize uint
+	Order     battle.OrderKind
+
+	// Commander maps the names of the battle.SoldierAttributes fields
+	// to the ranges the commander's attribute values are rolled from.
+	// The faction has no commander if nil
+	Commander map[string]Range
+
+	Aura               battle.Aura
+	DeathMoralePenalty float64
+}
+
+// command decodes and validates the command structure of a faction
+func (d *decoder) command(node *yaml.Node, path string) (Command, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return Command{}, err
+	}
+
+	command := Command{}
+	for _, f := range fields {
+		switch f.key {
+		case "squadSize":
+			command.SquadSize, err = d.uint(f.value, f.path)
+		case "order":
+			command.Order, err = d.order(f.value, f.path)
+		case "commander":
+			command.Commander, err = d.soldierAttributes(f.value, f.path)
+		case "aura":
+			command.Aura, err = d.aura(f.value, f.path)
+		case "deathMoralePenalty":
+			command.DeathMoralePenalty, err = d.percentage(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return Command{}, err
+		}
+	}
+	return command, nil
+}
+
+// order decodes the name of an order given before the battle
+func (d *decoder) order(node *yaml.Node, path string) (
+	battle.OrderKind,
+	error,
+) {
+	name, err := d.string(node, path)
+	if err != nil {
+		return 0, err
+	}
+	for _, kind := range []battle.OrderKind{
+		battle.OrderAdvance,
+		battle.OrderHold,
+	} {
+		if kind.String() == name {
+			return kind, nil
+		}
+	}
+	return 0, d.errorf(
+		node,
+		path,
+		"unknown order: '%s' (expected one of: %s, %s)",
+		name,
+		battle.OrderAdvance,
+		battle.OrderHold,
+	)
+}
+
+// aura decodes the aura of a commander
+func (d *decoder) aura(node *yaml.Node, path string) (battle.Aura, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return battle.Aura{}, err
+	}
+
+	aura := battle.Aura{}
+	for _, f := range fields {
+		switch f.key {
+		case "morale":
+			aura.Morale, err = d.percentage(f.value, f.path)
+		case "hitChance":
+			aura.HitChance, err = d.percentage(f.value, f.path)
+		case "radius":
+			aura.Radius, err = d.float(f.value, f.path)
+			if err == nil && aura.Radius < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return battle.Aura{}, err
+		}
+	}
+	return aura, nil
+}
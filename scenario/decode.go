@@ -0,0 +1,264 @@
+package scenario
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// field represents a key-value pair of a mapping node
+type field struct {
+	key   string
+	path  string
+	node  *yaml.Node
+	value *yaml.Node
+}
+
+// decoder decodes the nodes of a scenario file
+type decoder struct {
+	file string
+}
+
+// errorf creates a new error located at the given node
+func (d *decoder) errorf(
+	node *yaml.Node,
+	path string,
+	format string,
+	args ...interface{},
+) *Error {
+	err := &Error{
+		File:  d.file,
+		Field: path,
+		Err:   errors.Errorf(format, args...),
+	}
+	if node != nil {
+		err.Line = node.Line
+		err.Column = node.Column
+	}
+	return err
+}
+
+// mapping returns the fields of a mapping node
+func (d *decoder) mapping(node *yaml.Node, path string) ([]field, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, d.errorf(node, path, "expected an object")
+	}
+
+	fields := make([]field, 0, len(node.Content)/2)
+	keys := make(map[string]struct{}, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if key.Kind != yaml.ScalarNode {
+			return nil, d.errorf(key, path, "expected a field name")
+		}
+
+		fieldPath := key.Value
+		if path != "" {
+			fieldPath = path + "." + key.Value
+		}
+		if _, duplicate := keys[key.Value]; duplicate {
+			return nil, d.errorf(key, fieldPath, "duplicate field")
+		}
+		keys[key.Value] = struct{}{}
+
+		fields = append(fields, field{
+			key:   key.Value,
+			path:  fieldPath,
+			node:  key,
+			value: node.Content[i+1],
+		})
+	}
+	return fields, nil
+}
+
+// sequence returns the items of a sequence node
+func (d *decoder) sequence(node *yaml.Node, path string) ([]field, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, d.errorf(node, path, "expected a list")
+	}
+
+	items := make([]field, len(node.Content))
+	for i, item := range node.Content {
+		items[i] = field{
+			path:  fmt.Sprintf("%s[%d]", path, i),
+			node:  item,
+			value: item,
+		}
+	}
+	return items, nil
+}
+
+// scalar returns the value of a scalar node
+func (d *decoder) scalar(node *yaml.Node, path string) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", d.errorf(node, path, "expected a value")
+	}
+	return node.Value, nil
+}
+
+// string decodes a string value
+func (d *decoder) string(node *yaml.Node, path string) (string, error) {
+	return d.scalar(node, path)
+}
+
+// bool decodes a boolean
+func (d *decoder) bool(node *yaml.Node, path string) (bool, error) {
+	value, err := d.scalar(node, path)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, d.errorf(node, path, "invalid boolean: '%s'", value)
+	}
+	return b, nil
+}
+
+// float decodes a floating point number
+func (d *decoder) float(node *yaml.Node, path string) (float64, error) {
+	value, err := d.scalar(node, path)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, d.errorf(node, path, "invalid number: '%s'", value)
+	}
+	return number, nil
+}
+
+// int decodes a signed integer
+func (d *decoder) int(node *yaml.Node, path string) (int64, error) {
+	value, err := d.scalar(node, path)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, d.errorf(node, path, "invalid integer: '%s'", value)
+	}
+	return number, nil
+}
+
+// uint decodes an unsigned integer
+func (d *decoder) uint(node *yaml.Node, path string) (uint, error) {
+	value, err := d.scalar(node, path)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0, d.errorf(
+			node,
+			path,
+			"invalid unsigned integer: '%s'",
+			value,
+		)
+	}
+	return uint(number), nil
+}
+
+// percentage decodes a number between 0 and 1
+func (d *decoder) percentage(node *yaml.Node, path string) (float64, error) {
+	number, err := d.float(node, path)
+	if err != nil {
+		return 0, err
+	}
+	if number < 0 || number > 1 {
+		return 0, d.errorf(node, path, "invalid %%: %.2f", number)
+	}
+	return number, nil
+}
+
+// duration decodes a duration such as "1.5s" or "100ms"
+func (d *decoder) duration(node *yaml.Node, path string) (time.Duration, error) {
+	value, err := d.scalar(node, path)
+	if err != nil {
+		return 0, err
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, d.errorf(node, path, "invalid duration: '%s'", value)
+	}
+	return duration, nil
+}
+
+// position decodes an [x, y] position
+func (d *decoder) position(node *yaml.Node, path string) (
+	battle.Position,
+	error,
+) {
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return battle.Position{}, err
+	}
+	if len(items) != 2 {
+		return battle.Position{}, d.errorf(
+			node,
+			path,
+			"expected an [x, y] position, got %d values",
+			len(items),
+		)
+	}
+
+	x, err := d.float(items[0].value, items[0].path)
+	if err != nil {
+		return battle.Position{}, err
+	}
+	y, err := d.float(items[1].value, items[1].path)
+	if err != nil {
+		return battle.Position{}, err
+	}
+	return battle.Position{X: x, Y: y}, nil
+}
+
+// valueRange decodes either a single number or a [min, max] pair of numbers
+func (d *decoder) valueRange(node *yaml.Node, path string) (Range, error) {
+	if node.Kind == yaml.ScalarNode {
+		value, err := d.float(node, path)
+		if err != nil {
+			return Range{}, err
+		}
+		return Range{Min: value, Max: value}, nil
+	}
+
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return Range{}, d.errorf(
+			node,
+			path,
+			"expected either a number or a [min, max] range",
+		)
+	}
+	if len(items) != 2 {
+		return Range{}, d.errorf(
+			node,
+			path,
+			"expected a [min, max] range, got %d values",
+			len(items),
+		)
+	}
+
+	min, err := d.float(items[0].value, items[0].path)
+	if err != nil {
+		return Range{}, err
+	}
+	max, err := d.float(items[1].value, items[1].path)
+	if err != nil {
+		return Range{}, err
+	}
+	if min > max {
+		return Range{}, d.errorf(
+			node,
+			path,
+			"range minimum (%.2f) is greater maximum (%.2f)",
+			min,
+			max,
+		)
+	}
+	return Range{Min: min, Max: max}, nil
+}
@@ -0,0 +1,324 @@
+package scenario
+
+import (
+	"strings"
+
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// loadouts decodes and validates the loadouts of a unit
+func (d *decoder) loadouts(node *yaml.Node, path string) (
+	[]battle.Loadout,
+	error,
+) {
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) < 1 {
+		return nil, d.errorf(node, path, "at least 1 loadout required")
+	}
+
+	loadouts := make([]battle.Loadout, len(items))
+	for i, item := range items {
+		if loadouts[i], err = d.loadout(item.value, item.path); err != nil {
+			return nil, err
+		}
+	}
+	return loadouts, nil
+}
+
+// loadout decodes and validates a loadout.
+// The weight of a loadout defaults to 1
+func (d *decoder) loadout(node *yaml.Node, path string) (
+	battle.Loadout,
+	error,
+) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return battle.Loadout{}, err
+	}
+
+	loadout := battle.Loadout{Weight: 1}
+	for _, f := range fields {
+		switch f.key {
+		case "weight":
+			loadout.Weight, err = d.float(f.value, f.path)
+			if err == nil && loadout.Weight <= 0 {
+				err = d.errorf(f.value, f.path, "must be greater 0")
+			}
+		case "weapon":
+			loadout.Weapon, err = d.weapon(f.value, f.path)
+		case "armor":
+			loadout.Armor, err = d.armor(f.value, f.path)
+		case "shield":
+			loadout.Shield, err = d.shield(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return battle.Loadout{}, err
+		}
+	}
+	return loadout, nil
+}
+
+// weapon decodes and validates a weapon
+func (d *decoder) weapon(node *yaml.Node, path string) (*battle.Weapon, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	weapon := &battle.Weapon{}
+	var damageNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "name":
+			weapon.Name, err = d.string(f.value, f.path)
+		case "damageType":
+			weapon.DamageType, err = d.string(f.value, f.path)
+		case "damage":
+			damageNode = f.value
+			var damage Range
+			if damage, err = d.valueRange(f.value, f.path); err != nil {
+				break
+			}
+			weapon.DamageMin, weapon.DamageMax = damage.Min, damage.Max
+			if weapon.DamageMin < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		case "range":
+			weapon.Range, err = d.float(f.value, f.path)
+			if err == nil && weapon.Range < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		case "attackSpeed":
+			weapon.AttackSpeed, err = d.float(f.value, f.path)
+			if err == nil && weapon.AttackSpeed <= 0 {
+				err = d.errorf(f.value, f.path, "must be greater 0")
+			}
+		case "effects":
+			weapon.Effects, err = d.effects(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if damageNode == nil {
+		return nil, d.errorf(node, path+".damage", "missing")
+	}
+	return weapon, nil
+}
+
+// effects decodes and validates the status effects of a weapon
+func (d *decoder) effects(node *yaml.Node, path string) (
+	[]battle.StatusEffect,
+	error,
+) {
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	effects := make([]battle.StatusEffect, len(items))
+	for i, item := range items {
+		if effects[i], err = d.effect(item.value, item.path); err != nil {
+			return nil, err
+		}
+	}
+	return effects, nil
+}
+
+// effect decodes and validates a status effect
+func (d *decoder) effect(node *yaml.Node, path string) (
+	battle.StatusEffect,
+	error,
+) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return battle.StatusEffect{}, err
+	}
+
+	effect := battle.StatusEffect{}
+	var kindNode, chanceNode, intervalNode, ticksNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "kind":
+			kindNode = f.value
+			effect.Kind, err = d.effectKind(f.value, f.path)
+		case "chance":
+			chanceNode = f.value
+			effect.Chance, err = d.percentage(f.value, f.path)
+		case "interval":
+			intervalNode = f.value
+			effect.Interval, err = d.duration(f.value, f.path)
+			if err == nil && effect.Interval <= 0 {
+				err = d.errorf(f.value, f.path, "must be greater 0")
+			}
+		case "ticks":
+			ticksNode = f.value
+			effect.Ticks, err = d.uint(f.value, f.path)
+			if err == nil && effect.Ticks < 1 {
+				err = d.errorf(f.value, f.path, "must be greater 0")
+			}
+		case "magnitude":
+			effect.Magnitude, err = d.float(f.value, f.path)
+			if err == nil && effect.Magnitude < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return battle.StatusEffect{}, err
+		}
+	}
+
+	for _, required := range []struct {
+		node *yaml.Node
+		key  string
+	}{
+		{kindNode, "kind"},
+		{chanceNode, "chance"},
+		{intervalNode, "interval"},
+		{ticksNode, "ticks"},
+	} {
+		if required.node == nil {
+			return battle.StatusEffect{}, d.errorf(
+				node,
+				path+"."+required.key,
+				"missing",
+			)
+		}
+	}
+	if err := effect.Verify(); err != nil {
+		return battle.StatusEffect{}, d.errorf(node, path, "%s", err)
+	}
+	return effect, nil
+}
+
+// effectKind decodes the name of a status effect kind
+func (d *decoder) effectKind(node *yaml.Node, path string) (
+	battle.EffectKind,
+	error,
+) {
+	name, err := d.string(node, path)
+	if err != nil {
+		return 0, err
+	}
+	kinds := []battle.EffectKind{
+		battle.EffectBleeding,
+		battle.EffectStun,
+		battle.EffectFear,
+	}
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		if kind.String() == name {
+			return kind, nil
+		}
+		names[i] = kind.String()
+	}
+	return 0, d.errorf(
+		node,
+		path,
+		"unknown effect: '%s' (expected one of: %s)",
+		name,
+		strings.Join(names, ", "),
+	)
+}
+
+// armor decodes and validates an armor
+func (d *decoder) armor(node *yaml.Node, path string) (*battle.Armor, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	armor := &battle.Armor{}
+	for _, f := range fields {
+		switch f.key {
+		case "name":
+			armor.Name, err = d.string(f.value, f.path)
+		case "protection":
+			armor.Protection, err = d.protection(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return armor, nil
+}
+
+// protection decodes the protection of an armor per damage type.
+// The "untyped" key defines the protection against untyped damage
+func (d *decoder) protection(node *yaml.Node, path string) (
+	map[string]battle.Protection,
+	error,
+) {
+	damageTypes, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	protection := make(map[string]battle.Protection, len(damageTypes))
+	for _, damageType := range damageTypes {
+		fields, err := d.mapping(damageType.value, damageType.path)
+		if err != nil {
+			return nil, err
+		}
+		p := battle.Protection{}
+		for _, f := range fields {
+			switch f.key {
+			case "flat":
+				p.Flat, err = d.float(f.value, f.path)
+				if err == nil && p.Flat < 0 {
+					err = d.errorf(f.value, f.path, "must not be negative")
+				}
+			case "percentage":
+				p.Percentage, err = d.percentage(f.value, f.path)
+			default:
+				err = d.errorf(f.node, f.path, "unknown field")
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		key := damageType.key
+		if key == "untyped" {
+			key = ""
+		}
+		protection[key] = p
+	}
+	return protection, nil
+}
+
+// shield decodes and validates a shield
+func (d *decoder) shield(node *yaml.Node, path string) (*battle.Shield, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	shield := &battle.Shield{}
+	for _, f := range fields {
+		switch f.key {
+		case "name":
+			shield.Name, err = d.string(f.value, f.path)
+		case "blockChance":
+			shield.BlockChance, err = d.percentage(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return shield, nil
+}
@@ -0,0 +1,247 @@
+package scenario
+
+import (
+	"strings"
+	"time"
+
+	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/campaign"
+	"gopkg.in/yaml.v3"
+)
+
+// Faction represents a faction definition
+type Faction struct {
+	Name     string
+	ArmySize uint
+
+	// SoldierAttributes maps the names of the battle.SoldierAttributes fields
+	// to the ranges the attribute values are rolled from
+	SoldierAttributes map[string]Range
+	Loadouts          []battle.Loadout
+	Veterans          []battle.Veteran
+
+	// Units defines the unit groups of the faction
+	// replacing ArmySize and SoldierAttributes (if any)
+	Units []Unit
+
+	// Deployment is the deployment zone on the field (if any)
+	Deployment battle.Zone
+
+	// Targeting is the name of the built-in targeting strategy (if any)
+	Targeting string
+
+	// Command defines the squads and the commander of the faction
+	Command Command
+
+	// Reinforcements defines the reinforcement waves of the faction
+	Reinforcements []Wave
+
+	Team           string
+	Neutral        bool
+	AttackNeutrals bool
+
+	// BreakAlliance is the delay after which the faction leaves its team.
+	// The faction never leaves its team if zero
+	BreakAlliance time.Duration
+
+	// Replenishment defines the replenishment of the faction
+	// between the battles of a campaign (if any)
+	Replenishment *campaign.Replenishment
+
+	deploymentNode    *yaml.Node
+	replenishmentNode *yaml.Node
+}
+
+// factions decodes and validates the list of factions
+func (d *decoder) factions(node *yaml.Node, path string) ([]Faction, error) {
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(items))
+	factions := make([]Faction, len(items))
+	for i, item := range items {
+		faction, err := d.faction(item.value, item.path)
+		if err != nil {
+			return nil, err
+		}
+		if _, duplicate := names[faction.Name]; duplicate {
+			return nil, d.errorf(
+				item.value,
+				item.path+".name",
+				"duplicate faction name: '%s'",
+				faction.Name,
+			)
+		}
+		names[faction.Name] = struct{}{}
+		factions[i] = faction
+	}
+
+	// Factions without a team form a team named after themselves
+	for i, item := range items {
+		team := factions[i].Team
+		if _, clash := names[team]; !clash || team == factions[i].Name {
+			continue
+		}
+		fields, err := d.mapping(item.value, item.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			if f.key == "team" {
+				return nil, d.errorf(
+					f.value,
+					f.path,
+					"named after another faction: '%s'",
+					team,
+				)
+			}
+		}
+	}
+	return factions, nil
+}
+
+// faction decodes and validates a faction
+func (d *decoder) faction(node *yaml.Node, path string) (Faction, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return Faction{}, err
+	}
+
+	faction := Faction{}
+	var nameNode, armySizeNode, unitsNode, attackNeutralsNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "name":
+			nameNode = f.value
+			faction.Name, err = d.string(f.value, f.path)
+		case "armySize":
+			armySizeNode = f.value
+			faction.ArmySize, err = d.uint(f.value, f.path)
+		case "soldierAttributes":
+			faction.SoldierAttributes, err = d.soldierAttributes(
+				f.value,
+				f.path,
+			)
+		case "loadouts":
+			faction.Loadouts, err = d.loadouts(f.value, f.path)
+		case "veterans":
+			faction.Veterans, err = d.veterans(f.value, f.path)
+		case "command":
+			faction.Command, err = d.command(f.value, f.path)
+		case "reinforcements":
+			faction.Reinforcements, err = d.waves(f.value, f.path)
+		case "team":
+			faction.Team, err = d.string(f.value, f.path)
+		case "neutral":
+			faction.Neutral, err = d.bool(f.value, f.path)
+		case "attackNeutrals":
+			attackNeutralsNode = f.value
+			faction.AttackNeutrals, err = d.bool(f.value, f.path)
+		case "breakAlliance":
+			faction.BreakAlliance, err = d.trigger(f.value, f.path)
+		case "replenishment":
+			faction.replenishmentNode = f.value
+			faction.Replenishment, err = d.replenishment(f.value, f.path)
+		case "units":
+			unitsNode = f.value
+			faction.Units, err = d.units(f.value, f.path)
+		case "deployment":
+			faction.deploymentNode = f.value
+			faction.Deployment, err = d.zone(f.value, f.path)
+		case "targeting":
+			if faction.Targeting, err = d.string(f.value, f.path); err != nil {
+				break
+			}
+			if _, err = battle.NewTargetingStrategy(faction.Targeting); err != nil {
+				err = d.errorf(
+					f.value,
+					f.path,
+					"%s (expected one of: %s)",
+					err,
+					strings.Join(battle.TargetingStrategyNames(), ", "),
+				)
+			}
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return Faction{}, err
+		}
+	}
+
+	if nameNode == nil {
+		return Faction{}, d.errorf(node, path+".name", "missing")
+	}
+	if faction.Name == "" {
+		return Faction{}, d.errorf(nameNode, path+".name", "empty")
+	}
+	if faction.Neutral && faction.AttackNeutrals {
+		return Faction{}, d.errorf(
+			attackNeutralsNode,
+			path+".attackNeutrals",
+			"must not be defined for neutral factions",
+		)
+	}
+	if faction.Replenishment != nil {
+		if err := d.verifyRecruits(faction, path); err != nil {
+			return Faction{}, err
+		}
+	}
+	if unitsNode != nil {
+		if armySizeNode != nil {
+			return Faction{}, d.errorf(
+				armySizeNode,
+				path+".armySize",
+				"must not be defined alongside units",
+			)
+		}
+		if faction.SoldierAttributes != nil {
+			return Faction{}, d.errorf(
+				node,
+				path+".soldierAttributes",
+				"must not be defined alongside units",
+			)
+		}
+		if faction.Loadouts != nil {
+			return Faction{}, d.errorf(
+				node,
+				path+".loadouts",
+				"must not be defined alongside units",
+			)
+		}
+		if faction.Veterans != nil {
+			return Faction{}, d.errorf(
+				node,
+				path+".veterans",
+				"must not be defined alongside units",
+			)
+		}
+		return faction, nil
+	}
+	if armySizeNode == nil {
+		return Faction{}, d.errorf(node, path+".armySize", "missing")
+	}
+	if faction.ArmySize < 1 {
+		return Faction{}, d.errorf(
+			armySizeNode,
+			path+".armySize",
+			"must be greater 0",
+		)
+	}
+	if faction.SoldierAttributes == nil {
+		return Faction{}, d.errorf(node, path+".soldierAttributes", "missing")
+	}
+	if uint(len(faction.Veterans)) > faction.ArmySize {
+		return Faction{}, d.errorf(
+			node,
+			path+".veterans",
+			"more veterans (%d) than soldiers (%d)",
+			len(faction.Veterans),
+			faction.ArmySize,
+		)
+	}
+
+	return faction, nil
+}
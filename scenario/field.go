@@ -0,0 +1,83 @@
+package scenario
+
+import (
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// field decodes the field dimensions
+func (d *decoder) field(node *yaml.Node, path string) (*battle.Field, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	field := &battle.Field{}
+	for _, f := range fields {
+		switch f.key {
+		case "width":
+			field.Width, err = d.float(f.value, f.path)
+		case "height":
+			field.Height, err = d.float(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if field.Width <= 0 || field.Height <= 0 {
+		return nil, d.errorf(
+			node,
+			path,
+			"invalid dimensions: %.1fx%.1f",
+			field.Width,
+			field.Height,
+		)
+	}
+	return field, nil
+}
+
+// zone decodes a zone defined by its min and max corners
+func (d *decoder) zone(node *yaml.Node, path string) (battle.Zone, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return battle.Zone{}, err
+	}
+
+	zone := battle.Zone{}
+	var minNode, maxNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "min":
+			minNode = f.value
+			zone.Min, err = d.position(f.value, f.path)
+		case "max":
+			maxNode = f.value
+			zone.Max, err = d.position(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return battle.Zone{}, err
+		}
+	}
+
+	if minNode == nil {
+		return battle.Zone{}, d.errorf(node, path+".min", "missing")
+	}
+	if maxNode == nil {
+		return battle.Zone{}, d.errorf(node, path+".max", "missing")
+	}
+	if zone.Min.X > zone.Max.X || zone.Min.Y > zone.Max.Y {
+		return battle.Zone{}, d.errorf(
+			node,
+			path,
+			"min %s exceeds max %s",
+			zone.Min,
+			zone.Max,
+		)
+	}
+	return zone, nil
+}
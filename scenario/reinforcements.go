@@ -0,0 +1,123 @@
+package scenario
+
+import (
+	"time"
+
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// Wave represents a reinforcement wave definition (see battle.Wave)
+type Wave struct {
+	Size uint
+
+	// SoldierAttributes maps the names of the battle.SoldierAttributes fields
+	// to the ranges the attribute values are rolled from
+	SoldierAttributes map[string]Range
+	Loadouts          []battle.Loadout
+
+	// Units defines the unit groups of the wave
+	// replacing Size and SoldierAttributes (if any)
+	Units []Unit
+
+	Delay         time.Duration
+	ArmyThreshold float64
+}
+
+// waves decodes and validates the reinforcement waves of a faction
+func (d *decoder) waves(node *yaml.Node, path string) ([]Wave, error) {
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	waves := make([]Wave, len(items))
+	for i, item := range items {
+		if waves[i], err = d.wave(item.value, item.path); err != nil {
+			return nil, err
+		}
+	}
+	return waves, nil
+}
+
+// wave decodes and validates a reinforcement wave
+func (d *decoder) wave(node *yaml.Node, path string) (Wave, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return Wave{}, err
+	}
+
+	wave := Wave{}
+	var sizeNode, unitsNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "size":
+			sizeNode = f.value
+			wave.Size, err = d.uint(f.value, f.path)
+		case "soldierAttributes":
+			wave.SoldierAttributes, err = d.soldierAttributes(
+				f.value,
+				f.path,
+			)
+		case "loadouts":
+			wave.Loadouts, err = d.loadouts(f.value, f.path)
+		case "units":
+			unitsNode = f.value
+			wave.Units, err = d.units(f.value, f.path)
+		case "delay":
+			wave.Delay, err = d.duration(f.value, f.path)
+			if err == nil && wave.Delay <= 0 {
+				err = d.errorf(f.value, f.path, "must be greater 0")
+			}
+		case "armyThreshold":
+			wave.ArmyThreshold, err = d.percentage(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return Wave{}, err
+		}
+	}
+
+	if wave.Delay == 0 && wave.ArmyThreshold == 0 {
+		return Wave{}, d.errorf(
+			node,
+			path,
+			"either a delay or an army threshold required",
+		)
+	}
+	if unitsNode != nil {
+		if sizeNode != nil {
+			return Wave{}, d.errorf(
+				sizeNode,
+				path+".size",
+				"must not be defined alongside units",
+			)
+		}
+		if wave.SoldierAttributes != nil {
+			return Wave{}, d.errorf(
+				node,
+				path+".soldierAttributes",
+				"must not be defined alongside units",
+			)
+		}
+		if wave.Loadouts != nil {
+			return Wave{}, d.errorf(
+				node,
+				path+".loadouts",
+				"must not be defined alongside units",
+			)
+		}
+		return wave, nil
+	}
+	if sizeNode == nil {
+		return Wave{}, d.errorf(node, path+".size", "missing")
+	}
+	if wave.Size < 1 {
+		return Wave{}, d.errorf(sizeNode, path+".size", "must be greater 0")
+	}
+	if wave.SoldierAttributes == nil {
+		return Wave{}, d.errorf(node, path+".soldierAttributes", "missing")
+	}
+	return wave, nil
+}
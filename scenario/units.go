@@ -0,0 +1,198 @@
+package scenario
+
+import (
+	"math/rand"
+
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// Unit represents a unit group definition
+type Unit struct {
+	Type  string
+	Count uint
+
+	// SoldierAttributes maps the names of the battle.SoldierAttributes fields
+	// to the ranges the attribute values are rolled from
+	SoldierAttributes map[string]Range
+
+	Loadouts []battle.Loadout
+	Veterans []battle.Veteran
+}
+
+// rollUnits rolls the soldier attributes of the given units
+func rollUnits(units []Unit, rnd *rand.Rand) []battle.Unit {
+	if len(units) < 1 {
+		return nil
+	}
+	rolled := make([]battle.Unit, len(units))
+	for i, unit := range units {
+		rolled[i] = battle.Unit{
+			Type:              unit.Type,
+			Count:             unit.Count,
+			SoldierAttributes: roll(unit.SoldierAttributes, rnd),
+			Loadouts:          unit.Loadouts,
+			Veterans:          unit.Veterans,
+		}
+	}
+	return rolled
+}
+
+// units decodes and validates the unit groups of a faction
+func (d *decoder) units(node *yaml.Node, path string) ([]Unit, error) {
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) < 1 {
+		return nil, d.errorf(node, path, "at least 1 unit required")
+	}
+
+	types := make(map[string]struct{}, len(items))
+	units := make([]Unit, len(items))
+	for i, item := range items {
+		unit, err := d.unit(item.value, item.path)
+		if err != nil {
+			return nil, err
+		}
+		if _, duplicate := types[unit.Type]; duplicate {
+			return nil, d.errorf(
+				item.value,
+				item.path+".type",
+				"duplicate unit type: '%s'",
+				unit.Type,
+			)
+		}
+		types[unit.Type] = struct{}{}
+		units[i] = unit
+	}
+	return units, nil
+}
+
+// unit decodes and validates a unit group
+func (d *decoder) unit(node *yaml.Node, path string) (Unit, error) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return Unit{}, err
+	}
+
+	unit := Unit{}
+	var typeNode, countNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "type":
+			typeNode = f.value
+			unit.Type, err = d.string(f.value, f.path)
+		case "count":
+			countNode = f.value
+			unit.Count, err = d.uint(f.value, f.path)
+		case "soldierAttributes":
+			unit.SoldierAttributes, err = d.soldierAttributes(
+				f.value,
+				f.path,
+			)
+		case "loadouts":
+			unit.Loadouts, err = d.loadouts(f.value, f.path)
+		case "veterans":
+			unit.Veterans, err = d.veterans(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return Unit{}, err
+		}
+	}
+
+	if typeNode == nil {
+		return Unit{}, d.errorf(node, path+".type", "missing")
+	}
+	if unit.Type == "" {
+		return Unit{}, d.errorf(typeNode, path+".type", "empty")
+	}
+	if countNode == nil {
+		return Unit{}, d.errorf(node, path+".count", "missing")
+	}
+	if unit.Count < 1 {
+		return Unit{}, d.errorf(countNode, path+".count", "must be greater 0")
+	}
+	if unit.SoldierAttributes == nil {
+		return Unit{}, d.errorf(node, path+".soldierAttributes", "missing")
+	}
+	if uint(len(unit.Veterans)) > unit.Count {
+		return Unit{}, d.errorf(
+			node,
+			path+".veterans",
+			"more veterans (%d) than soldiers (%d)",
+			len(unit.Veterans),
+			unit.Count,
+		)
+	}
+
+	return unit, nil
+}
+
+// damageModifiers decodes the damage multipliers between unit types
+func (d *decoder) damageModifiers(
+	node *yaml.Node,
+	path string,
+) (battle.DamageMatrix, error) {
+	attackers, err := d.mapping(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := make(battle.DamageMatrix, len(attackers))
+	for _, attacker := range attackers {
+		defenders, err := d.mapping(attacker.value, attacker.path)
+		if err != nil {
+			return nil, err
+		}
+		multipliers := make(map[string]float64, len(defenders))
+		for _, defender := range defenders {
+			multiplier, err := d.float(defender.value, defender.path)
+			if err != nil {
+				return nil, err
+			}
+			if multiplier < 0 {
+				return nil, d.errorf(
+					defender.value,
+					defender.path,
+					"must not be negative",
+				)
+			}
+			multipliers[defender.key] = multiplier
+		}
+		matrix[attacker.key] = multipliers
+	}
+	return matrix, nil
+}
+
+// verifyUnitTypes verifies the damage modifiers only refer
+// to unit types defined by the factions
+func (d *decoder) verifyUnitTypes(s *Scenario, node *yaml.Node) error {
+	types := make(map[string]struct{})
+	for _, faction := range s.Factions {
+		for _, unit := range faction.Units {
+			types[unit.Type] = struct{}{}
+		}
+	}
+
+	// The node tree was validated during decoding
+	attackers, _ := d.mapping(node, "damageModifiers")
+	for _, attacker := range attackers {
+		if _, defined := types[attacker.key]; !defined {
+			return d.errorf(attacker.node, attacker.path, "unknown unit type")
+		}
+		defenders, _ := d.mapping(attacker.value, attacker.path)
+		for _, defender := range defenders {
+			if _, defined := types[defender.key]; !defined {
+				return d.errorf(
+					defender.node,
+					defender.path,
+					"unknown unit type",
+				)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package scenario
+
+import (
+	"github.com/romshark/go-battle-simulator/battle"
+	"gopkg.in/yaml.v3"
+)
+
+// veterans decodes and validates the veterans of a unit
+func (d *decoder) veterans(node *yaml.Node, path string) (
+	[]battle.Veteran,
+	error,
+) {
+	items, err := d.sequence(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(items))
+	veterans := make([]battle.Veteran, len(items))
+	for i, item := range items {
+		if veterans[i], err = d.veteran(item.value, item.path); err != nil {
+			return nil, err
+		}
+		if _, duplicate := names[veterans[i].Name]; duplicate {
+			return nil, d.errorf(
+				item.value,
+				item.path+".name",
+				"duplicate veteran name: '%s'",
+				veterans[i].Name,
+			)
+		}
+		names[veterans[i].Name] = struct{}{}
+	}
+	return veterans, nil
+}
+
+// veteran decodes and validates a veteran
+func (d *decoder) veteran(node *yaml.Node, path string) (
+	battle.Veteran,
+	error,
+) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return battle.Veteran{}, err
+	}
+
+	veteran := battle.Veteran{}
+	var nameNode *yaml.Node
+	for _, f := range fields {
+		switch f.key {
+		case "name":
+			nameNode = f.value
+			veteran.Name, err = d.string(f.value, f.path)
+		case "experience":
+			veteran.Experience, err = d.float(f.value, f.path)
+			if err == nil && veteran.Experience < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return battle.Veteran{}, err
+		}
+	}
+
+	if nameNode == nil {
+		return battle.Veteran{}, d.errorf(node, path+".name", "missing")
+	}
+	if veteran.Name == "" {
+		return battle.Veteran{}, d.errorf(nameNode, path+".name", "empty")
+	}
+	return veteran, nil
+}
+
+// veterancy decodes and validates the experience rules
+func (d *decoder) veterancy(node *yaml.Node, path string) (
+	battle.Veterancy,
+	error,
+) {
+	fields, err := d.mapping(node, path)
+	if err != nil {
+		return battle.Veterancy{}, err
+	}
+
+	veterancy := battle.Veterancy{}
+	for _, f := range fields {
+		switch f.key {
+		case "killExperience":
+			veterancy.KillExperience, err = d.float(f.value, f.path)
+			if err == nil && veterancy.KillExperience < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		case "damageExperience":
+			veterancy.DamageExperience, err = d.float(f.value, f.path)
+			if err == nil && veterancy.DamageExperience < 0 {
+				err = d.errorf(f.value, f.path, "must not be negative")
+			}
+		case "levelExperience":
+			veterancy.LevelExperience, err = d.float(f.value, f.path)
+			if err == nil && veterancy.LevelExperience <= 0 {
+				err = d.errorf(f.value, f.path, "must be greater 0")
+			}
+		case "maxLevel":
+			veterancy.MaxLevel, err = d.uint(f.value, f.path)
+		case "hitChanceBonus":
+			veterancy.HitChanceBonus, err = d.percentage(f.value, f.path)
+		case "dodgeChanceBonus":
+			veterancy.DodgeChanceBonus, err = d.percentage(f.value, f.path)
+		case "moraleResilience":
+			veterancy.MoraleResilience, err = d.percentage(f.value, f.path)
+		default:
+			err = d.errorf(f.node, f.path, "unknown field")
+		}
+		if err != nil {
+			return battle.Veterancy{}, err
+		}
+	}
+	return veterancy, nil
+}
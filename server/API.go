@@ -0,0 +1,152 @@
+package server
+
+import (
+	"time"
+
+	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/scenario"
+)
+
+// CreateBattle represents a request creating a new battle
+type CreateBattle struct {
+	// Scenario is the name of a scenario file
+	// in the scenario directory of the server (see Config.ScenarioDir)
+	Scenario string
+
+	// Definition defines the scenario inline in the given format
+	// instead, the format defaults to YAML
+	Definition string
+	Format     scenario.Format
+
+	// Seed overrides the seed of the scenario unless zero.
+	// Battles are seeded randomly if neither defines a seed
+	Seed int64
+
+	// Virtual makes the battle run in virtual time as fast as possible
+	Virtual bool
+
+	// Start starts the battle right away
+	Start bool
+}
+
+// State represents the state of a hosted battle
+type State string
+
+const (
+	// StateCreated is the state of a battle that wasn't started yet
+	StateCreated State = "created"
+
+	// StateRunning is the state of a running battle
+	StateRunning State = "running"
+
+	// StateFinished is the state of a battle that is over
+	// including canceled battles
+	StateFinished State = "finished"
+)
+
+// BattleInfo represents the description of a hosted battle
+type BattleInfo struct {
+	ID       string
+	Scenario string
+
+	// Seed is the seed of the battle, battles are seeded randomly
+	// unless the scenario or the request defines a seed
+	Seed int64
+
+	State    State
+	Created  time.Time
+	Factions []string
+	Paused   bool
+	Events   int
+
+	// Result represents the result of a finished battle
+	Result *Result `json:",omitempty"`
+}
+
+// Result represents the result of a finished battle (see battle.Result)
+type Result struct {
+	// Outcome is either "victory", "draw", "timeout" or "canceled"
+	Outcome    string
+	Winners    []string
+	WinnerTeam string
+	Survivors  map[string][]battle.SoldierID
+	Routed     map[string][]battle.SoldierID
+	Fled       map[string][]battle.SoldierID
+	Deployed   map[string]int
+	Duration   time.Duration
+}
+
+// newResult converts the result of a battle
+func newResult(result battle.Result) *Result {
+	ids := func(soldiers map[string][]battle.Soldier) map[string][]battle.SoldierID {
+		m := make(map[string][]battle.SoldierID, len(soldiers))
+		for faction, list := range soldiers {
+			m[faction] = make([]battle.SoldierID, len(list))
+			for i, soldier := range list {
+				m[faction][i] = soldier.ID()
+			}
+		}
+		return m
+	}
+	return &Result{
+		Outcome:    result.Outcome.String(),
+		Winners:    result.Winners,
+		WinnerTeam: result.WinnerTeam,
+		Survivors:  ids(result.Survivors),
+		Routed:     ids(result.Routed),
+		Fled:       ids(result.Fled),
+		Deployed:   result.Deployed,
+		Duration:   result.Duration,
+	}
+}
+
+// Soldier represents the current state of a soldier
+type Soldier struct {
+	ID        battle.SoldierID
+	Commander bool
+	Alive     bool
+
+	// Squad is the index of the soldier's squad within its faction
+	Squad *int `json:",omitempty"`
+
+	Status    battle.SoldierStatus
+	Stats     battle.SoldierStatistics
+	Equipment battle.Equipment
+
+	// Position represents the position of the soldier
+	// on a spatial battlefield
+	Position *battle.Position `json:",omitempty"`
+}
+
+// Statistics represents the current statistics of a battle
+type Statistics struct {
+	Events        int
+	WinnerFaction string `json:",omitempty"`
+	Factions      []FactionStatistics
+}
+
+// FactionStatistics represents the current statistics of a faction
+type FactionStatistics struct {
+	Name string
+	Team string
+
+	// Deployed is the number of soldiers deployed including reinforcements.
+	// Fighting, Routed, Fled and Dead break them down by their status
+	Deployed int
+	Fighting int
+	Routed   int
+	Fled     int
+	Dead     int
+
+	// Kills, DamageCaused, DamageTaken and HealingDone
+	// sum up the statistics of the faction's soldiers
+	Kills        uint
+	DamageCaused float64
+	DamageTaken  float64
+	HealingDone  float64
+}
+
+// Error represents the body of an error response
+type Error struct {
+	Error string
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+)
+
+// stopTimeout limits the time waited for a canceled battle to finish
+const stopTimeout = 5 * time.Second
+
+// errStopTimeout is returned when a canceled battle doesn't finish in time
+var errStopTimeout = errors.New("the battle didn't stop in time")
+
+// hostedBattle represents a battle hosted by the server
+type hostedBattle struct {
+	lock     *sync.Mutex
+	seq      uint64
+	id       string
+	scenario string
+	seed     int64
+	created  time.Time
+	factions []string
+	battle   *battle.Battle
+	state    State
+	cancel   context.CancelFunc
+	result   *Result
+
+	// done is closed once the battle is finished,
+	// removed once the battle is removed from the server
+	done    chan struct{}
+	removed chan struct{}
+}
+
+// newHostedBattle creates a new hosted battle
+func newHostedBattle(
+	seq uint64,
+	id string,
+	scenario string,
+	seed int64,
+	btl *battle.Battle,
+	factions []battle.Faction,
+) *hostedBattle {
+	hb := &hostedBattle{
+		lock:     &sync.Mutex{},
+		seq:      seq,
+		id:       id,
+		scenario: scenario,
+		seed:     seed,
+		created:  time.Now(),
+		factions: make([]string, len(factions)),
+		battle:   btl,
+		state:    StateCreated,
+		done:     make(chan struct{}),
+		removed:  make(chan struct{}),
+	}
+	for i, faction := range factions {
+		hb.factions[i] = faction.Name
+	}
+	return hb
+}
+
+// info returns the description of the battle
+func (hb *hostedBattle) info() BattleInfo {
+	hb.lock.Lock()
+	defer hb.lock.Unlock()
+	return BattleInfo{
+		ID:       hb.id,
+		Scenario: hb.scenario,
+		Seed:     hb.seed,
+		State:    hb.state,
+		Created:  hb.created,
+		Factions: hb.factions,
+		Paused:   hb.battle.Paused(),
+		Events:   len(hb.battle.Statistics().Log()),
+		Result:   hb.result,
+	}
+}
+
+// start runs the battle in the background.
+// A non-zero timeout limits the duration of the battle
+func (hb *hostedBattle) start(timeout time.Duration) error {
+	hb.lock.Lock()
+	defer hb.lock.Unlock()
+
+	if hb.state != StateCreated {
+		return errors.Errorf("the battle is already %s", hb.state)
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	hb.state = StateRunning
+	hb.cancel = cancel
+
+	go func() {
+		defer cancel()
+		result := hb.battle.Run(ctx)
+
+		hb.lock.Lock()
+		hb.state = StateFinished
+		hb.result = newResult(result)
+		hb.lock.Unlock()
+		close(hb.done)
+	}()
+	return nil
+}
+
+// stop cancels the running battle and waits for it to finish.
+// Returns errStopTimeout if it doesn't finish in time
+func (hb *hostedBattle) stop() error {
+	hb.lock.Lock()
+	state, cancel := hb.state, hb.cancel
+	hb.lock.Unlock()
+
+	if state != StateRunning {
+		return errors.Errorf("the battle is %s", state)
+	}
+	cancel()
+
+	timer := time.NewTimer(stopTimeout)
+	defer timer.Stop()
+	select {
+	case <-hb.done:
+		return nil
+	case <-timer.C:
+		return errStopTimeout
+	}
+}
+
+// running returns an error unless the battle is running
+func (hb *hostedBattle) running() error {
+	hb.lock.Lock()
+	defer hb.lock.Unlock()
+	if hb.state != StateRunning {
+		return errors.Errorf("the battle is %s", hb.state)
+	}
+	return nil
+}
+
+// soldiers returns the current state of the soldiers of the given faction
+// or of all factions if the faction name is empty
+func (hb *hostedBattle) soldiers(factionName string) ([]Soldier, error) {
+	factions := hb.factions
+	if factionName != "" {
+		factions = nil
+		for _, name := range hb.factions {
+			if name == factionName {
+				factions = []string{name}
+			}
+		}
+		if factions == nil {
+			return nil, errors.Errorf("unknown faction: '%s'", factionName)
+		}
+	}
+
+	field, isSpatial := hb.battle.Battlefield().(battle.SpatialBattlefield)
+	var soldiers []Soldier
+	for _, name := range factions {
+		for _, s := range hb.battle.Soldiers(name) {
+			soldier := Soldier{
+				ID:        s.ID(),
+				Commander: s.IsCommander(),
+				Alive:     s.IsAlive(),
+				Status:    s.Status(),
+				Stats:     s.Stats(),
+				Equipment: s.Equipment(),
+			}
+			if squad := s.Squad(); squad != nil {
+				index := squad.Index()
+				soldier.Squad = &index
+			}
+			if isSpatial {
+				if position, err := field.Position(s.ID()); err == nil {
+					soldier.Position = &position
+				}
+			}
+			soldiers = append(soldiers, soldier)
+		}
+	}
+	return soldiers, nil
+}
+
+// statistics returns the current statistics of the battle
+func (hb *hostedBattle) statistics() Statistics {
+	stats := hb.battle.Statistics()
+	statistics := Statistics{
+		Events:        len(stats.Log()),
+		WinnerFaction: stats.WinnerFaction(),
+		Factions:      make([]FactionStatistics, len(hb.factions)),
+	}
+	for i, name := range hb.factions {
+		faction := FactionStatistics{Name: name, Team: hb.battle.Team(name)}
+		for _, s := range hb.battle.Soldiers(name) {
+			status, soldierStats := s.Status(), s.Stats()
+			faction.Deployed++
+			switch {
+			case status.Health <= 0:
+				faction.Dead++
+			case status.Fled:
+				faction.Fled++
+			case status.Routed:
+				faction.Routed++
+			default:
+				faction.Fighting++
+			}
+			faction.Kills += soldierStats.Kills
+			faction.DamageCaused += soldierStats.DamageCaused
+			faction.DamageTaken += soldierStats.DamageTaken
+			faction.HealingDone += soldierStats.HealingDone
+		}
+		statistics.Factions[i] = faction
+	}
+	return statistics
+}
@@ -0,0 +1,321 @@
+// Package server implements an HTTP API for creating, running
+// and observing battles. Every battle is backed by its own battle.Battle
+// and runs concurrently with the other battles of the server
+package server
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/scenario"
+)
+
+// MaxRequestSize limits the size of request bodies in bytes
+const MaxRequestSize = 1 << 20
+
+// Config represents the configuration of a server
+type Config struct {
+	// ScenarioDir is the directory the scenario files referenced
+	// by name are loaded from. Only inline scenarios can be used if empty
+	ScenarioDir string
+
+	// Timeout limits the duration of every battle. Zero means no limit
+	Timeout time.Duration
+}
+
+// Server serves the battle API:
+//
+//	GET    /battles                     lists all battles
+//	POST   /battles                     creates a battle (see CreateBattle)
+//	GET    /battles/{id}                describes a battle
+//	DELETE /battles/{id}                cancels and removes a battle
+//	POST   /battles/{id}/start          starts a battle
+//	POST   /battles/{id}/cancel         cancels a running battle
+//	POST   /battles/{id}/pause          pauses a running battle
+//	POST   /battles/{id}/resume         resumes a paused battle
+//	GET    /battles/{id}/soldiers       lists the soldiers (?faction=name)
+//	GET    /battles/{id}/statistics     returns the statistics
+//	GET    /battles/{id}/events         streams the battle log (see streamEvents)
+type Server struct {
+	lock    *sync.Mutex
+	config  Config
+	seq     uint64
+	battles map[string]*hostedBattle
+}
+
+// New creates a new server instance
+func New(config Config) *Server {
+	return &Server{
+		lock:    &sync.Mutex{},
+		config:  config,
+		battles: make(map[string]*hostedBattle),
+	}
+}
+
+// Close cancels all running battles and waits for them to finish
+func (s *Server) Close() {
+	s.lock.Lock()
+	battles := make([]*hostedBattle, 0, len(s.battles))
+	for _, hb := range s.battles {
+		battles = append(battles, hb)
+	}
+	s.lock.Unlock()
+
+	for _, hb := range battles {
+		// Battles that aren't running can't be canceled
+		_ = hb.stop()
+	}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if path[0] != "battles" || len(path) > 3 {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.list())
+		case http.MethodPost:
+			s.create(w, r)
+		default:
+			methodNotAllowed(w, http.MethodGet, http.MethodPost)
+		}
+		return
+	}
+
+	hb := s.battle(path[1])
+	if hb == nil {
+		writeError(
+			w,
+			http.StatusNotFound,
+			errors.Errorf("unknown battle: '%s'", path[1]),
+		)
+		return
+	}
+
+	if len(path) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, hb.info())
+		case http.MethodDelete:
+			s.remove(hb)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+		}
+		return
+	}
+
+	switch path[2] {
+	case "start", "cancel", "pause", "resume":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		if err := s.control(hb, path[2]); err != nil {
+			status := http.StatusConflict
+			if err == errStopTimeout {
+				status = http.StatusInternalServerError
+			}
+			writeError(w, status, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, hb.info())
+	case "soldiers", "statistics", "events":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		switch path[2] {
+		case "soldiers":
+			soldiers, err := hb.soldiers(r.URL.Query().Get("faction"))
+			if err != nil {
+				writeError(w, http.StatusNotFound, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, soldiers)
+		case "statistics":
+			writeJSON(w, http.StatusOK, hb.statistics())
+		case "events":
+			streamEvents(w, r, hb)
+		}
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// battle returns the battle of the given ID or nil if there's none
+func (s *Server) battle(id string) *hostedBattle {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.battles[id]
+}
+
+// list returns the descriptions of all battles in the order of creation
+func (s *Server) list() []BattleInfo {
+	s.lock.Lock()
+	battles := make([]*hostedBattle, 0, len(s.battles))
+	for _, hb := range s.battles {
+		battles = append(battles, hb)
+	}
+	s.lock.Unlock()
+
+	sort.Slice(battles, func(i, j int) bool {
+		return battles[i].seq < battles[j].seq
+	})
+	infos := make([]BattleInfo, len(battles))
+	for i, hb := range battles {
+		infos[i] = hb.info()
+	}
+	return infos
+}
+
+// create creates a new battle from the scenario of the request
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req CreateBattle
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	if err := decoder.Decode(&req); err != nil {
+		writeError(
+			w,
+			http.StatusBadRequest,
+			errors.Wrap(err, "decoding request"),
+		)
+		return
+	}
+
+	scn, err := s.loadScenario(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	config := scn.Config()
+	if req.Seed != 0 {
+		config.Seed = req.Seed
+	}
+	if config.Seed == 0 {
+		// Hosted battles are always seeded to make them reproducible
+		// and have their soldiers act sequentially
+		config.Seed = time.Now().UnixNano()
+	}
+	if req.Virtual {
+		config.Clock = battle.NewVirtualClock(time.Now())
+	}
+	factions := scn.Roll(rand.New(rand.NewSource(config.Seed)))
+
+	btl, err := battle.NewBattle(config, factions...)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.lock.Lock()
+	s.seq++
+	id := strconv.FormatUint(s.seq, 10)
+	hb := newHostedBattle(s.seq, id, req.Scenario, config.Seed, btl, factions)
+	s.battles[id] = hb
+	s.lock.Unlock()
+
+	if req.Start {
+		if err := hb.start(s.config.Timeout); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+	}
+	w.Header().Set("Location", "/battles/"+id)
+	writeJSON(w, http.StatusCreated, hb.info())
+}
+
+// loadScenario loads the scenario of a create request
+func (s *Server) loadScenario(req CreateBattle) (*scenario.Scenario, error) {
+	switch {
+	case req.Scenario != "" && req.Definition != "":
+		return nil, errors.New("both a scenario and a definition given")
+	case req.Definition != "":
+		format := req.Format
+		if format == "" {
+			format = scenario.FormatYAML
+		}
+		return scenario.Parse("definition", format, []byte(req.Definition))
+	case req.Scenario == "":
+		return nil, errors.New("neither a scenario nor a definition given")
+	case s.config.ScenarioDir == "":
+		return nil, errors.New("no scenario directory configured")
+	case filepath.Base(req.Scenario) != req.Scenario ||
+		strings.HasPrefix(req.Scenario, "."):
+		return nil, errors.Errorf("invalid scenario name: '%s'", req.Scenario)
+	}
+	return scenario.Load(filepath.Join(s.config.ScenarioDir, req.Scenario))
+}
+
+// control starts, cancels, pauses or resumes the battle
+func (s *Server) control(hb *hostedBattle, action string) error {
+	switch action {
+	case "start":
+		return hb.start(s.config.Timeout)
+	case "cancel":
+		return hb.stop()
+	case "pause":
+		if err := hb.running(); err != nil {
+			return err
+		}
+		hb.battle.Pause()
+	case "resume":
+		if err := hb.running(); err != nil {
+			return err
+		}
+		hb.battle.Resume()
+	}
+	return nil
+}
+
+// remove cancels the battle if it's running and removes it
+func (s *Server) remove(hb *hostedBattle) {
+	s.lock.Lock()
+	if s.battles[hb.id] != hb {
+		// Removed concurrently
+		s.lock.Unlock()
+		return
+	}
+	delete(s.battles, hb.id)
+	s.lock.Unlock()
+
+	// Battles that aren't running can't be canceled
+	_ = hb.stop()
+	close(hb.removed)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// The status is already sent, encoding errors can't be reported anymore
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Error{Error: err.Error()})
+}
+
+// methodNotAllowed writes an error response listing the allowed methods
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(
+		w,
+		http.StatusMethodNotAllowed,
+		errors.New("method not allowed"),
+	)
+}
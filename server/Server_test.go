@@ -0,0 +1,415 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRoutes sends requests to every route of the API
+// concerning a battle that wasn't started
+func TestRoutes(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	info := createBattle(t, srv, CreateBattle{Definition: testDefinition(50)})
+	battle := "/battles/" + info.ID
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		status int
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/battles",
+			status: http.StatusOK,
+		},
+		{
+			name:   "create from scenario file",
+			method: http.MethodPost,
+			path:   "/battles",
+			body:   CreateBattle{Scenario: "default.yaml", Seed: 1},
+			status: http.StatusCreated,
+		},
+		{
+			name:   "create from scenario file in JSON",
+			method: http.MethodPost,
+			path:   "/battles",
+			body:   CreateBattle{Scenario: "dodgers.json", Virtual: true},
+			status: http.StatusCreated,
+		},
+		{
+			name:   "create from JSON definition without factions",
+			method: http.MethodPost,
+			path:   "/battles",
+			body: CreateBattle{
+				Definition: `{"factions": []}`,
+				Format:     "json",
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create from invalid request",
+			method: http.MethodPost,
+			path:   "/battles",
+			body:   "{",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create from scenario and definition",
+			method: http.MethodPost,
+			path:   "/battles",
+			body: CreateBattle{
+				Scenario:   "default.yaml",
+				Definition: testDefinition(50),
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create without scenario",
+			method: http.MethodPost,
+			path:   "/battles",
+			body:   CreateBattle{},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create from scenario outside the directory",
+			method: http.MethodPost,
+			path:   "/battles",
+			body:   CreateBattle{Scenario: "../scenarios/default.yaml"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create from unknown scenario",
+			method: http.MethodPost,
+			path:   "/battles",
+			body:   CreateBattle{Scenario: "unknown.yaml"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create from invalid definition",
+			method: http.MethodPost,
+			path:   "/battles",
+			body:   CreateBattle{Definition: "factions: {"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "list with unsupported method",
+			method: http.MethodPut,
+			path:   "/battles",
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "describe",
+			method: http.MethodGet,
+			path:   battle,
+			status: http.StatusOK,
+		},
+		{
+			name:   "describe unknown battle",
+			method: http.MethodGet,
+			path:   "/battles/unknown",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "describe with unsupported method",
+			method: http.MethodPost,
+			path:   battle,
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "pause battle that isn't running",
+			method: http.MethodPost,
+			path:   battle + "/pause",
+			status: http.StatusConflict,
+		},
+		{
+			name:   "resume battle that isn't running",
+			method: http.MethodPost,
+			path:   battle + "/resume",
+			status: http.StatusConflict,
+		},
+		{
+			name:   "cancel battle that isn't running",
+			method: http.MethodPost,
+			path:   battle + "/cancel",
+			status: http.StatusConflict,
+		},
+		{
+			name:   "start with unsupported method",
+			method: http.MethodGet,
+			path:   battle + "/start",
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "soldiers",
+			method: http.MethodGet,
+			path:   battle + "/soldiers",
+			status: http.StatusOK,
+		},
+		{
+			name:   "soldiers of faction",
+			method: http.MethodGet,
+			path:   battle + "/soldiers?faction=A",
+			status: http.StatusOK,
+		},
+		{
+			name:   "soldiers of unknown faction",
+			method: http.MethodGet,
+			path:   battle + "/soldiers?faction=C",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "statistics",
+			method: http.MethodGet,
+			path:   battle + "/statistics",
+			status: http.StatusOK,
+		},
+		{
+			name:   "statistics with unsupported method",
+			method: http.MethodPost,
+			path:   battle + "/statistics",
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "invalid event index",
+			method: http.MethodGet,
+			path:   battle + "/events?from=-1",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown battle route",
+			method: http.MethodGet,
+			path:   battle + "/unknown",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown route",
+			method: http.MethodGet,
+			path:   "/unknown",
+			status: http.StatusNotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var response json.RawMessage
+			status := request(t, srv, tc.method, tc.path, tc.body, &response)
+			if status != tc.status {
+				t.Fatalf(
+					"status %d, expected %d: %s",
+					status,
+					tc.status,
+					response,
+				)
+			}
+			if status >= 400 {
+				var e Error
+				err := json.Unmarshal(response, &e)
+				if err != nil || e.Error == "" {
+					t.Fatalf("missing error message: %s", response)
+				}
+			}
+		})
+	}
+}
+
+// TestControl starts, pauses, resumes, cancels and removes a battle
+func TestControl(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	// The soldiers are tough enough to outlast the test
+	info := createBattle(t, srv, CreateBattle{
+		Definition: testDefinition(10000),
+		Start:      true,
+	})
+	battle := "/battles/" + info.ID
+	if info.State != StateRunning {
+		t.Fatalf("state %s, expected %s", info.State, StateRunning)
+	}
+
+	for _, tc := range []struct {
+		action string
+		status int
+		state  State
+		paused bool
+	}{
+		{action: "start", status: http.StatusConflict},
+		{
+			action: "pause",
+			status: http.StatusOK,
+			state:  StateRunning,
+			paused: true,
+		},
+		{action: "resume", status: http.StatusOK, state: StateRunning},
+		{action: "cancel", status: http.StatusOK, state: StateFinished},
+		{action: "cancel", status: http.StatusConflict},
+		{action: "resume", status: http.StatusConflict},
+	} {
+		var info BattleInfo
+		path := battle + "/" + tc.action
+		status := request(t, srv, http.MethodPost, path, nil, &info)
+		if status != tc.status {
+			t.Fatalf("%s: status %d, expected %d", tc.action, status, tc.status)
+		}
+		if status != http.StatusOK {
+			continue
+		}
+		if info.State != tc.state || info.Paused != tc.paused {
+			t.Fatalf(
+				"%s: state %s (paused: %t), expected %s (paused: %t)",
+				tc.action,
+				info.State,
+				info.Paused,
+				tc.state,
+				tc.paused,
+			)
+		}
+	}
+
+	info = awaitFinished(t, srv, info.ID)
+	if info.Result == nil || info.Result.Outcome != "canceled" {
+		t.Fatalf("unexpected result: %+v", info.Result)
+	}
+
+	var battles []BattleInfo
+	request(t, srv, http.MethodGet, "/battles", nil, &battles)
+	if len(battles) != 1 || battles[0].ID != info.ID {
+		t.Fatalf("unexpected battles: %+v", battles)
+	}
+	status := request(t, srv, http.MethodDelete, battle, nil, nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("removing the battle: status %d", status)
+	}
+	status = request(t, srv, http.MethodGet, battle, nil, nil)
+	if status != http.StatusNotFound {
+		t.Fatalf("describing the removed battle: status %d", status)
+	}
+}
+
+// testTimeout limits the time tests wait for battles
+const testTimeout = 10 * time.Second
+
+// testDefinition returns an inline scenario of two evenly matched factions
+// of soldiers of the given health
+func testDefinition(health float64) string {
+	faction := func(name string) string {
+		return fmt.Sprintf(`
+  - name: %s
+    armySize: 5
+    soldierAttributes:
+      healthMin: [%[2]g, %[2]g]
+      healthMax: [%[2]g, %[2]g]
+      attackStrengthMin: [5, 5]
+      attackStrengthMax: [15, 15]
+      dodgeChanceMin: [.2, .2]
+      dodgeChanceMax: [.4, .4]
+      hitChanceMin: [.4, .4]
+      hitChanceMax: [.8, .8]
+      moraleIncrementFactor: [1, 1]
+      moraleDecrementFactor: [1, 1]`, name, health)
+	}
+	return "baseActionDelay: 5ms\nfactions:" + faction("A") + faction("B")
+}
+
+// testServer represents a server listening on a local test address
+type testServer struct {
+	*httptest.Server
+	server *Server
+}
+
+// newTestServer starts a new server serving the repository's scenarios
+func newTestServer() *testServer {
+	srv := New(Config{ScenarioDir: "../scenarios"})
+	return &testServer{Server: httptest.NewServer(srv), server: srv}
+}
+
+// Close cancels the battles to end their event streams
+// and shuts the server down
+func (ts *testServer) Close() {
+	ts.server.Close()
+	ts.Server.Close()
+}
+
+// request sends a request to the server decoding the JSON response
+// into the given value unless nil and returns the response status
+func request(
+	t *testing.T,
+	srv *testServer,
+	method string,
+	path string,
+	body interface{},
+	response interface{},
+) int {
+	t.Helper()
+	var reqBody []byte
+	switch body := body.(type) {
+	case nil:
+	case string:
+		reqBody = []byte(body)
+	default:
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, srv.URL+path, bytes.NewReader(reqBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, response); err != nil {
+			t.Fatalf("decoding %s: %s", data, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// createBattle creates a battle failing the test on error
+func createBattle(
+	t *testing.T,
+	srv *testServer,
+	req CreateBattle,
+) BattleInfo {
+	t.Helper()
+	var info BattleInfo
+	status := request(t, srv, http.MethodPost, "/battles", req, &info)
+	if status != http.StatusCreated {
+		t.Fatalf("creating the battle: status %d", status)
+	}
+	return info
+}
+
+// awaitFinished polls the battle until it's finished
+// failing the test if it isn't finished in time
+func awaitFinished(t *testing.T, srv *testServer, id string) BattleInfo {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for {
+		var info BattleInfo
+		request(t, srv, http.MethodGet, "/battles/"+id, nil, &info)
+		if info.State == StateFinished {
+			return info
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("battle %s didn't finish in time", id)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/romshark/go-battle-simulator/battle"
+	"github.com/romshark/go-battle-simulator/wire"
+)
+
+// eventBufferSize is the buffer size of the event stream subscriptions
+const eventBufferSize = 1024
+
+// streamEvents streams the battle log as server-sent events.
+// Every log entry is sent as a message event containing the entry
+// in the JSON wire format (see package wire) with its index in the log
+// as the event ID. The stream starts at the beginning of the log
+// or at the index given by the "from" query parameter
+// and resumes after the Last-Event-ID when the client reconnects.
+// Once the battle is finished an "end" event containing
+// the description of the battle (see BattleInfo) is sent.
+// Slow clients are disconnected when they fall too far behind
+// and are expected to reconnect
+func streamEvents(w http.ResponseWriter, r *http.Request, hb *hostedBattle) {
+	flusher, isFlusher := w.(http.Flusher)
+	if !isFlusher {
+		writeError(
+			w,
+			http.StatusInternalServerError,
+			errors.New("streaming unsupported"),
+		)
+		return
+	}
+
+	from := 0
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		id, err := strconv.Atoi(lastID)
+		if err != nil || id < 0 {
+			writeError(
+				w,
+				http.StatusBadRequest,
+				errors.Errorf("invalid Last-Event-ID: '%s'", lastID),
+			)
+			return
+		}
+		from = id + 1
+	} else if param := r.URL.Query().Get("from"); param != "" {
+		index, err := strconv.Atoi(param)
+		if err != nil || index < 0 {
+			writeError(
+				w,
+				http.StatusBadRequest,
+				errors.Errorf("invalid event index: '%s'", param),
+			)
+			return
+		}
+		from = index
+	}
+
+	stats := hb.battle.Statistics()
+	log, sub := stats.SubscribeWithLog(
+		eventBufferSize,
+		battle.OverflowDropNewest,
+	)
+	defer stats.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	buf := &bytes.Buffer{}
+	encoder := wire.NewJSONEncoder(buf)
+	send := func(index int, entry battle.LogEntry) error {
+		wireEntry, err := battle.WireEntry(entry)
+		if err != nil {
+			return err
+		}
+		buf.Reset()
+		if err := encoder.Encode(wireEntry); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(
+			w,
+			"id: %d\ndata: %s\n\n",
+			index,
+			bytes.TrimSuffix(buf.Bytes(), []byte("\n")),
+		)
+		return err
+	}
+
+	for index := from; index < len(log); index++ {
+		if err := send(index, log[index]); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	index := len(log)
+	for {
+		select {
+		case entry, open := <-sub.C():
+			if !open {
+				endStream(w, r, hb)
+				flusher.Flush()
+				return
+			}
+			if sub.Dropped() > 0 {
+				// Entries are missing, the client must reconnect
+				// and resume after the last event it received
+				return
+			}
+			if index >= from {
+				if err := send(index, entry); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+			index++
+		case <-r.Context().Done():
+			return
+		case <-hb.removed:
+			return
+		}
+	}
+}
+
+// endStream sends the end event once the battle is finished
+func endStream(w http.ResponseWriter, r *http.Request, hb *hostedBattle) {
+	// The log is closed slightly before the battle is finished
+	select {
+	case <-hb.done:
+	case <-r.Context().Done():
+		return
+	case <-hb.removed:
+		return
+	}
+	data, err := json.Marshal(hb.info())
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: end\ndata: %s\n\n", data)
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/romshark/go-battle-simulator/wire"
+)
+
+// sentEvent represents a server-sent event
+type sentEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// TestStreamEvents streams the log of a finished battle
+// starting at different indexes
+func TestStreamEvents(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	info := createBattle(t, srv, CreateBattle{
+		Definition: testDefinition(50),
+		Virtual:    true,
+		Start:      true,
+	})
+	info = awaitFinished(t, srv, info.ID)
+	if info.Events < 20 {
+		t.Fatalf("only %d events logged", info.Events)
+	}
+	events := "/battles/" + info.ID + "/events"
+
+	for _, tc := range []struct {
+		name        string
+		path        string
+		lastEventID string
+		from        int
+	}{
+		{name: "from the beginning", path: events},
+		{name: "from an index", path: events + "?from=10", from: 10},
+		{
+			name:        "after the last event ID",
+			path:        events + "?from=5",
+			lastEventID: "9",
+			from:        10,
+		},
+		{
+			name: "past the end",
+			path: events + "?from=" + strconv.Itoa(info.Events+5),
+			from: info.Events,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body := openStream(t, srv, tc.path, tc.lastEventID)
+			defer body.Close()
+			checkStream(t, readEvents(t, body), tc.from, info.Events)
+		})
+	}
+}
+
+// TestStreamLiveEvents streams the log of a battle started
+// after the stream was opened
+func TestStreamLiveEvents(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	info := createBattle(t, srv, CreateBattle{
+		Definition: testDefinition(50),
+		Virtual:    true,
+	})
+	battle := "/battles/" + info.ID
+
+	body := openStream(t, srv, battle+"/events", "")
+	defer body.Close()
+	status := request(t, srv, http.MethodPost, battle+"/start", nil, nil)
+	if status != http.StatusOK {
+		t.Fatalf("starting the battle: status %d", status)
+	}
+	events := readEvents(t, body)
+
+	info = awaitFinished(t, srv, info.ID)
+	checkStream(t, events, 0, info.Events)
+}
+
+// openStream opens the event stream of the given path
+// optionally resuming after the given last event ID
+func openStream(
+	t *testing.T,
+	srv *testServer,
+	path string,
+	lastEventID string,
+) io.ReadCloser {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	if tp := resp.Header.Get("Content-Type"); tp != "text/event-stream" {
+		resp.Body.Close()
+		t.Fatalf("content type %s", tp)
+	}
+	return resp.Body
+}
+
+// readEvents reads server-sent events until the stream ends
+func readEvents(t *testing.T, r io.Reader) []sentEvent {
+	t.Helper()
+	var events []sentEvent
+	var event sentEvent
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			events = append(events, event)
+			event = sentEvent{}
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		default:
+			t.Fatalf("unexpected line: %s", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+// checkStream fails the test unless the events contain the log entries
+// starting at the given index followed by the end event
+func checkStream(t *testing.T, events []sentEvent, from, logged int) {
+	t.Helper()
+	if len(events) != logged-from+1 {
+		t.Fatalf(
+			"%d events sent, expected %d",
+			len(events),
+			logged-from+1,
+		)
+	}
+	for i, event := range events[:len(events)-1] {
+		if id := strconv.Itoa(from + i); event.id != id {
+			t.Fatalf("event ID %s, expected %s", event.id, id)
+		}
+		decoder := wire.NewJSONDecoder(strings.NewReader(event.data), nil)
+		entry, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("decoding event %s: %s", event.id, err)
+		}
+		if _, isUnknown := entry.Event.(wire.Unknown); isUnknown {
+			t.Fatalf("unknown event type %s", entry.Event.EventType())
+		}
+	}
+
+	end := events[len(events)-1]
+	if end.event != "end" {
+		t.Fatalf("last event %+v, expected the end event", end)
+	}
+	var info BattleInfo
+	if err := json.Unmarshal([]byte(end.data), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.State != StateFinished || info.Result == nil {
+		t.Fatalf("unexpected end event: %s", end.data)
+	}
+}
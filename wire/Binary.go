@@ -0,0 +1,158 @@
+package wire
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MaxFrameSize limits the size of a binary frame read by decoders
+const MaxFrameSize = 16 << 20
+
+// BinaryEncoder writes log entries in the binary format.
+// Every entry is written as a frame prefixed by its length (uvarint)
+// consisting of the version (uvarint), the event type name (string),
+// the time in nanoseconds since the Unix epoch (varint) and the event.
+// Strings and slices are prefixed by their length (uvarint),
+// integers are varints, floats are 8 bytes little-endian,
+// booleans and nil-flags of pointers are single bytes
+// and struct fields are written in the order of their declaration
+type BinaryEncoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewBinaryEncoder creates a new binary encoder
+func NewBinaryEncoder(w io.Writer) *BinaryEncoder {
+	return &BinaryEncoder{w: w}
+}
+
+// Encode writes a log entry
+func (enc *BinaryEncoder) Encode(entry Entry) error {
+	if entry.Event == nil {
+		return errors.New("missing event")
+	}
+	typeName := entry.Event.EventType()
+	v := reflect.ValueOf(entry.Event)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if err := supported(v.Type()); err != nil {
+		return errors.Wrapf(err, "encoding %s", typeName)
+	}
+
+	payload := appendUvarint(nil, Version)
+	payload = appendValue(payload, reflect.ValueOf(typeName))
+	payload = appendVarint(payload, entry.Time.UnixNano())
+	if unknown, isUnknown := entry.Event.(Unknown); isUnknown {
+		payload = append(payload, unknown.Data...)
+	} else {
+		payload = appendValue(payload, v)
+	}
+
+	enc.buf = appendUvarint(enc.buf[:0], uint64(len(payload)))
+	enc.buf = append(enc.buf, payload...)
+	if _, err := enc.w.Write(enc.buf); err != nil {
+		return errors.Wrap(err, "writing entry")
+	}
+	return nil
+}
+
+// BinaryDecoder reads log entries in the binary format
+type BinaryDecoder struct {
+	r        *bufio.Reader
+	registry *Registry
+	frame    int
+}
+
+// NewBinaryDecoder creates a new binary decoder decoding the events
+// into the types of the given registry (DefaultRegistry if nil)
+func NewBinaryDecoder(r io.Reader, registry *Registry) *BinaryDecoder {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	return &BinaryDecoder{r: bufio.NewReader(r), registry: registry}
+}
+
+// Decode reads the next log entry and returns io.EOF
+// once all entries were read
+func (dec *BinaryDecoder) Decode() (Entry, error) {
+	size, err := readUvarint(dec.r)
+	if err != nil {
+		return Entry{}, err
+	}
+	if size > MaxFrameSize {
+		return Entry{}, errors.Errorf(
+			"frame %d: size exceeds the maximum: %d",
+			dec.frame,
+			size,
+		)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(dec.r, payload); err != nil {
+		return Entry{}, errors.Wrapf(err, "frame %d", dec.frame)
+	}
+	frame := dec.frame
+	dec.frame++
+
+	r := &binaryReader{data: payload}
+	version, err := r.uvarint()
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "frame %d", frame)
+	}
+	if version < 1 || version > Version {
+		return Entry{}, errors.Errorf(
+			"frame %d: unsupported version: %d",
+			frame,
+			version,
+		)
+	}
+	var typeName string
+	if err := r.readValue(reflect.ValueOf(&typeName).Elem()); err != nil {
+		return Entry{}, errors.Wrapf(err, "frame %d", frame)
+	}
+	nanos, err := r.varint()
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "frame %d", frame)
+	}
+	entry := Entry{Time: time.Unix(0, nanos).UTC()}
+
+	tp, registered := dec.registry.lookup(typeName)
+	if !registered {
+		entry.Event = Unknown{Type: typeName, Data: r.data}
+		return entry, nil
+	}
+	event := reflect.New(tp).Elem()
+	if err := r.readValue(event); err != nil {
+		return Entry{}, errors.Wrapf(err, "frame %d: decoding %s", frame, typeName)
+	}
+	entry.Event = event.Interface().(Event)
+	return entry, nil
+}
+
+// readUvarint reads the uvarint frame size. Returns io.EOF only
+// if the stream ended before the frame
+func readUvarint(r io.ByteReader) (uint64, error) {
+	var x uint64
+	var shift uint
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		if i == 9 && b > 1 || i > 9 {
+			return 0, errors.New("frame size overflows")
+		}
+		if b < 0x80 {
+			return x | uint64(b)<<shift, nil
+		}
+		x |= uint64(b&0x7f) << shift
+		shift += 7
+	}
+}
@@ -0,0 +1,58 @@
+// Package wire defines the stable, versioned wire representations
+// of the battle log. It doesn't depend on the simulator and allows
+// downstream tools to read battle logs in either the JSON lines
+// or the compact binary format
+package wire
+
+import "time"
+
+// Version is the version of the wire format.
+// Decoders reject entries of newer versions
+const Version = 1
+
+// Event represents a wire event
+type Event interface {
+	// EventType returns the name the event type is registered by
+	EventType() string
+}
+
+// Entry represents a battle log entry
+type Entry struct {
+	Time  time.Time
+	Event Event
+}
+
+// SoldierID represents a soldier's unique identifier
+type SoldierID struct {
+	Faction string
+	Name    string
+
+	// Unit is the unit type of the soldier (empty if unnamed)
+	Unit string
+}
+
+// Soldier represents a soldier involved in an event
+// and its status at the time of the event
+type Soldier struct {
+	ID      SoldierID
+	Health  float64
+	Morale  float64
+	Stamina float64
+	Routed  bool
+}
+
+// Position represents a position on a spatial battlefield
+type Position struct {
+	X float64
+	Y float64
+}
+
+// StatusEffect represents a status effect inflicted by a weapon
+type StatusEffect struct {
+	// Kind is either "bleeding", "stun" or "fear"
+	Kind      string
+	Chance    float64
+	Interval  time.Duration
+	Ticks     uint
+	Magnitude float64
+}
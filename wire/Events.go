@@ -0,0 +1,194 @@
+package wire
+
+// Dodge represents a dodged attack
+type Dodge struct {
+	Attacker        Soldier
+	Defender        Soldier
+	MoralePenalty   float64
+	AttackerStamina float64
+	DefenderStamina float64
+}
+
+// EventType implements the Event interface
+func (Dodge) EventType() string { return "dodge" }
+
+// Block represents an attack blocked by the shield of the defender
+type Block struct {
+	Attacker        Soldier
+	Defender        Soldier
+	MoralePenalty   float64
+	AttackerStamina float64
+}
+
+// EventType implements the Event interface
+func (Block) EventType() string { return "block" }
+
+// Miss represents a missed attack
+type Miss struct {
+	Attacker        Soldier
+	Attacked        Soldier
+	MoralePenalty   float64
+	AttackerStamina float64
+}
+
+// EventType implements the Event interface
+func (Miss) EventType() string { return "miss" }
+
+// Hit represents a successful attack
+type Hit struct {
+	Attacker        Soldier
+	Attacked        Soldier
+	DamageDealt     float64
+	MoraleBonus     float64
+	AttackerStamina float64
+	Critical        bool
+}
+
+// EventType implements the Event interface
+func (Hit) EventType() string { return "hit" }
+
+// Kill represents a deadly attack
+type Kill struct {
+	Attacker        Soldier
+	Killed          Soldier
+	DamageDealt     float64
+	MoraleBonus     float64
+	AttackerStamina float64
+	Critical        bool
+}
+
+// EventType implements the Event interface
+func (Kill) EventType() string { return "kill" }
+
+// Move represents a soldier moving on a spatial battlefield
+type Move struct {
+	Soldier Soldier
+
+	// Target is the soldier moved toward, it's nil for retreats
+	Target *Soldier
+	From   Position
+	To     Position
+}
+
+// EventType implements the Event interface
+func (Move) EventType() string { return "move" }
+
+// Rout represents a soldier whose morale broke
+type Rout struct {
+	Soldier Soldier
+	Morale  float64
+}
+
+// EventType implements the Event interface
+func (Rout) EventType() string { return "rout" }
+
+// Rally represents a routed soldier fighting again
+type Rally struct {
+	Soldier Soldier
+	Morale  float64
+}
+
+// EventType implements the Event interface
+func (Rally) EventType() string { return "rally" }
+
+// Flee represents a routed soldier leaving the battlefield
+type Flee struct {
+	Soldier Soldier
+}
+
+// EventType implements the Event interface
+func (Flee) EventType() string { return "flee" }
+
+// CommanderKilled represents the death of a faction's commander
+type CommanderKilled struct {
+	Attacker      Soldier
+	Commander     Soldier
+	MoralePenalty float64
+}
+
+// EventType implements the Event interface
+func (CommanderKilled) EventType() string { return "commanderKilled" }
+
+// ReinforcementsArrived represents a reinforcement wave
+// joining the battle
+type ReinforcementsArrived struct {
+	Faction  string
+	Wave     int
+	Soldiers []Soldier
+}
+
+// EventType implements the Event interface
+func (ReinforcementsArrived) EventType() string {
+	return "reinforcementsArrived"
+}
+
+// AllianceBroken represents a faction leaving its team
+type AllianceBroken struct {
+	Faction string
+	Team    string
+}
+
+// EventType implements the Event interface
+func (AllianceBroken) EventType() string { return "allianceBroken" }
+
+// Heal represents a medic healing a wounded ally
+type Heal struct {
+	Healer  Soldier
+	Healed  Soldier
+	Healing float64
+}
+
+// EventType implements the Event interface
+func (Heal) EventType() string { return "heal" }
+
+// EffectApplied represents a status effect inflicted on a soldier
+type EffectApplied struct {
+	Soldier Soldier
+	Source  Soldier
+	Effect  StatusEffect
+}
+
+// EventType implements the Event interface
+func (EffectApplied) EventType() string { return "effectApplied" }
+
+// EffectTick represents a tick of an active status effect
+type EffectTick struct {
+	Soldier   Soldier
+	Effect    string
+	Magnitude float64
+	TicksLeft uint
+	Killed    bool
+}
+
+// EventType implements the Event interface
+func (EffectTick) EventType() string { return "effectTick" }
+
+// EffectExpired represents an expired status effect
+type EffectExpired struct {
+	Soldier Soldier
+	Effect  string
+}
+
+// EventType implements the Event interface
+func (EffectExpired) EventType() string { return "effectExpired" }
+
+// EffectKill represents a soldier killed by a status effect
+type EffectKill struct {
+	Source      Soldier
+	Killed      Soldier
+	Effect      string
+	DamageDealt float64
+}
+
+// EventType implements the Event interface
+func (EffectKill) EventType() string { return "effectKill" }
+
+// LevelUp represents a soldier reaching a new veterancy level
+type LevelUp struct {
+	Soldier    Soldier
+	Level      uint
+	Experience float64
+}
+
+// EventType implements the Event interface
+func (LevelUp) EventType() string { return "levelUp" }
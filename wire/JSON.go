@@ -0,0 +1,124 @@
+package wire
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// jsonEntry represents a line of the JSON lines format
+type jsonEntry struct {
+	Version int             `json:"version"`
+	Time    time.Time       `json:"time"`
+	Type    string          `json:"type"`
+	Event   json.RawMessage `json:"event"`
+}
+
+// JSONEncoder writes log entries in the JSON lines format,
+// one JSON object per line
+type JSONEncoder struct {
+	w io.Writer
+}
+
+// NewJSONEncoder creates a new JSON lines encoder
+func NewJSONEncoder(w io.Writer) *JSONEncoder {
+	return &JSONEncoder{w: w}
+}
+
+// Encode writes a log entry
+func (enc *JSONEncoder) Encode(entry Entry) error {
+	if entry.Event == nil {
+		return errors.New("missing event")
+	}
+	line := jsonEntry{
+		Version: Version,
+		Time:    entry.Time,
+		Type:    entry.Event.EventType(),
+	}
+	if unknown, isUnknown := entry.Event.(Unknown); isUnknown {
+		line.Event = unknown.Data
+	} else {
+		data, err := json.Marshal(entry.Event)
+		if err != nil {
+			return errors.Wrapf(err, "encoding %s", line.Type)
+		}
+		line.Event = data
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return errors.Wrap(err, "encoding entry")
+	}
+	if _, err := enc.w.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "writing entry")
+	}
+	return nil
+}
+
+// JSONDecoder reads log entries in the JSON lines format
+type JSONDecoder struct {
+	r        *bufio.Reader
+	registry *Registry
+	line     int
+}
+
+// NewJSONDecoder creates a new JSON lines decoder decoding the events
+// into the types of the given registry (DefaultRegistry if nil)
+func NewJSONDecoder(r io.Reader, registry *Registry) *JSONDecoder {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	return &JSONDecoder{r: bufio.NewReader(r), registry: registry}
+}
+
+// Decode reads the next log entry and returns io.EOF
+// once all entries were read
+func (dec *JSONDecoder) Decode() (Entry, error) {
+	var data []byte
+	for len(data) < 1 {
+		line, err := dec.r.ReadBytes('\n')
+		if err == io.EOF && len(line) > 0 {
+			err = nil
+		}
+		if err != nil {
+			return Entry{}, err
+		}
+		dec.line++
+		data = bytes.TrimSpace(line)
+	}
+
+	var line jsonEntry
+	if err := json.Unmarshal(data, &line); err != nil {
+		return Entry{}, errors.Wrapf(err, "line %d", dec.line)
+	}
+	if line.Version < 1 || line.Version > Version {
+		return Entry{}, errors.Errorf(
+			"line %d: unsupported version: %d",
+			dec.line,
+			line.Version,
+		)
+	}
+
+	tp, registered := dec.registry.lookup(line.Type)
+	if !registered {
+		return Entry{
+			Time:  line.Time,
+			Event: Unknown{Type: line.Type, Data: line.Event},
+		}, nil
+	}
+	event := reflect.New(tp)
+	if err := json.Unmarshal(line.Event, event.Interface()); err != nil {
+		return Entry{}, errors.Wrapf(
+			err,
+			"line %d: decoding %s",
+			dec.line,
+			line.Type,
+		)
+	}
+	return Entry{Time: line.Time, Event: event.Elem().Interface().(Event)}, nil
+}
@@ -0,0 +1,101 @@
+package wire
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Registry maps the names of the event types to the types
+// the decoders decode the events into
+type Registry struct {
+	lock  *sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// NewRegistry creates a new registry of all built-in event types
+func NewRegistry() *Registry {
+	r := &Registry{
+		lock:  &sync.RWMutex{},
+		types: make(map[string]reflect.Type),
+	}
+	for _, event := range []Event{
+		Dodge{},
+		Block{},
+		Miss{},
+		Hit{},
+		Kill{},
+		Move{},
+		Rout{},
+		Rally{},
+		Flee{},
+		CommanderKilled{},
+		ReinforcementsArrived{},
+		AllianceBroken{},
+		Heal{},
+		EffectApplied{},
+		EffectTick{},
+		EffectExpired{},
+		EffectKill{},
+		LevelUp{},
+	} {
+		if err := r.Register(event); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// DefaultRegistry is the registry used by decoders
+// created without a registry
+var DefaultRegistry = NewRegistry()
+
+// Register registers the type of the given event by its type name.
+// Events must be structs whose fields are supported by the binary format
+func (r *Registry) Register(event Event) error {
+	tp := reflect.TypeOf(event)
+	if tp == nil || tp.Kind() != reflect.Struct {
+		return errors.Errorf("invalid event type: %T (expected a struct)", event)
+	}
+	name := event.EventType()
+	if name == "" {
+		return errors.Errorf("event type %s has no name", tp)
+	}
+	if err := supported(tp); err != nil {
+		return errors.Wrapf(err, "event type %s", tp)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if registered, duplicate := r.types[name]; duplicate {
+		return errors.Errorf(
+			"event type name '%s' already registered by %s",
+			name,
+			registered,
+		)
+	}
+	r.types[name] = tp
+	return nil
+}
+
+// lookup returns the event type registered by the given name
+func (r *Registry) lookup(name string) (reflect.Type, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	tp, registered := r.types[name]
+	return tp, registered
+}
+
+// Unknown represents an event of a type that's not registered.
+// Decoders return unknown events instead of failing
+// to allow reading logs of newer simulator versions
+type Unknown struct {
+	Type string
+
+	// Data is the encoded event in the format it was read from
+	Data []byte
+}
+
+// EventType implements the Event interface
+func (ev Unknown) EventType() string { return ev.Type }
@@ -0,0 +1,133 @@
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// customEvent is an event type that's not built-in
+type customEvent struct {
+	Name  string
+	Count int
+}
+
+func (customEvent) EventType() string { return "custom" }
+
+// invalidEvent is an event type that's not a struct
+type invalidEvent string
+
+func (invalidEvent) EventType() string { return "invalid" }
+
+// unnamedEvent is an event type without a name
+type unnamedEvent struct{}
+
+func (unnamedEvent) EventType() string { return "" }
+
+// unsupportedEvent is an event type the binary format doesn't support
+type unsupportedEvent struct {
+	Values map[string]int
+}
+
+func (unsupportedEvent) EventType() string { return "unsupported" }
+
+// TestRegistryCoverage makes sure every built-in event type
+// is covered by the round-trip tests
+func TestRegistryCoverage(t *testing.T) {
+	covered := map[string]bool{}
+	for _, entry := range testEntries() {
+		covered[entry.Event.EventType()] = true
+	}
+	for name := range DefaultRegistry.types {
+		if !covered[name] {
+			t.Errorf("event type %s isn't covered", name)
+		}
+	}
+}
+
+// TestRegister registers valid and invalid event types
+func TestRegister(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		event Event
+		valid bool
+	}{
+		{name: "custom", event: customEvent{}, valid: true},
+		{name: "duplicate", event: Hit{}},
+		{name: "nil", event: nil},
+		{name: "not a struct", event: invalidEvent("")},
+		{name: "unnamed", event: unnamedEvent{}},
+		{name: "unsupported field", event: unsupportedEvent{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewRegistry().Register(tc.event)
+			switch {
+			case tc.valid && err != nil:
+				t.Fatalf("unexpected error: %s", err)
+			case !tc.valid && err == nil:
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+// TestUnknownEvents decodes events of unregistered types as unknown events
+// and makes sure they're encoded unchanged
+func TestUnknownEvents(t *testing.T) {
+	for _, format := range testFormats {
+		t.Run(format.name, func(t *testing.T) {
+			registry := NewRegistry()
+			if err := registry.Register(customEvent{}); err != nil {
+				t.Fatal(err)
+			}
+			expected := testEntries()
+			expected = append(expected, Entry{
+				Time:  expected[len(expected)-1].Time,
+				Event: customEvent{Name: "custom", Count: 3},
+			})
+
+			encoded := &bytes.Buffer{}
+			encoder := format.newEncoder(encoded)
+			for _, entry := range expected {
+				if err := encoder.Encode(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Decode all events as unknown events and encode them again
+			reencoded := &bytes.Buffer{}
+			decoder := format.newDecoder(encoded, emptyRegistry())
+			encoder = format.newEncoder(reencoded)
+			for _, entry := range expected {
+				unknown, err := decoder.Decode()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, isUnknown := unknown.Event.(Unknown); !isUnknown {
+					t.Fatalf("unexpected event: %#v", unknown.Event)
+				}
+				if tp := unknown.Event.EventType(); tp != entry.Event.EventType() {
+					t.Fatalf("event type %s, expected %s", tp, entry.Event.EventType())
+				}
+				if err := encoder.Encode(unknown); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			decoder = format.newDecoder(reencoded, registry)
+			for _, entry := range expected {
+				decoded, err := decoder.Decode()
+				if err != nil {
+					t.Fatal(err)
+				}
+				compareEntries(t, decoded, entry)
+			}
+		})
+	}
+}
+
+// emptyRegistry returns a registry without any event types
+func emptyRegistry() *Registry {
+	return &Registry{lock: &sync.RWMutex{}, types: map[string]reflect.Type{}}
+}
@@ -0,0 +1,208 @@
+package wire
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// supported returns an error if the given type
+// can't be encoded in the binary format
+func supported(tp reflect.Type) error {
+	switch tp.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Float32, reflect.Float64, reflect.String:
+		return nil
+	case reflect.Slice, reflect.Ptr:
+		return supported(tp.Elem())
+	case reflect.Struct:
+		for i := 0; i < tp.NumField(); i++ {
+			if tp.Field(i).PkgPath != "" {
+				// Unexported fields are ignored
+				continue
+			}
+			if err := supported(tp.Field(i).Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.Errorf("unsupported type: %s", tp)
+}
+
+// appendUvarint appends an unsigned varint
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutUvarint(tmp[:], x)]...)
+}
+
+// appendVarint appends a signed varint
+func appendVarint(buf []byte, x int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutVarint(tmp[:], x)]...)
+}
+
+// appendValue appends the binary representation of a value.
+// Struct fields are encoded in the order of their declaration
+func appendValue(buf []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendVarint(buf, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return appendUvarint(buf, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		var tmp [8]byte
+		binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(v.Float()))
+		return append(buf, tmp[:]...)
+	case reflect.String:
+		buf = appendUvarint(buf, uint64(v.Len()))
+		return append(buf, v.String()...)
+	case reflect.Slice:
+		buf = appendUvarint(buf, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			buf = appendValue(buf, v.Index(i))
+		}
+		return buf
+	case reflect.Ptr:
+		if v.IsNil() {
+			return append(buf, 0)
+		}
+		return appendValue(append(buf, 1), v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				buf = appendValue(buf, v.Field(i))
+			}
+		}
+		return buf
+	}
+	// Verified during the registration
+	panic(errors.Errorf("unsupported type: %s", v.Type()))
+}
+
+// binaryReader reads binary values from a buffer
+type binaryReader struct {
+	data []byte
+}
+
+// errTruncated is returned when the data ends unexpectedly
+var errTruncated = errors.New("truncated data")
+
+func (r *binaryReader) uvarint() (uint64, error) {
+	x, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.data = r.data[n:]
+	return x, nil
+}
+
+func (r *binaryReader) varint() (int64, error) {
+	x, n := binary.Varint(r.data)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.data = r.data[n:]
+	return x, nil
+}
+
+func (r *binaryReader) bytes(n uint64) ([]byte, error) {
+	if uint64(len(r.data)) < n {
+		return nil, errTruncated
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+// readValue reads the binary representation of a value into v
+func (r *binaryReader) readValue(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := r.bytes(1)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b[0] != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := r.varint()
+		if err != nil {
+			return err
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		x, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		v.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		b, err := r.bytes(8)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	case reflect.String:
+		n, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		b, err := r.bytes(n)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(b))
+	case reflect.Slice:
+		n, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		if n > uint64(len(r.data)) {
+			// Every element takes at least one byte
+			return errTruncated
+		}
+		slice := reflect.MakeSlice(v.Type(), int(n), int(n))
+		for i := 0; i < int(n); i++ {
+			if err := r.readValue(slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Ptr:
+		b, err := r.bytes(1)
+		if err != nil {
+			return err
+		}
+		if b[0] == 0 {
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := r.readValue(elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := r.readValue(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Errorf("unsupported type: %s", v.Type())
+	}
+	return nil
+}
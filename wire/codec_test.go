@@ -0,0 +1,227 @@
+package wire
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestRoundTrip encodes and decodes an entry of every event type
+// in every wire format
+func TestRoundTrip(t *testing.T) {
+	for _, format := range testFormats {
+		t.Run(format.name, func(t *testing.T) {
+			entries := testEntries()
+			buf := &bytes.Buffer{}
+			encoder := format.newEncoder(buf)
+			for _, entry := range entries {
+				if err := encoder.Encode(entry); err != nil {
+					t.Fatalf("encoding %s: %s", entry.Event.EventType(), err)
+				}
+			}
+
+			decoder := format.newDecoder(buf, nil)
+			for _, expected := range entries {
+				entry, err := decoder.Decode()
+				if err != nil {
+					t.Fatalf("decoding %s: %s", expected.Event.EventType(), err)
+				}
+				compareEntries(t, entry, expected)
+			}
+			if _, err := decoder.Decode(); err != io.EOF {
+				t.Fatalf("expected io.EOF after the last entry, got: %v", err)
+			}
+		})
+	}
+}
+
+// TestMissingEvent encodes an entry without an event in every wire format
+func TestMissingEvent(t *testing.T) {
+	for _, format := range testFormats {
+		t.Run(format.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := format.newEncoder(buf).Encode(Entry{}); err == nil {
+				t.Fatal("expected an error")
+			}
+			if buf.Len() > 0 {
+				t.Fatalf("%d bytes written", buf.Len())
+			}
+		})
+	}
+}
+
+// compareEntries fails the test if the entries differ
+func compareEntries(t *testing.T, entry, expected Entry) {
+	t.Helper()
+	if !entry.Time.Equal(expected.Time) {
+		t.Errorf(
+			"%s: time %s, expected %s",
+			expected.Event.EventType(),
+			entry.Time,
+			expected.Time,
+		)
+	}
+	if !reflect.DeepEqual(entry.Event, expected.Event) {
+		t.Errorf(
+			"%s: decoded %#v\nexpected %#v",
+			expected.Event.EventType(),
+			entry.Event,
+			expected.Event,
+		)
+	}
+}
+
+// encoder represents the encoders of the wire formats
+type encoder interface {
+	Encode(entry Entry) error
+}
+
+// decoder represents the decoders of the wire formats
+type decoder interface {
+	Decode() (Entry, error)
+}
+
+// testFormats lists the wire formats
+var testFormats = []struct {
+	name       string
+	newEncoder func(w io.Writer) encoder
+	newDecoder func(r io.Reader, registry *Registry) decoder
+}{
+	{
+		name:       "JSON",
+		newEncoder: func(w io.Writer) encoder { return NewJSONEncoder(w) },
+		newDecoder: func(r io.Reader, registry *Registry) decoder {
+			return NewJSONDecoder(r, registry)
+		},
+	},
+	{
+		name:       "binary",
+		newEncoder: func(w io.Writer) encoder { return NewBinaryEncoder(w) },
+		newDecoder: func(r io.Reader, registry *Registry) decoder {
+			return NewBinaryDecoder(r, registry)
+		},
+	},
+}
+
+// testSoldier returns a soldier of the given faction and name
+func testSoldier(faction, name string) Soldier {
+	return Soldier{
+		ID:      SoldierID{Faction: faction, Name: name, Unit: "infantry"},
+		Health:  42.5,
+		Morale:  .75,
+		Stamina: .5,
+		Routed:  name == "Routed",
+	}
+}
+
+// testEntries returns an entry of every built-in event type
+func testEntries() []Entry {
+	attacker := testSoldier("A", "Attacker")
+	defender := testSoldier("B", "Defender")
+	routed := testSoldier("B", "Routed")
+	events := []Event{
+		Dodge{
+			Attacker:        attacker,
+			Defender:        defender,
+			MoralePenalty:   -.05,
+			AttackerStamina: .4,
+			DefenderStamina: .3,
+		},
+		Block{
+			Attacker:        attacker,
+			Defender:        defender,
+			MoralePenalty:   -.05,
+			AttackerStamina: .4,
+		},
+		Miss{
+			Attacker:        attacker,
+			Attacked:        defender,
+			MoralePenalty:   -.1,
+			AttackerStamina: .4,
+		},
+		Hit{
+			Attacker:        attacker,
+			Attacked:        defender,
+			DamageDealt:     12.25,
+			MoraleBonus:     .05,
+			AttackerStamina: .4,
+			Critical:        true,
+		},
+		Kill{
+			Attacker:        attacker,
+			Killed:          defender,
+			DamageDealt:     7,
+			MoraleBonus:     .5,
+			AttackerStamina: .4,
+		},
+		Move{
+			Soldier: attacker,
+			Target:  &defender,
+			From:    Position{X: 1, Y: 2},
+			To:      Position{X: 2.5, Y: 3},
+		},
+		Move{
+			Soldier: routed,
+			From:    Position{X: 1, Y: 2},
+			To:      Position{X: 0, Y: 2},
+		},
+		Rout{Soldier: routed, Morale: .1},
+		Rally{Soldier: routed, Morale: .3},
+		Flee{Soldier: routed},
+		CommanderKilled{
+			Attacker:      attacker,
+			Commander:     defender,
+			MoralePenalty: .3,
+		},
+		ReinforcementsArrived{
+			Faction:  "B",
+			Wave:     1,
+			Soldiers: []Soldier{defender, routed},
+		},
+		AllianceBroken{Faction: "B", Team: "North"},
+		Heal{Healer: attacker, Healed: routed, Healing: 8},
+		EffectApplied{
+			Soldier: defender,
+			Source:  attacker,
+			Effect: StatusEffect{
+				Kind:      "bleeding",
+				Chance:    .3,
+				Interval:  500 * time.Millisecond,
+				Ticks:     3,
+				Magnitude: 2,
+			},
+		},
+		EffectTick{
+			Soldier:   defender,
+			Effect:    "bleeding",
+			Magnitude: 2,
+			TicksLeft: 2,
+		},
+		EffectTick{
+			Soldier:   defender,
+			Effect:    "bleeding",
+			Magnitude: 1.5,
+			Killed:    true,
+		},
+		EffectExpired{Soldier: defender, Effect: "fear"},
+		EffectKill{
+			Source:      attacker,
+			Killed:      defender,
+			Effect:      "bleeding",
+			DamageDealt: 1.5,
+		},
+		LevelUp{Soldier: attacker, Level: 2, Experience: 45.5},
+	}
+
+	start := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	entries := make([]Entry, len(events))
+	for i, event := range events {
+		entries[i] = Entry{
+			Time:  start.Add(time.Duration(i) * time.Millisecond),
+			Event: event,
+		}
+	}
+	return entries
+}